@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// runConfig valida la configuración (check). Con --db también comprueba la conexión a la
+// base de datos y que el esquema esté al día, con los mismos códigos de salida que migrate status.
+func runConfig(ctx context.Context, cmd *command, args []string) error {
+	action, args, err := subcommand(cmd, args, "check")
+	if err != nil {
+		return err
+	}
+	if action != "check" {
+		return usagef("acción desconocida %q", action)
+	}
+	fs := newFlags(cmd, "config check")
+	checkDB := fs.Bool("db", false, "comprobar también la conexión a la base de datos y las migraciones")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	printConfig(cfg)
+
+	if *checkDB {
+		db, err := config.ConnectDB(cfg)
+		if err != nil {
+			return withCode(exitDatabase, err)
+		}
+		defer config.CloseDB(db)
+		if err := checkSchema(ctx, db); err != nil {
+			return err
+		}
+	}
+	fmt.Println("Configuración válida")
+	return nil
+}
+
+// printConfig muestra un resumen de la configuración sin secretos
+func printConfig(cfg *config.Config) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "GIN_MODE\t%s\n", cfg.GinMode)
+	fmt.Fprintf(tw, "PORT\t%s\n", cfg.Port)
+	fmt.Fprintf(tw, "DB_DRIVER\t%s\n", cfg.DBDriver)
+	fmt.Fprintf(tw, "URL_DATABASE\t%s\n", redactDSN(cfg.DBDriver, cfg.URLDatabase))
+	fmt.Fprintf(tw, "JWT_SECRET\t(%d caracteres)\n", len(cfg.JWTSecret))
+	fmt.Fprintf(tw, "JWT_EXPIRE_IN\t%s\n", cfg.JWTExpireIn)
+	fmt.Fprintf(tw, "TRASH_RETENTION\t%s\n", cfg.TrashRetention)
+	fmt.Fprintf(tw, "REQUIRE_IF_MATCH\t%t\n", cfg.RequireIfMatch)
+	fmt.Fprintf(tw, "EVENTS_BACKEND\t%s\n", cfg.EventsBackend)
+	fmt.Fprintf(tw, "GRPC_PORT\t%s\n", valueOrDash(cfg.GRPCPort))
+	fmt.Fprintf(tw, "GRPC_GATEWAY_PORT\t%s\n", valueOrDash(cfg.GRPCGatewayPort))
+	tw.Flush()
+}
+
+// redactDSN oculta la contraseña de la cadena de conexión
+func redactDSN(driver, dsn string) string {
+	switch driver {
+	case config.DriverSQLite:
+		return dsn
+	case config.DriverMySQL:
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return "(configurada)"
+		}
+		if cfg.Passwd != "" {
+			cfg.Passwd = "xxxxx"
+		}
+		return cfg.FormatDSN()
+	default:
+		u, err := url.Parse(dsn)
+		if err != nil || u.Scheme == "" {
+			return "(configurada)"
+		}
+		return u.Redacted()
+	}
+}
+
+// valueOrDash devuelve "-" para los valores vacíos
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
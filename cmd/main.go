@@ -18,82 +18,273 @@
 // @name Authorization
 // @description Tipo de token JWT con el prefijo 'Bearer '
 
+// Command main es el servidor de la API y sus tareas administrativas.
+//
+// Uso:
+//
+//	main [comando] [argumentos]
+//
+// Sin comando inicia el servidor (equivale a "serve").
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/alexroel/gin-tasks-api/internal/app"
 	"github.com/alexroel/gin-tasks-api/internal/config"
-	"github.com/alexroel/gin-tasks-api/internal/handler"
-	"github.com/alexroel/gin-tasks-api/internal/middleware"
-	"github.com/alexroel/gin-tasks-api/internal/repository"
-	"github.com/alexroel/gin-tasks-api/internal/service"
-	"github.com/gin-gonic/gin"
-
-	_ "github.com/alexroel/gin-tasks-api/docs"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/alexroel/gin-tasks-api/internal/migrations"
+	"gorm.io/gorm"
 )
 
+// Códigos de salida, pensados para scripts de despliegue
+const (
+	exitOK       = 0 // el comando terminó correctamente
+	exitError    = 1 // error durante la ejecución
+	exitUsage    = 2 // comando u opciones inválidos
+	exitConfig   = 3 // la configuración es inválida
+	exitDatabase = 4 // no se pudo conectar a la base de datos
+	exitPending  = 5 // el esquema de la base de datos tiene migraciones pendientes
+	exitNewer    = 6 // el esquema de la base de datos es de una versión más nueva de la aplicación
+)
+
+// codedError es un error que termina el proceso con un código de salida concreto
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withCode asocia un código de salida a un error; si err es nil devuelve nil
+func withCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// usageError es un error en los argumentos de un comando
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef crea un usageError con formato
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// command es un subcomando del binario
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, cmd *command, args []string) error
+}
+
+// commands son los subcomandos disponibles, en el orden en que se muestran en la ayuda
+var commands = []command{
+	{"serve", "[--migrate=false]", "Iniciar la API REST, el servidor gRPC y las tareas periódicas (por defecto)", runServe},
+	{"migrate", "up|down|status", "Aplicar o consultar las migraciones de la base de datos", runMigrate},
+	{"seed", "[--users N] [--tasks N] [--password CONTRASEÑA] [--seed N] [--force]", "Cargar usuarios y tareas de prueba para desarrollo", runSeed},
+	{"user", "create|reset-password [opciones]", "Crear usuarios o restablecer su contraseña", runUser},
+	{"purge-deleted", "[--older-than DURACIÓN]", "Eliminar definitivamente las tareas de la papelera", runPurgeDeleted},
+	{"config", "check [--db]", "Validar la configuración y, opcionalmente, la conexión a la base de datos", runConfig},
+}
+
 func main() {
-	// Cargar configuración
-	if err := config.LoadConfig(); err != nil {
-		log.Fatal("Error en configuración: ", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// run ejecuta el comando indicado en los argumentos y devuelve el código de salida
+func run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		printUsage(os.Stdout)
+		return exitOK
 	}
 
-	// Conectar a la base de datos
-	if err := config.ConnectDB(); err != nil {
-		log.Fatal(err)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "comando desconocido %q\n\n", args[0])
+		printUsage(os.Stderr)
+		return exitUsage
 	}
-	defer config.CloseDB()
 
-	// Ejecutar migraciones
-	if err := config.RunMigrations(); err != nil {
-		log.Fatal(err)
+	err := cmd.run(ctx, cmd, args[1:])
+	var usageErr *usageError
+	var codedErr *codedError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(os.Stderr, "%s: %v\nUso: %s %s\n", cmd.name, err, cmd.name, cmd.args)
+		return exitUsage
+	case errors.As(err, &codedErr):
+		log.Printf("Error en %s: %v", cmd.name, err)
+		return codedErr.code
+	default:
+		log.Printf("Error en %s: %v", cmd.name, err)
+		return exitError
 	}
+}
+
+// printUsage muestra la ayuda general
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: main [comando] [argumentos]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Comandos:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Sin comando se ejecuta serve. Use '<comando> -h' para ver sus opciones.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Códigos de salida: 0 correcto, 1 error, 2 uso incorrecto, 3 configuración inválida,")
+	fmt.Fprintln(w, "4 base de datos no disponible, 5 migraciones pendientes, 6 esquema de una versión más nueva.")
+}
+
+// newFlags crea el conjunto de opciones de un comando
+func newFlags(cmd *command, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stdout, "Uso: %s %s\n\n%s\n\n", name, cmd.args, cmd.summary)
+		fs.SetOutput(os.Stdout)
+		fs.PrintDefaults()
+		fs.SetOutput(io.Discard)
+	}
+	return fs
+}
 
-	// Registrar repositorios
-	userRepo := repository.NewUserRepository()
-	taskRepo := repository.NewTaskRepository()
+// parseFlags analiza las opciones de un comando, que no admite argumentos posicionales
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usagef("argumento inesperado %q", fs.Arg(0))
+	}
+	return nil
+}
 
-	// Registrar servicios
-	authService := service.NewAuthService(userRepo)
-	taskService := service.NewTaskService(taskRepo)
+// subcommand separa la acción de un comando con acciones (por ejemplo "migrate up") de sus opciones.
+// expected describe las acciones válidas para el mensaje de error.
+func subcommand(cmd *command, args []string, expected string) (string, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if err := parseFlags(newFlags(cmd, cmd.name), args); err != nil {
+			return "", nil, err
+		}
+		return "", nil, usagef("indica %s", expected)
+	}
+	return args[0], args[1:], nil
+}
 
-	// Registrar Handlers
-	authHandler := handler.NewAuthHandler(authService)
-	taskHandler := handler.NewTaskHandler(taskService)
+// env es la configuración y la conexión a la base de datos de un comando
+type env struct {
+	cfg *config.Config
+	db  *gorm.DB
+	app *app.App
+}
 
-	// Iniciar el servidor
-	router := gin.Default()
+// close cierra la conexión a la base de datos
+func (e *env) close() {
+	config.CloseDB(e.db)
+}
 
-	// Ruta de documentación Swagger
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+// loadConfig carga la configuración; si es inválida el comando termina con exitConfig
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, withCode(exitConfig, fmt.Errorf("configuración inválida: %w", err))
+	}
+	return cfg, nil
+}
 
-	// Middleware de autenticación
-	authMiddleware := middleware.AuthMiddleware(config.AppConfig.JWTSecret)
+// connect carga la configuración y abre la conexión a la base de datos.
+// Quien la llama debe cerrar la conexión con env.close.
+func connect() (*env, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	db, err := config.ConnectDB(cfg)
+	if err != nil {
+		return nil, withCode(exitDatabase, err)
+	}
+	return &env{cfg: cfg, db: db}, nil
+}
 
-	// Rutas de autenticación
-	authRoutes := router.Group("/api/auth")
-	{
-		authRoutes.POST("/signup", authHandler.SignUpHandler)
-		authRoutes.POST("/login", authHandler.Login)
-		authRoutes.GET("/profile", authMiddleware, authHandler.Profile)
-		authRoutes.PUT("/profile", authMiddleware, authHandler.UpdateProfile)
-		authRoutes.DELETE("/profile", authMiddleware, authHandler.DeleteAccount)
+// checkSchema comprueba que el esquema coincida con las migraciones de este binario antes de
+// usar la base de datos: ni migraciones pendientes ni versiones desconocidas
+func checkSchema(ctx context.Context, db *gorm.DB) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
 	}
+	return schemaError(migrator.Check(ctx))
+}
 
-	// Rutas de tareas (protegidas)
-	taskRoutes := router.Group("/api/tasks")
-	taskRoutes.Use(authMiddleware)
-	{
-		taskRoutes.POST("", taskHandler.Create)
-		taskRoutes.GET("", taskHandler.GetAll)
-		taskRoutes.GET("/:id", taskHandler.GetByID)
-		taskRoutes.PUT("/:id", taskHandler.Update)
-		taskRoutes.DELETE("/:id", taskHandler.Delete)
-		taskRoutes.PATCH("/:id/status", taskHandler.ToggleStatus)
+// schemaError asigna a los errores de migración su código de salida
+func schemaError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, migrations.ErrNewerSchema):
+		return withCode(exitNewer, fmt.Errorf("%w; actualiza la aplicación", err))
+	case errors.Is(err, migrations.ErrPending):
+		return withCode(exitPending, fmt.Errorf("%w; ejecuta 'migrate up'", err))
+	default:
+		return err
 	}
+}
 
-	router.Run(config.AppConfig.Port)
+// openApp conecta a la base de datos y construye la aplicación para los comandos administrativos.
+// No inicia los eventos ni las tareas periódicas: eso solo lo hace serve.
+func openApp(ctx context.Context) (*env, error) {
+	e, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSchema(ctx, e.db); err != nil {
+		e.close()
+		return nil, err
+	}
+	if e.app, err = app.New(e.cfg, e.db); err != nil {
+		e.close()
+		return nil, err
+	}
+	return e, nil
 }
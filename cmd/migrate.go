@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/migrations"
+	"gorm.io/gorm"
+)
+
+// runMigrate aplica (up), revierte (down) o consulta (status) las migraciones versionadas.
+// status termina con exitPending si falta alguna y con exitNewer si la base de datos tiene
+// versiones que este binario no conoce, para detener un despliegue antes de servir.
+func runMigrate(ctx context.Context, cmd *command, args []string) error {
+	action, args, err := subcommand(cmd, args, "up, down o status")
+	if err != nil {
+		return err
+	}
+	fs := newFlags(cmd, "migrate "+action)
+	var steps *int
+	switch action {
+	case "up", "status":
+	case "down":
+		steps = fs.Int("steps", 1, "cantidad de migraciones a revertir")
+	default:
+		return usagef("acción desconocida %q", action)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if steps != nil && *steps < 1 {
+		return usagef("--steps debe ser al menos 1")
+	}
+
+	e, err := connect()
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	switch action {
+	case "up":
+		return migrateUp(ctx, e.db)
+	case "down":
+		return migrateDown(ctx, e.db, *steps)
+	default:
+		return migrateStatus(ctx, e.db)
+	}
+}
+
+// migrateUp aplica las migraciones pendientes
+func migrateUp(ctx context.Context, db *gorm.DB) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return schemaError(err)
+	}
+	if len(applied) == 0 {
+		log.Println("El esquema está al día")
+	}
+	return nil
+}
+
+// migrateDown revierte las últimas migraciones aplicadas
+func migrateDown(ctx context.Context, db *gorm.DB, steps int) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	reverted, err := migrator.Down(ctx, steps)
+	if err != nil {
+		return schemaError(err)
+	}
+	if len(reverted) == 0 {
+		log.Println("No hay migraciones aplicadas")
+	}
+	return nil
+}
+
+// migrateStatus lista las migraciones con su estado
+func migrateStatus(ctx context.Context, db *gorm.DB) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return withCode(exitDatabase, fmt.Errorf("no se pudo consultar el esquema: %w", err))
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSIÓN\tNOMBRE\tESTADO")
+	for _, status := range statuses {
+		state := "pendiente"
+		switch {
+		case status.Unknown:
+			state = "desconocida (aplicada por una versión más nueva)"
+		case status.AppliedAt != 0:
+			state = "aplicada " + time.Unix(status.AppliedAt, 0).Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%04d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return schemaError(migrator.Check(ctx))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// runPurgeDeleted elimina definitivamente las tareas que llevan en la papelera más de --older-than.
+// Es la misma limpieza que hace el servidor cada TRASH_PURGE_INTERVAL, a demanda.
+func runPurgeDeleted(ctx context.Context, cmd *command, args []string) error {
+	fs := newFlags(cmd, "purge-deleted")
+	olderThan := fs.Duration("older-than", 0, "antigüedad mínima en la papelera, por ejemplo 168h (por defecto TRASH_RETENTION)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return usagef("--older-than no puede ser negativo")
+	}
+
+	e, err := openApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	retention := e.cfg.TrashRetention
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "older-than" {
+			retention = *olderThan
+		}
+	})
+
+	purged, err := e.app.TaskService().PurgeExpired(ctx, retention)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d tareas eliminadas definitivamente (más de %s en la papelera)\n", purged, retention)
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+)
+
+// Datos para generar usuarios y tareas de prueba
+var (
+	seedFirstNames = []string{
+		"Ana", "Luis", "María", "Carlos", "Lucía", "Jorge", "Sofía", "Diego",
+		"Valeria", "Andrés", "Camila", "Javier", "Paula", "Martín", "Elena", "Tomás",
+	}
+	seedLastNames = []string{
+		"García", "Rodríguez", "Martínez", "López", "Hernández", "Pérez", "Sánchez",
+		"Ramírez", "Torres", "Flores", "Díaz", "Morales", "Romero", "Vargas",
+	}
+	seedTaskTitles = []string{
+		"Revisar el informe mensual de ventas",
+		"Preparar la presentación para el cliente",
+		"Pagar las facturas pendientes",
+		"Actualizar el presupuesto del proyecto",
+		"Llamar al proveedor de hosting",
+		"Organizar la reunión de equipo del lunes",
+		"Responder los tickets de soporte",
+		"Documentar los endpoints de la API",
+		"Planificar el sprint siguiente",
+		"Corregir el error de inicio de sesión",
+		"Renovar el certificado SSL",
+		"Enviar el contrato firmado",
+		"Comprar material de oficina",
+		"Reservar vuelos para la conferencia",
+		"Hacer copia de seguridad de la base de datos",
+		"Revisar el pull request de autenticación",
+		"Escribir pruebas para el servicio de tareas",
+		"Agendar cita con el dentista",
+		"Preparar la demo del viernes",
+		"Actualizar las dependencias del proyecto",
+		"Redactar el boletín de noticias",
+		"Configurar las alertas de monitoreo",
+		"Migrar los datos del sistema antiguo",
+		"Revisar los comentarios de la encuesta",
+		"Pedir presupuesto de la mudanza",
+		"Cancelar la suscripción que ya no uso",
+		"Leer la documentación de Kubernetes",
+		"Optimizar las consultas lentas",
+		"Hacer la compra semanal",
+		"Preparar la retrospectiva del equipo",
+	}
+	// seedAccents quita las tildes para formar los emails
+	seedAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ñ", "n")
+)
+
+// runSeed carga usuarios con tareas de prueba para desarrollo. Los usuarios se crean con los
+// servicios de la aplicación, igual que por la API, y todos comparten la contraseña --password.
+// Es repetible: si un email ya existe se prueba con un sufijo numérico.
+func runSeed(ctx context.Context, cmd *command, args []string) error {
+	fs := newFlags(cmd, "seed")
+	users := fs.Int("users", 5, "cantidad de usuarios")
+	tasks := fs.Int("tasks", 20, "cantidad de tareas por usuario")
+	password := fs.String("password", "password123", "contraseña de los usuarios creados")
+	seed := fs.Int64("seed", 0, "semilla de los datos aleatorios (por defecto, la hora actual)")
+	force := fs.Bool("force", false, "permitir la carga con GIN_MODE=release")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *users < 1 || *tasks < 0 {
+		return usagef("--users debe ser al menos 1 y --tasks no puede ser negativo")
+	}
+	if len(*password) < 8 {
+		return usagef("--password debe tener al menos 8 caracteres")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	e, err := openApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+	if e.cfg.GinMode == "release" && !*force {
+		return usagef("GIN_MODE=release: la carga de datos de prueba requiere --force")
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	authService := e.app.AuthService()
+	taskService := e.app.TaskService()
+	for i := 0; i < *users; i++ {
+		first := seedFirstNames[rng.Intn(len(seedFirstNames))]
+		last := seedLastNames[rng.Intn(len(seedLastNames))]
+		local := seedAccents.Replace(strings.ToLower(first + "." + last))
+
+		var user *domain.User
+		for n := 1; user == nil; n++ {
+			email := local + "@example.com"
+			if n > 1 {
+				email = fmt.Sprintf("%s%d@example.com", local, n)
+			}
+			user, err = authService.Register(ctx, &domain.UserCreate{
+				FullName: first + " " + last,
+				Email:    email,
+				Password: *password,
+			})
+			if err != nil && !errors.Is(err, service.ErrUserAlreadyExists) {
+				return fmt.Errorf("no se pudo crear el usuario %s: %w", email, err)
+			}
+		}
+
+		// Aproximadamente un tercio de las tareas quedan completadas
+		for j := 0; j < *tasks; j++ {
+			task, err := taskService.Create(ctx, user.ID, &domain.CreateTask{
+				Title: seedTaskTitles[rng.Intn(len(seedTaskTitles))],
+			})
+			if err == nil && rng.Intn(3) == 0 {
+				_, err = taskService.UpdateStatus(ctx, task.ID, user.ID, true, 0)
+			}
+			if err != nil {
+				return fmt.Errorf("no se pudo crear una tarea de %s: %w", user.Email, err)
+			}
+		}
+		fmt.Printf("%s <%s>: %d tareas\n", user.FullName, user.Email, *tasks)
+	}
+	fmt.Printf("Datos de prueba cargados (semilla %d); contraseña de los usuarios: %s\n", *seed, *password)
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/app"
+	"github.com/alexroel/gin-tasks-api/internal/rpc"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout es el tiempo que se espera a que terminen las peticiones en curso al detener el servidor
+const shutdownTimeout = 10 * time.Second
+
+// runServe inicia la API REST, el servidor gRPC, su proxy HTTP/JSON y las tareas periódicas
+// hasta recibir SIGINT o SIGTERM
+func runServe(ctx context.Context, cmd *command, args []string) error {
+	fs := newFlags(cmd, "serve")
+	migrate := fs.Bool("migrate", true, "aplicar las migraciones pendientes antes de iniciar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	e, err := connect()
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	// Ejecutar las migraciones pendientes y exigir que el esquema sea el de esta versión:
+	// con un esquema más nuevo el servidor no inicia
+	if *migrate {
+		if err := migrateUp(ctx, e.db); err != nil {
+			return err
+		}
+	}
+	if err := checkSchema(ctx, e.db); err != nil {
+		return err
+	}
+
+	// Construir la aplicación: repositorios, servicios, handlers y rutas
+	application, err := app.New(e.cfg, e.db)
+	if err != nil {
+		return err
+	}
+
+	// Iniciar los eventos en tiempo real y las tareas periódicas
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	application.Start(ctx)
+
+	// Los servidores terminan con el primer error; el resto se detiene con ctx
+	errc := make(chan error, 3)
+
+	// Servidor gRPC en su propio puerto
+	if e.cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", e.cfg.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("error al iniciar el servidor gRPC: %w", err)
+		}
+		grpcServer := application.GRPCServer()
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				errc <- fmt.Errorf("error en el servidor gRPC: %w", err)
+			}
+		}()
+		defer stopGRPC(grpcServer)
+	}
+
+	// Proxy HTTP/JSON hacia el servidor gRPC
+	if e.cfg.GRPCGatewayPort != "" {
+		gateway, err := rpc.NewGateway(ctx, "localhost"+e.cfg.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("error al iniciar el proxy gRPC: %w", err)
+		}
+		gatewayServer := newHTTPServer(ctx, e.cfg.GRPCGatewayPort, gateway)
+		go serveHTTP(gatewayServer, "el proxy gRPC", errc)
+		defer shutdown(gatewayServer)
+	}
+
+	// Servidor de la API. Las peticiones heredan ctx, así los streams de eventos
+	// y los WebSocket se cierran al recibir la señal en lugar de bloquear la parada.
+	server := newHTTPServer(ctx, e.cfg.Port, application.Router())
+	go serveHTTP(server, "el servidor", errc)
+	defer shutdown(server)
+	log.Printf("Servidor escuchando en %s", e.cfg.Port)
+
+	select {
+	case <-ctx.Done():
+		log.Println("Deteniendo el servidor...")
+	case err = <-errc:
+	}
+	cancel()
+	return err
+}
+
+// newHTTPServer crea un servidor HTTP cuyas peticiones se cancelan junto con ctx
+func newHTTPServer(ctx context.Context, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:        addr,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+}
+
+// serveHTTP atiende peticiones hasta que el servidor se detiene y envía a errc cualquier otro error
+func serveHTTP(server *http.Server, name string, errc chan<- error) {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errc <- fmt.Errorf("error en %s: %w", name, err)
+	}
+}
+
+// stopGRPC detiene el servidor gRPC esperando a las llamadas en curso como máximo shutdownTimeout
+func stopGRPC(server *grpc.Server) {
+	timer := time.AfterFunc(shutdownTimeout, server.Stop)
+	defer timer.Stop()
+	server.GracefulStop()
+}
+
+// shutdown detiene el servidor esperando a las peticiones en curso como máximo shutdownTimeout
+func shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Error al detener el servidor %s: %v", server.Addr, err)
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/pkg/client"
+	"golang.org/x/term"
+)
+
+// flags crea el conjunto de opciones de un comando. Los errores de análisis no se imprimen aquí:
+// run los muestra junto con la línea de uso.
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("tasksctl "+name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return fs
+}
+
+// parse analiza las opciones de un comando, que pueden aparecer antes o después de los argumentos
+// (por ejemplo `tasksctl add "Deploy" -o json`). Después de "--" todo se toma como argumento.
+func (a *app) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				a.printHelp(fs)
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// printHelp muestra el uso y las opciones de un comando
+func (a *app) printHelp(fs *flag.FlagSet) {
+	fmt.Fprintf(a.stdout, "Uso: tasksctl %s %s\n\n%s\n\n", a.cmd.name, a.cmd.args, a.cmd.summary)
+	fs.SetOutput(a.stdout)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+}
+
+// outputFlag agrega la opción -o con el formato de salida
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", formatTable, "formato de salida: table, json o plain")
+}
+
+// checkFormat valida el formato de salida
+func checkFormat(format string) error {
+	if !validFormat(format) {
+		return usagef("formato de salida inválido %q (use table, json o plain)", format)
+	}
+	return nil
+}
+
+// parseIDs convierte los argumentos en IDs de tareas
+func parseIDs(args []string) ([]uint, error) {
+	if len(args) == 0 {
+		return nil, usagef("indica al menos un ID de tarea")
+	}
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseUint(strings.TrimPrefix(arg, "#"), 10, 32)
+		if err != nil || id == 0 {
+			return nil, usagef("ID de tarea inválido %q", arg)
+		}
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+// runLogin inicia sesión y guarda el token y el servidor en el directorio de configuración
+func runLogin(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("login")
+	email := fs.String("email", a.settings.Email, "email de la cuenta")
+	passwordStdin := fs.Bool("password-stdin", false, "leer la contraseña de la entrada estándar")
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("argumento inesperado %q", rest[0])
+	}
+
+	reader := bufio.NewReader(a.stdin)
+	if *email == "" {
+		fmt.Fprint(a.stderr, "Email: ")
+		if *email, err = readLine(reader); err != nil {
+			return err
+		}
+	}
+
+	var password string
+	file, isFile := a.stdin.(*os.File)
+	switch {
+	case *passwordStdin:
+		if password, err = readLine(reader); err != nil {
+			return err
+		}
+	case isFile && term.IsTerminal(int(file.Fd())):
+		fmt.Fprint(a.stderr, "Contraseña: ")
+		secret, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(a.stderr)
+		if err != nil {
+			return err
+		}
+		password = string(secret)
+	default:
+		return usagef("la entrada estándar no es una terminal: usa --password-stdin")
+	}
+	if *email == "" || password == "" {
+		return usagef("el email y la contraseña son obligatorios")
+	}
+
+	user, err := a.client.Login(ctx, *email, password)
+	if err != nil {
+		return err
+	}
+	a.settings.Server = a.server
+	a.settings.Email = user.Email
+	if err := saveSettings(a.dir, a.settings); err != nil {
+		return fmt.Errorf("no se pudo guardar la configuración: %w", err)
+	}
+	fmt.Fprintf(a.stdout, "Sesión iniciada como %s <%s> en %s\n", user.FullName, user.Email, a.server)
+	return nil
+}
+
+// readLine lee una línea sin el salto de línea final
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("no se pudo leer la entrada: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// runLogout elimina el token guardado
+func runLogout(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("logout")
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("argumento inesperado %q", rest[0])
+	}
+	if err := a.client.Logout(); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stdout, "Sesión cerrada")
+	return nil
+}
+
+// runWhoami muestra el usuario de la sesión
+func runWhoami(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("whoami")
+	format := outputFlag(fs)
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("argumento inesperado %q", rest[0])
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	user, err := a.client.Profile(ctx)
+	if err != nil {
+		return err
+	}
+	return printUser(a.stdout, *format, user)
+}
+
+// runList lista las tareas. Las palabras sueltas, --filter y --status se combinan en una
+// expresión del lenguaje de filtros; con --watch sigue mostrando los cambios en tiempo real.
+func runList(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("list")
+	filter := fs.String("filter", "", "expresión de filtro, por ejemplo 'status:open created<7d'")
+	statusFlag := fs.String("status", "all", "estado de las tareas: open, done o all")
+	format := outputFlag(fs)
+	watch := fs.Bool("watch", false, "seguir mostrando los cambios de las tareas hasta Ctrl+C")
+	words, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	terms := words
+	if *filter != "" {
+		terms = append([]string{*filter}, terms...)
+	}
+	switch *statusFlag {
+	case "all":
+	case "open", "done":
+		terms = append(terms, "status:"+*statusFlag)
+	default:
+		return usagef("estado inválido %q (use open, done o all)", *statusFlag)
+	}
+	opts := &client.ListTasksOptions{Filter: strings.Join(terms, " ")}
+
+	list := func() error {
+		tasks, err := a.client.ListTasks(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printTasks(a.stdout, *format, tasks)
+	}
+	if err := list(); err != nil {
+		return err
+	}
+	if !*watch {
+		return nil
+	}
+	return a.watch(ctx, *format, list)
+}
+
+// runAdd crea una tarea con los argumentos como título
+func runAdd(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("add")
+	format := outputFlag(fs)
+	words, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	title := strings.TrimSpace(strings.Join(words, " "))
+	if title == "" {
+		return usagef("indica el título de la tarea")
+	}
+
+	task, err := a.client.CreateTask(ctx, client.CreateTaskInput{Title: title})
+	if err != nil {
+		return err
+	}
+	return printTask(a.stdout, *format, task)
+}
+
+// runDone marca tareas como completadas, o como pendientes con --undo.
+// Cada tarea se modifica sobre la versión recién leída, para no pisar cambios concurrentes.
+func runDone(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("done")
+	undo := fs.Bool("undo", false, "marcar las tareas como pendientes")
+	format := outputFlag(fs)
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	ids, err := parseIDs(rest)
+	if err != nil {
+		return err
+	}
+
+	var updated []client.Task
+	for _, id := range ids {
+		var task *client.Task
+		if task, err = a.client.GetTask(ctx, id); err == nil {
+			task, err = a.client.SetTaskStatus(ctx, id, !*undo, task.Version)
+		}
+		if err != nil {
+			err = fmt.Errorf("tarea %d: %w", id, err)
+			break
+		}
+		updated = append(updated, *task)
+	}
+	if len(updated) > 0 {
+		if printErr := printResult(a, *format, updated); printErr != nil && err == nil {
+			err = printErr
+		}
+	}
+	return err
+}
+
+// runEdit modifica el título o el estado de una tarea
+func runEdit(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("edit")
+	title := fs.String("title", "", "nuevo título")
+	statusFlag := fs.String("status", "", "nuevo estado: open o done")
+	format := outputFlag(fs)
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("indica un único ID de tarea")
+	}
+	ids, err := parseIDs(rest)
+	if err != nil {
+		return err
+	}
+
+	var input client.UpdateTaskInput
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "title" {
+			input.Title = title
+		}
+	})
+	switch *statusFlag {
+	case "":
+	case "open", "done":
+		completed := *statusFlag == "done"
+		input.Completed = &completed
+	default:
+		return usagef("estado inválido %q (use open o done)", *statusFlag)
+	}
+	if input.Title == nil && input.Completed == nil {
+		return usagef("indica --title o --status")
+	}
+
+	task, err := a.client.GetTask(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	if task, err = a.client.UpdateTask(ctx, task.ID, input, task.Version); err != nil {
+		return err
+	}
+	return printTask(a.stdout, *format, task)
+}
+
+// runRemove mueve tareas a la papelera
+func runRemove(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("rm")
+	rest, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	ids, err := parseIDs(rest)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		task, err := a.client.GetTask(ctx, id)
+		if err == nil {
+			err = a.client.DeleteTask(ctx, id, task.Version)
+		}
+		if err != nil {
+			return fmt.Errorf("tarea %d: %w", id, err)
+		}
+		fmt.Fprintf(a.stdout, "Tarea %d movida a la papelera: %s\n", task.ID, task.Title)
+	}
+	return nil
+}
+
+// printResult escribe las tareas modificadas por un comando: una sola tarea en JSON es un objeto
+func printResult(a *app, format string, tasks []client.Task) error {
+	if len(tasks) == 1 {
+		return printTask(a.stdout, format, &tasks[0])
+	}
+	return printTasks(a.stdout, format, tasks)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// settings es la configuración que tasksctl guarda entre ejecuciones
+type settings struct {
+	Server string `json:"server"`
+	Email  string `json:"email,omitempty"`
+}
+
+// configDir devuelve el directorio de configuración: TASKSCTL_CONFIG_DIR o
+// el directorio de configuración del sistema operativo (por ejemplo ~/.config/tasksctl)
+func configDir() (string, error) {
+	if dir := os.Getenv("TASKSCTL_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "tasksctl"), nil
+}
+
+// loadSettings lee la configuración guardada; si no existe devuelve una configuración vacía
+func loadSettings(dir string) (*settings, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// saveSettings guarda la configuración con permisos 0600
+func saveSettings(dir string, s *settings) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "config.json"), append(data, '\n'), 0o600)
+}
@@ -0,0 +1,189 @@
+// Command tasksctl es un cliente de línea de comandos para la API de tareas.
+//
+// Uso:
+//
+//	tasksctl [--server URL] <comando> [argumentos]
+//
+// La sesión se guarda en el directorio de configuración del sistema operativo
+// (o en TASKSCTL_CONFIG_DIR) y se renueva automáticamente.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/alexroel/gin-tasks-api/pkg/client"
+)
+
+// defaultServer es la URL de la API si no se indica otra
+const defaultServer = "http://localhost:8080"
+
+// Códigos de salida
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// usageError es un error en los argumentos de un comando
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef crea un usageError con formato
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// command es un subcomando de tasksctl
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, app *app, args []string) error
+}
+
+// commands son los subcomandos disponibles, en el orden en que se muestran en la ayuda
+var commands = []command{
+	{"login", "[--email EMAIL] [--password-stdin]", "Iniciar sesión y guardar el token", runLogin},
+	{"logout", "", "Cerrar la sesión local", runLogout},
+	{"whoami", "[-o FORMATO]", "Mostrar el usuario de la sesión", runWhoami},
+	{"list", "[--filter EXPR] [--status open|done|all] [-o FORMATO] [--watch] [PALABRAS...]", "Listar tareas", runList},
+	{"add", "[-o FORMATO] TÍTULO...", "Crear una tarea", runAdd},
+	{"done", "[--undo] [-o FORMATO] ID...", "Marcar tareas como completadas", runDone},
+	{"edit", "[--title TÍTULO] [--status open|done] [-o FORMATO] ID", "Modificar una tarea", runEdit},
+	{"rm", "ID...", "Mover tareas a la papelera", runRemove},
+}
+
+// app contiene el estado compartido por los comandos
+type app struct {
+	cmd      *command
+	dir      string
+	settings *settings
+	server   string
+	client   *client.Client
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run ejecuta tasksctl con los argumentos indicados y devuelve el código de salida
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("tasksctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	server := global.String("server", "", "URL de la API (por defecto TASKS_SERVER, la del último login o "+defaultServer+")")
+	global.Usage = func() { printUsage(stderr, global) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		printUsage(stderr, global)
+		return exitUsage
+	}
+
+	name := global.Arg(0)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "tasksctl: comando desconocido %q\n", name)
+		printUsage(stderr, global)
+		return exitUsage
+	}
+
+	a, err := newApp(*server, stdin, stdout, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "tasksctl:", err)
+		return exitError
+	}
+
+	a.cmd = cmd
+	err = cmd.run(ctx, a, global.Args()[1:])
+	var usageErr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "tasksctl %s: %v\nUso: tasksctl %s %s\n", cmd.name, err, cmd.name, cmd.args)
+		return exitUsage
+	case errors.Is(err, client.ErrNotLoggedIn):
+		fmt.Fprintln(stderr, "tasksctl: no hay sesión iniciada; ejecuta 'tasksctl login'")
+		return exitError
+	case errors.Is(err, client.ErrUnauthorized) && cmd.name != "login":
+		fmt.Fprintln(stderr, "tasksctl: la sesión expiró o no es válida; ejecuta 'tasksctl login'")
+		return exitError
+	default:
+		fmt.Fprintln(stderr, "tasksctl:", err)
+		return exitError
+	}
+}
+
+// newApp carga la configuración y crea el cliente de la API.
+// La URL se toma de --server, de TASKS_SERVER o de la configuración guardada, en ese orden.
+func newApp(server string, stdin io.Reader, stdout, stderr io.Writer) (*app, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo determinar el directorio de configuración: %w", err)
+	}
+	s, err := loadSettings(dir)
+	if err != nil {
+		return nil, fmt.Errorf("configuración inválida en %s: %w", dir, err)
+	}
+
+	for _, candidate := range []string{server, os.Getenv("TASKS_SERVER"), s.Server, defaultServer} {
+		if candidate != "" {
+			server = candidate
+			break
+		}
+	}
+	c, err := client.New(server,
+		client.WithTokenStore(client.NewFileTokenStore(filepath.Join(dir, "token"))),
+		client.WithUserAgent("tasksctl"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &app{dir: dir, settings: s, server: server, client: c, stdin: stdin, stdout: stdout, stderr: stderr}, nil
+}
+
+// printUsage muestra la ayuda general
+func printUsage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintln(w, "Uso: tasksctl [--server URL] <comando> [argumentos]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Comandos:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Opciones globales:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Formatos de salida (-o): table, json, plain. Use 'tasksctl <comando> -h' para ver sus opciones.")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/pkg/client"
+)
+
+// Formatos de salida
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatPlain = "plain"
+)
+
+// validFormat indica si el formato de salida es conocido
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatPlain
+}
+
+// status devuelve el estado de una tarea con los mismos valores que el lenguaje de filtros
+func status(task client.Task) string {
+	if task.Completed {
+		return "done"
+	}
+	return "open"
+}
+
+// printTasks escribe una lista de tareas.
+// table es para leer en la terminal, json es un arreglo y plain tiene una tarea por línea
+// con los campos ID, estado y título separados por tabuladores, sin encabezado.
+func printTasks(w io.Writer, format string, tasks []client.Task) error {
+	switch format {
+	case formatJSON:
+		if tasks == nil {
+			tasks = []client.Task{}
+		}
+		return writeJSON(w, tasks)
+	case formatPlain:
+		for _, task := range tasks {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\n", task.ID, status(task), task.Title); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if len(tasks) == 0 {
+			_, err := fmt.Fprintln(w, "No hay tareas")
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tESTADO\tTÍTULO\tVERSIÓN\tACTUALIZADA")
+		for _, task := range tasks {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n", task.ID, status(task), task.Title, task.Version, formatTime(task.UpdatedAt))
+		}
+		return tw.Flush()
+	}
+}
+
+// printTask escribe una tarea; en JSON es un objeto en lugar de un arreglo
+func printTask(w io.Writer, format string, task *client.Task) error {
+	if format == formatJSON {
+		return writeJSON(w, task)
+	}
+	return printTasks(w, format, []client.Task{*task})
+}
+
+// printUser escribe el usuario autenticado
+func printUser(w io.Writer, format string, user *client.User) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(w, user)
+	case formatPlain:
+		_, err := fmt.Fprintf(w, "%d\t%s\t%s\n", user.ID, user.Email, user.FullName)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, "%s <%s> (ID %d)\n", user.FullName, user.Email, user.ID)
+		return err
+	}
+}
+
+// eventJSON es la representación JSON de un evento en modo --watch
+type eventJSON struct {
+	ID     string       `json:"id,omitempty"`
+	Type   string       `json:"type"`
+	TaskID uint         `json:"task_id,omitempty"`
+	Action string       `json:"action,omitempty"`
+	Task   *client.Task `json:"task,omitempty"`
+}
+
+// printEvent escribe un evento en tiempo real en una línea: en JSON, un objeto por línea
+func printEvent(w io.Writer, format string, event client.Event) error {
+	if format == formatJSON {
+		return json.NewEncoder(w).Encode(eventJSON{
+			ID:     event.ID,
+			Type:   event.Type,
+			TaskID: event.TaskID,
+			Action: event.Action,
+			Task:   event.Task,
+		})
+	}
+
+	fields := []string{event.Type, fmt.Sprint(event.TaskID)}
+	if event.Task != nil && event.Type != client.EventTaskDeleted {
+		fields = append(fields, status(*event.Task), event.Task.Title)
+	}
+	if format == formatPlain {
+		_, err := fmt.Fprintln(w, strings.Join(fields, "\t"))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s  %-12s  #%s\n", time.Now().Format("15:04:05"), fields[0], strings.Join(fields[1:], "  "))
+	return err
+}
+
+// writeJSON escribe un valor como JSON indentado
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// formatTime formatea una marca de tiempo Unix en la zona horaria local
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).Format("2006-01-02 15:04")
+}
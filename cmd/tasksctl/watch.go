@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/pkg/client"
+)
+
+// Espera antes de reconectar el stream de eventos; se duplica en cada fallo seguido
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// watch muestra los eventos en tiempo real hasta que se cancela ctx (Ctrl+C).
+// Si se pierde la conexión, reconecta con el ID del último evento para no perder cambios;
+// si el servidor ya no los tiene (evento resync), vuelve a mostrar la lista con reload.
+// Los eventos son de todas las tareas del usuario: no se les aplica el filtro de la lista.
+func (a *app) watch(ctx context.Context, format string, reload func() error) error {
+	if format != formatJSON {
+		fmt.Fprintln(a.stderr, "Esperando cambios (Ctrl+C para salir)...")
+	}
+
+	lastEventID := ""
+	delay := minReconnectDelay
+	for {
+		var handlerErr error
+		err := a.client.Events(ctx, lastEventID, func(event client.Event) error {
+			delay = minReconnectDelay
+			if event.ID != "" {
+				lastEventID = event.ID
+			}
+			if event.Type == client.EventResync && format != formatJSON {
+				fmt.Fprintln(a.stderr, "Se perdieron eventos; lista actualizada:")
+				handlerErr = reload()
+			} else {
+				handlerErr = printEvent(a.stdout, format, event)
+			}
+			return handlerErr
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if handlerErr != nil {
+			return handlerErr
+		}
+		if errors.Is(err, client.ErrNotLoggedIn) || errors.Is(err, client.ErrUnauthorized) || errors.Is(err, client.ErrForbidden) {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(a.stderr, "tasksctl: se perdió la conexión (%v); reconectando en %s\n", err, delay)
+		} else {
+			fmt.Fprintf(a.stderr, "tasksctl: el servidor cerró la conexión; reconectando en %s\n", delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
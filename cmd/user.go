@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/gin-gonic/gin/binding"
+	"golang.org/x/term"
+)
+
+// runUser crea usuarios (create) o restablece su contraseña (reset-password)
+func runUser(ctx context.Context, cmd *command, args []string) error {
+	action, args, err := subcommand(cmd, args, "create o reset-password")
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "create":
+		return runUserCreate(ctx, cmd, args)
+	case "reset-password":
+		return runUserResetPassword(ctx, cmd, args)
+	default:
+		return usagef("acción desconocida %q", action)
+	}
+}
+
+// runUserCreate crea un usuario, opcionalmente administrador.
+// La contraseña se pide por la terminal o se lee de la entrada estándar con --password-stdin.
+func runUserCreate(ctx context.Context, cmd *command, args []string) error {
+	fs := newFlags(cmd, "user create")
+	email := fs.String("email", "", "email del usuario (obligatorio)")
+	name := fs.String("name", "", "nombre completo (obligatorio)")
+	admin := fs.Bool("admin", false, "crear el usuario como administrador")
+	passwordStdin := fs.Bool("password-stdin", false, "leer la contraseña de la entrada estándar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *email == "" || *name == "" {
+		return usagef("--email y --name son obligatorios")
+	}
+
+	password, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+	req := &domain.UserCreate{
+		FullName: strings.TrimSpace(*name),
+		Email:    strings.TrimSpace(*email),
+		Password: password,
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return usagef("datos inválidos: %v", err)
+	}
+
+	e, err := openApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	user, err := e.app.AuthService().CreateUser(ctx, req, *admin)
+	if errors.Is(err, service.ErrUserAlreadyExists) {
+		return fmt.Errorf("ya existe un usuario con el email %s", req.Email)
+	}
+	if err != nil {
+		return err
+	}
+	role := "usuario"
+	if user.IsAdmin {
+		role = "administrador"
+	}
+	fmt.Printf("Usuario creado: %s <%s> (ID %d, %s)\n", user.FullName, user.Email, user.ID, role)
+	return nil
+}
+
+// runUserResetPassword reemplaza la contraseña de un usuario sin pedir la actual
+func runUserResetPassword(ctx context.Context, cmd *command, args []string) error {
+	fs := newFlags(cmd, "user reset-password")
+	email := fs.String("email", "", "email del usuario (obligatorio)")
+	passwordStdin := fs.Bool("password-stdin", false, "leer la nueva contraseña de la entrada estándar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usagef("--email es obligatorio")
+	}
+
+	password, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+	if err := binding.Validator.ValidateStruct(&domain.UserUpdate{Password: &password}); err != nil {
+		return usagef("contraseña inválida: %v", err)
+	}
+
+	e, err := openApp(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	user, err := e.app.AuthService().ResetPassword(ctx, strings.TrimSpace(*email), password)
+	if errors.Is(err, service.ErrUserNotFound) {
+		return fmt.Errorf("no existe un usuario con el email %s", *email)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Contraseña restablecida: %s <%s> (ID %d)\n", user.FullName, user.Email, user.ID)
+	return nil
+}
+
+// readPassword lee una contraseña de la entrada estándar (una línea) o, si es una terminal,
+// la pide dos veces sin mostrarla
+func readPassword(fromStdin bool) (string, error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return "", fmt.Errorf("no se pudo leer la contraseña: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", usagef("la entrada estándar no es una terminal: usa --password-stdin")
+	}
+	var answers [2]string
+	for i, prompt := range []string{"Contraseña: ", "Repite la contraseña: "} {
+		fmt.Fprint(os.Stderr, prompt)
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("no se pudo leer la contraseña: %w", err)
+		}
+		answers[i] = string(secret)
+	}
+	if answers[0] != answers[1] {
+		return "", errors.New("las contraseñas no coinciden")
+	}
+	return answers[0], nil
+}
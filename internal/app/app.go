@@ -0,0 +1,282 @@
+// Package app construye la aplicación: repositorios, servicios, handlers y rutas.
+// Lo usan tanto el binario del servidor como las pruebas que necesitan el router real.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/collab"
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/alexroel/gin-tasks-api/internal/events"
+	"github.com/alexroel/gin-tasks-api/internal/graph"
+	"github.com/alexroel/gin-tasks-api/internal/handler"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/internal/rpc"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+
+	_ "github.com/alexroel/gin-tasks-api/docs"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// App contiene el router HTTP y los servicios que usan las tareas en segundo plano.
+// Cada App tiene su propia configuración y conexión, así que puede haber varias en un mismo proceso.
+type App struct {
+	cfg                *config.Config
+	router             *gin.Engine
+	hub                *events.Hub
+	authService        service.AuthServiceInterface
+	taskService        service.TaskService
+	idempotencyService service.IdempotencyService
+	webhookService     service.WebhookService
+}
+
+// New construye la aplicación a partir de la configuración y la base de datos ya conectada
+func New(cfg *config.Config, db *gorm.DB) (*App, error) {
+	// Registrar repositorios
+	userRepo := repository.NewUserRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	filterRepo := repository.NewFilterRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	importJobRepo := repository.NewImportJobRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	appPasswordRepo := repository.NewAppPasswordRepository(db)
+
+	// Eventos en tiempo real: el PubSub distribuye los eventos entre réplicas
+	var pubsub events.PubSub
+	switch cfg.EventsBackend {
+	case "postgres":
+		pubsub = events.NewPostgresPubSub(db, cfg.URLDatabase)
+	case "database":
+		pubsub = events.NewDatabasePubSub(db, cfg.EventsPollInterval)
+	default:
+		pubsub = events.NewMemoryPubSub()
+	}
+	hub := events.NewHub(pubsub, cfg.EventsReplaySize)
+
+	// Registrar servicios
+	webhookService := service.NewWebhookService(webhookRepo, cfg.WebhookMaxAttempts, cfg.WebhookDisableAfter)
+	activityService := service.NewActivityService(activityRepo, taskRepo, service.NewEventNotifier(hub), webhookService)
+	authService := service.NewAuthService(userRepo, activityService, cfg.JWTSecret, cfg.JWTExpireIn)
+	taskService := service.NewTaskService(taskRepo, activityService, cfg.TrashRetention)
+	filterService := service.NewFilterService(filterRepo, taskRepo)
+	calendarService := service.NewCalendarService(calendarFeedRepo, taskRepo)
+	appPasswordService := service.NewAppPasswordService(appPasswordRepo, userRepo)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL, cfg.IdempotencyLease)
+	importService := service.NewImportService(taskRepo, importJobRepo, activityService, cfg.ImportAsyncThreshold)
+
+	// Registrar Handlers
+	authHandler := handler.NewAuthHandler(authService)
+	taskHandler := handler.NewTaskHandler(taskService)
+	activityHandler := handler.NewActivityHandler(activityService)
+	syncHandler := handler.NewSyncHandler(taskService)
+	importHandler := handler.NewImportHandler(importService, cfg.ImportMaxBytes)
+	filterHandler := handler.NewFilterHandler(filterService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	appPasswordHandler := handler.NewAppPasswordHandler(appPasswordService)
+	calDAVHandler := handler.NewCalDAVHandler(taskService)
+	eventsHandler := handler.NewEventsHandler(hub, cfg.EventsHeartbeat)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	graphQLServer, err := graph.NewServer(taskService, filterService, activityService, authService, cfg.GraphQLMaxDepth, cfg.GraphQLMaxComplexity)
+	if err != nil {
+		return nil, fmt.Errorf("error al construir el esquema GraphQL: %w", err)
+	}
+	graphQLHandler := handler.NewGraphQLHandler(graphQLServer)
+	collabHandler := handler.NewCollabHandler(collab.NewHub(), taskService, hub, cfg.WSAllowedOrigins)
+
+	// Registrar rutas
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
+
+	// Ruta de documentación Swagger
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Middleware de autenticación
+	authMiddleware := middleware.AuthMiddleware(cfg.JWTSecret)
+
+	// Middleware de concurrencia optimista para las rutas que modifican recursos
+	ifMatch := middleware.RequireIfMatch(cfg.RequireIfMatch)
+
+	// Middleware de idempotencia para reintentar peticiones POST de forma segura
+	idempotency := middleware.Idempotency(idempotencyService, cfg.IdempotencyMaxBody)
+
+	// Rutas de autenticación
+	authRoutes := router.Group("/api/auth")
+	{
+		authRoutes.POST("/signup", authHandler.SignUpHandler)
+		authRoutes.POST("/login", authHandler.Login)
+		authRoutes.POST("/refresh", authMiddleware, authHandler.Refresh)
+		authRoutes.GET("/profile", authMiddleware, authHandler.Profile)
+		authRoutes.PUT("/profile", authMiddleware, ifMatch, authHandler.UpdateProfile)
+		authRoutes.PATCH("/profile", authMiddleware, ifMatch, authHandler.PatchProfile)
+		authRoutes.DELETE("/profile", authMiddleware, ifMatch, authHandler.DeleteAccount)
+		// Sin idempotencia: la respuesta contiene la contraseña en claro y no debe guardarse
+		authRoutes.POST("/app-passwords", authMiddleware, appPasswordHandler.Create)
+		authRoutes.GET("/app-passwords", authMiddleware, appPasswordHandler.GetAll)
+		authRoutes.DELETE("/app-passwords/:id", authMiddleware, appPasswordHandler.Delete)
+	}
+
+	// Rutas de tareas (protegidas)
+	taskRoutes := router.Group("/api/tasks")
+	taskRoutes.Use(authMiddleware, idempotency)
+	{
+		taskRoutes.POST("", taskHandler.Create)
+		taskRoutes.GET("", taskHandler.GetAll)
+		taskRoutes.POST("/bulk", taskHandler.Bulk)
+		taskRoutes.GET("/search", taskHandler.Search)
+		taskRoutes.GET("/export", taskHandler.Export)
+		taskRoutes.GET("/trash", taskHandler.Trash)
+		taskRoutes.DELETE("/trash/:id", taskHandler.Purge)
+		taskRoutes.GET("/:id", taskHandler.GetByID)
+		taskRoutes.PUT("/:id", ifMatch, taskHandler.Update)
+		taskRoutes.PATCH("/:id", ifMatch, taskHandler.Patch)
+		taskRoutes.DELETE("/:id", ifMatch, taskHandler.Delete)
+		taskRoutes.PATCH("/:id/status", ifMatch, taskHandler.ToggleStatus)
+		taskRoutes.GET("/:id/activity", activityHandler.TaskActivity)
+		taskRoutes.POST("/:id/restore", taskHandler.Restore)
+		taskRoutes.POST("/:id/move", taskHandler.Move)
+	}
+
+	// Rutas de filtros guardados (protegidas)
+	filterRoutes := router.Group("/api/filters")
+	filterRoutes.Use(authMiddleware, idempotency)
+	{
+		filterRoutes.POST("", filterHandler.Create)
+		filterRoutes.GET("", filterHandler.GetAll)
+		filterRoutes.GET("/:id", filterHandler.GetByID)
+		filterRoutes.PUT("/:id", filterHandler.Update)
+		filterRoutes.DELETE("/:id", filterHandler.Delete)
+		filterRoutes.GET("/:id/tasks", filterHandler.Tasks)
+	}
+
+	// Rutas de feeds de calendario (protegidas)
+	calendarRoutes := router.Group("/api/calendar/feeds")
+	// Sin idempotencia: crear y rotar devuelven el token en claro y la respuesta no debe guardarse
+	calendarRoutes.Use(authMiddleware)
+	{
+		calendarRoutes.POST("", calendarHandler.Create)
+		calendarRoutes.GET("", calendarHandler.GetAll)
+		calendarRoutes.GET("/:id", calendarHandler.GetByID)
+		calendarRoutes.PUT("/:id", calendarHandler.Update)
+		calendarRoutes.DELETE("/:id", calendarHandler.Delete)
+		calendarRoutes.POST("/:id/rotate", calendarHandler.Rotate)
+	}
+
+	// Feed iCalendar público: el token de la URL autentica la suscripción
+	router.GET("/api/calendar/:token", calendarHandler.Feed)
+
+	// Rutas de sincronización sin conexión (protegidas)
+	syncRoutes := router.Group("/api/sync")
+	syncRoutes.Use(authMiddleware, idempotency)
+	{
+		syncRoutes.GET("", syncHandler.Pull)
+		syncRoutes.POST("", syncHandler.Push)
+	}
+
+	// Rutas de importación (protegidas)
+	importRoutes := router.Group("/api/import")
+	// El middleware de idempotencia lee el cuerpo antes que el handler: usa el límite de la importación
+	importRoutes.Use(authMiddleware, middleware.Idempotency(idempotencyService, importHandler.MaxBodyBytes()))
+	{
+		importRoutes.POST("", importHandler.Import)
+		importRoutes.GET("/jobs/:id", importHandler.Job)
+	}
+
+	// Rutas de webhooks (protegidas)
+	webhookRoutes := router.Group("/api/webhooks")
+	webhookRoutes.Use(authMiddleware, idempotency)
+	{
+		webhookRoutes.POST("", webhookHandler.Create)
+		webhookRoutes.GET("", webhookHandler.GetAll)
+		webhookRoutes.GET("/:id", webhookHandler.GetByID)
+		webhookRoutes.PUT("/:id", webhookHandler.Update)
+		webhookRoutes.DELETE("/:id", webhookHandler.Delete)
+		webhookRoutes.GET("/:id/deliveries", webhookHandler.Deliveries)
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+	}
+
+	// Rutas de actividad (protegidas)
+	activityRoutes := router.Group("/api/activity")
+	activityRoutes.Use(authMiddleware)
+	{
+		activityRoutes.GET("", activityHandler.Feed)
+	}
+
+	// Rutas de eventos en tiempo real (protegidas)
+	router.GET("/api/events", authMiddleware, eventsHandler.Stream)
+	router.GET("/api/ws", middleware.TokenFromQuery(), authMiddleware, collabHandler.Connect)
+
+	// Endpoint GraphQL (protegido)
+	router.POST("/api/graphql", authMiddleware, graphQLHandler.Query)
+
+	// Servidor CalDAV: los clientes se autentican con HTTP Basic y una contraseña de aplicación
+	calDAVAuth := middleware.BasicAuth("gin-tasks-api CalDAV", appPasswordService)
+	router.Handle(http.MethodOptions, "/caldav/*path", calDAVHandler.Options)
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, handler.MethodPropfind, handler.MethodReport} {
+		router.Handle(method, "/caldav/*path", calDAVAuth, calDAVHandler.Serve)
+	}
+	router.GET("/.well-known/caldav", calDAVHandler.WellKnown)
+	router.Handle(handler.MethodPropfind, "/.well-known/caldav", calDAVHandler.WellKnown)
+
+	return &App{
+		cfg:                cfg,
+		router:             router,
+		hub:                hub,
+		authService:        authService,
+		taskService:        taskService,
+		idempotencyService: idempotencyService,
+		webhookService:     webhookService,
+	}, nil
+}
+
+// Router devuelve el router HTTP con todas las rutas registradas
+func (a *App) Router() *gin.Engine {
+	return a.router
+}
+
+// AuthService devuelve el servicio de usuarios, para las tareas administrativas
+func (a *App) AuthService() service.AuthServiceInterface {
+	return a.authService
+}
+
+// TaskService devuelve el servicio de tareas, para las tareas administrativas
+func (a *App) TaskService() service.TaskService {
+	return a.taskService
+}
+
+// GRPCServer construye el servidor gRPC sobre los mismos servicios que la API REST
+func (a *App) GRPCServer() *grpc.Server {
+	return rpc.NewServer(a.authService, a.taskService, a.cfg.JWTSecret, a.cfg.RequireIfMatch)
+}
+
+// Start inicia los eventos en tiempo real y las tareas periódicas en segundo plano.
+// Se detienen al cancelar ctx.
+func (a *App) Start(ctx context.Context) {
+	// Entregar los eventos en tiempo real a los clientes conectados
+	go func() {
+		if err := a.hub.Run(ctx); err != nil {
+			log.Fatal("Error al iniciar los eventos en tiempo real: ", err)
+		}
+	}()
+
+	// Purgar periódicamente la papelera de tareas
+	go service.StartTrashPurger(ctx, a.taskService, a.cfg.TrashRetention, a.cfg.TrashPurgeInterval)
+
+	// Reequilibrar periódicamente las posiciones del orden manual
+	go service.StartPositionRebalancer(ctx, a.taskService, a.cfg.RebalanceInterval)
+
+	// Purgar periódicamente las claves de idempotencia expiradas
+	go service.StartIdempotencyPurger(ctx, a.idempotencyService, a.cfg.IdempotencyPurgeInterval)
+
+	// Enviar periódicamente las entregas pendientes de webhooks
+	go service.StartWebhookDispatcher(ctx, a.webhookService, a.cfg.WebhookDispatchInterval)
+}
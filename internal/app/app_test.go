@@ -0,0 +1,259 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/alexroel/gin-tasks-api/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestApp construye la aplicación sobre una base SQLite temporal con las variables de env
+func newTestApp(t *testing.T, env map[string]string) (http.Handler, *gorm.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GIN_MODE", gin.TestMode)
+	t.Setenv("JWT_SECRET", "secreto-de-pruebas-de-la-app")
+	t.Setenv("DB_DRIVER", config.DriverSQLite)
+	t.Setenv("URL_DATABASE", filepath.Join(t.TempDir(), "tasks.db"))
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	db := testutil.NewDB(t, cfg)
+
+	application, err := New(cfg, db)
+	require.NoError(t, err)
+	return application.Router(), db
+}
+
+// login registra un usuario e inicia sesión; devuelve el token
+func login(t *testing.T, router http.Handler) string {
+	t.Helper()
+	credentials := `{"full_name":"Ana Pérez","email":"ana@example.com","password":"secreto123"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/auth/signup", strings.NewReader(credentials)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(credentials)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data.Token
+}
+
+func TestImportBodyLimit(t *testing.T) {
+	router, _ := newTestApp(t, map[string]string{"IMPORT_MAX_BYTES": "1024"})
+	token := login(t, router)
+
+	tests := []struct {
+		name     string
+		fileSize int
+		key      string
+		want     int
+	}{
+		{"archivo dentro del límite con clave", 200, "import-1", http.StatusCreated},
+		{"archivo dentro del límite sin clave", 200, "", http.StatusCreated},
+		{"cuerpo demasiado grande con clave", 256 << 10, "import-2", http.StatusRequestEntityTooLarge},
+		{"cuerpo demasiado grande sin clave", 256 << 10, "", http.StatusRequestEntityTooLarge},
+		{"archivo demasiado grande con clave", 2048, "import-3", http.StatusRequestEntityTooLarge},
+	}
+	send := func(t *testing.T, fileSize int, key string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		require.NoError(t, form.WriteField("format", "todotxt"))
+		file, err := form.CreateFormFile("file", "tareas.txt")
+		require.NoError(t, err)
+		line := "Tarea importada\n"
+		_, err = file.Write([]byte(strings.Repeat(line, fileSize/len(line)+1)[:fileSize]))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(t, tt.fileSize, tt.key)
+			require.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+
+	// El cuerpo demasiado grande se rechaza antes de reservar la clave: se puede reintentar
+	// con la misma clave y un archivo válido
+	t.Run("reintento tras 413", func(t *testing.T) {
+		w := send(t, 200, "import-2")
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+}
+
+// Si no se puede registrar la actividad, la eliminación se revierte: sync depende del historial
+// para conocer las tareas eliminadas definitivamente
+func TestDeleteRollsBackWithoutActivity(t *testing.T) {
+	router, db := newTestApp(t, nil)
+	token := login(t, router)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(title string) string {
+		w := send(http.MethodPost, "/api/tasks", `{"title":"`+title+`"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return fmt.Sprint(resp.Data.ID)
+	}
+	failActivity := func(fail bool) {
+		if fail {
+			require.NoError(t, db.Exec("CREATE TRIGGER fail_activity BEFORE INSERT ON activities BEGIN SELECT RAISE(ABORT, 'sin historial'); END").Error)
+		} else {
+			require.NoError(t, db.Exec("DROP TRIGGER fail_activity").Error)
+		}
+	}
+
+	first, second := create("primera"), create("segunda")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		check  string
+	}{
+		{"eliminar", http.MethodDelete, "/api/tasks/" + first, "", "/api/tasks/" + first},
+		{"eliminar varias", http.MethodPost, "/api/tasks/bulk", `{"action":"delete","ids":[` + second + `]}`, "/api/tasks/" + second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failActivity(true)
+			w := send(tt.method, tt.path, tt.body)
+			failActivity(false)
+			assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+			assert.Equal(t, http.StatusOK, send(http.MethodGet, tt.check, "").Code, "la tarea debe seguir existiendo")
+		})
+	}
+
+	t.Run("eliminar definitivamente", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send(http.MethodDelete, "/api/tasks/"+first, "").Code)
+
+		failActivity(true)
+		w := send(http.MethodDelete, "/api/tasks/trash/"+first, "")
+		failActivity(false)
+		assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+		assert.Contains(t, send(http.MethodGet, "/api/tasks/trash", "").Body.String(), `"title":"primera"`)
+	})
+}
+
+// Las respuestas que contienen un secreto en claro no se guardan en idempotency_keys aunque
+// la petición envíe Idempotency-Key
+func TestSecretResponsesAreNotStored(t *testing.T) {
+	router, db := newTestApp(t, nil)
+	token := login(t, router)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		secret string
+	}{
+		{"contraseña de aplicación", "/api/auth/app-passwords", `{"name":"Thunderbird"}`, "password"},
+		{"feed de calendario", "/api/calendar/feeds", `{"name":"Pendientes"}`, "token"},
+		{"rotación del feed", "/api/calendar/feeds/1/rotate", "", "url"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Idempotency-Key", fmt.Sprintf("secreto-%d", i))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Less(t, w.Code, 300, w.Body.String())
+
+			var resp struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			secret, _ := resp.Data[tt.secret].(string)
+			require.NotEmpty(t, secret, w.Body.String())
+
+			var count int64
+			require.NoError(t, db.Table("idempotency_keys").Where("response_body LIKE ?", "%"+secret+"%").Count(&count).Error)
+			assert.Zero(t, count)
+		})
+	}
+}
+
+// Completar o reabrir una tarea con PATCH o PUT emite task.completed o task.reopened además de
+// task.updated, igual que el cambio de estado
+func TestWebhookCompletionEventsOnUpdate(t *testing.T) {
+	router, db := newTestApp(t, nil)
+	token := login(t, router)
+
+	send := func(t *testing.T, method, path, contentType, body string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, w.Body.String())
+	}
+	send(t, http.MethodPost, "/api/webhooks", "application/json", `{"url":"https://hooks.example.com/tasks","events":["*"]}`)
+	send(t, http.MethodPost, "/api/tasks", "application/json", `{"title":"Informe"}`)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		want        []string
+	}{
+		{"completar con PATCH", http.MethodPatch, "application/merge-patch+json", `{"completed":true}`, []string{"task.updated", "task.completed"}},
+		{"cambiar el título con PATCH", http.MethodPatch, "application/merge-patch+json", `{"title":"Informe final"}`, []string{"task.updated"}},
+		{"reabrir con PUT", http.MethodPut, "application/json", `{"completed":false}`, []string{"task.updated", "task.reopened"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, db.Exec("DELETE FROM webhook_deliveries").Error)
+			send(t, tt.method, "/api/tasks/1", tt.contentType, tt.body)
+
+			var events []string
+			require.NoError(t, db.Table("webhook_deliveries").Order("id").Pluck("event", &events).Error)
+			assert.Equal(t, tt.want, events)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package caldav implementa las piezas del protocolo CalDAV (RFC 4791) que no dependen del
+// acceso a datos: rutas y nombres de los recursos, lectura de las peticiones XML, escritura de
+// las respuestas Multi-Status y conversión entre tareas y componentes VTODO.
+//
+// Las tareas todavía no pertenecen a proyectos, por lo que cada usuario tiene una sola
+// colección de calendario con todas sus tareas.
+package caldav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/exporter"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+)
+
+// Rutas de los recursos CalDAV
+const (
+	RootPath       = "/caldav/"
+	PrincipalPath  = "/caldav/principal/"
+	HomePath       = "/caldav/calendars/"
+	CollectionPath = "/caldav/calendars/tasks/"
+)
+
+// CollectionName es el nombre visible de la colección de tareas
+const CollectionName = "Tareas"
+
+// ContentType es el tipo de contenido de los recursos de la colección
+const ContentType = "text/calendar; charset=utf-8; component=VTODO"
+
+// SyncTokenPrefix convierte el token de sincronización de las tareas en la URI que exige WebDAV Sync
+const SyncTokenPrefix = "urn:gin-tasks-api:sync:"
+
+// maxResourceName es la longitud máxima del nombre de un recurso, la de client_id
+const maxResourceName = 64
+
+// Tipos de recurso según su ruta
+const (
+	KindUnknown = iota
+	KindRoot
+	KindPrincipal
+	KindHome
+	KindCollection
+	KindResource
+)
+
+var (
+	ErrUnsupportedComponent = errors.New("la colección solo admite componentes VTODO")
+	ErrInvalidTodo          = errors.New("VTODO inválido")
+)
+
+// Resolve clasifica una ruta y, si es un recurso de la colección, devuelve su nombre sin la extensión .ics
+func Resolve(path string) (int, string) {
+	switch path {
+	case RootPath, strings.TrimSuffix(RootPath, "/"):
+		return KindRoot, ""
+	case PrincipalPath, strings.TrimSuffix(PrincipalPath, "/"):
+		return KindPrincipal, ""
+	case HomePath, strings.TrimSuffix(HomePath, "/"):
+		return KindHome, ""
+	case CollectionPath, strings.TrimSuffix(CollectionPath, "/"):
+		return KindCollection, ""
+	}
+
+	name, ok := strings.CutPrefix(path, CollectionPath)
+	if !ok || strings.Contains(name, "/") {
+		return KindUnknown, ""
+	}
+	name, ok = strings.CutSuffix(name, ".ics")
+	if !ok || name == "" || len(name) > maxResourceName {
+		return KindUnknown, ""
+	}
+	return KindResource, name
+}
+
+// ResolveHref obtiene el nombre del recurso al que apunta un DAV:href, que puede ser una ruta o una URL absoluta
+func ResolveHref(href string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", false
+	}
+	kind, name := Resolve(parsed.Path)
+	return name, kind == KindResource
+}
+
+// ResourceName devuelve el nombre del recurso de una tarea: el que eligió el cliente que la
+// creó o, si se creó desde la API, uno derivado de su ID
+func ResourceName(id uint, clientID *string) string {
+	if clientID != nil {
+		return *clientID
+	}
+	return "task-" + strconv.FormatUint(uint64(id), 10)
+}
+
+// ResourceHref devuelve la ruta del recurso de una tarea
+func ResourceHref(id uint, clientID *string) string {
+	return CollectionPath + url.PathEscape(ResourceName(id, clientID)) + ".ics"
+}
+
+// TaskIDFromName obtiene el ID de una tarea creada desde la API a partir del nombre de su recurso
+func TaskIDFromName(name string) (uint, bool) {
+	digits, ok := strings.CutPrefix(name, "task-")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil || id == 0 || strconv.FormatUint(id, 10) != digits {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// Calendar serializa una tarea como un VCALENDAR con una sola VTODO
+func Calendar(task *domain.Task) string {
+	var buf bytes.Buffer
+	writer := ical.NewWriter(&buf)
+	exporter.BeginCalendar(writer, CollectionName)
+	exporter.WriteTodo(writer, task)
+	writer.End("VCALENDAR")
+	return buf.String()
+}
+
+// Todo contiene los campos de una VTODO que las tareas soportan. Los demás (DUE, PRIORITY,
+// DESCRIPTION, RRULE...) se ignoran.
+type Todo struct {
+	UID       string
+	Title     string
+	Completed bool
+}
+
+// ParseTodo lee un recurso de calendario con una única VTODO
+func ParseTodo(data []byte) (*Todo, error) {
+	calendar, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTodo, err)
+	}
+	if calendar.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("%w: el recurso debe ser un VCALENDAR", ErrInvalidTodo)
+	}
+
+	var todo *ical.Component
+	for _, component := range calendar.Components {
+		switch component.Name {
+		case "VTIMEZONE":
+			// Las zonas horarias acompañan a las fechas, que no se guardan
+		case "VTODO":
+			if todo != nil && !sameUID(todo, component) {
+				return nil, fmt.Errorf("%w: el recurso contiene más de una tarea", ErrInvalidTodo)
+			}
+			// Las excepciones de una tarea recurrente comparten el UID; se usa la primera instancia
+			if todo == nil {
+				todo = component
+			}
+		default:
+			return nil, ErrUnsupportedComponent
+		}
+	}
+	if todo == nil {
+		return nil, ErrUnsupportedComponent
+	}
+
+	result := &Todo{}
+	if uid := todo.Get("UID"); uid != nil {
+		result.UID = uid.Text()
+	}
+	if summary := todo.Get("SUMMARY"); summary != nil {
+		result.Title = strings.TrimSpace(summary.Text())
+	}
+	if result.Title == "" {
+		return nil, fmt.Errorf("%w: SUMMARY es requerido", ErrInvalidTodo)
+	}
+	if utf8.RuneCountInString(result.Title) > 200 {
+		return nil, fmt.Errorf("%w: SUMMARY debe tener como máximo 200 caracteres", ErrInvalidTodo)
+	}
+
+	if status := todo.Get("STATUS"); status != nil {
+		result.Completed = strings.EqualFold(status.Value, "COMPLETED")
+	} else {
+		result.Completed = todo.Get("COMPLETED") != nil
+	}
+	return result, nil
+}
+
+// sameUID indica si dos componentes tienen el mismo UID
+func sameUID(a, b *ical.Component) bool {
+	uidA, uidB := a.Get("UID"), b.Get("UID")
+	return uidA != nil && uidB != nil && uidA.Value == uidB.Value
+}
+
+// Match evalúa el filtro de un calendar-query sobre el VCALENDAR de una tarea.
+// Los rangos de tiempo no se evalúan: las tareas no tienen fechas de inicio ni vencimiento,
+// así que se consideran dentro de cualquier rango.
+func Match(filter *CompFilter, calendar *ical.Component) bool {
+	if filter == nil {
+		return true
+	}
+	return matchComponent(filter, []*ical.Component{calendar})
+}
+
+// matchComponent evalúa un comp-filter sobre los componentes con el nombre del filtro
+func matchComponent(filter *CompFilter, candidates []*ical.Component) bool {
+	var matching []*ical.Component
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.Name, filter.Name) {
+			matching = append(matching, candidate)
+		}
+	}
+	if filter.IsNotDefined != nil {
+		return len(matching) == 0
+	}
+
+	for _, component := range matching {
+		if matchFilters(filter, component) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilters indica si un componente cumple todos los filtros anidados de un comp-filter
+func matchFilters(filter *CompFilter, component *ical.Component) bool {
+	for i := range filter.CompFilters {
+		if !matchComponent(&filter.CompFilters[i], component.Components) {
+			return false
+		}
+	}
+	for i := range filter.PropFilters {
+		if !matchProperty(&filter.PropFilters[i], component) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchProperty evalúa un prop-filter. text-match usa la comparación i;ascii-casemap por subcadena.
+func matchProperty(filter *PropFilter, component *ical.Component) bool {
+	prop := component.Get(filter.Name)
+	if filter.IsNotDefined != nil {
+		return prop == nil
+	}
+	if prop == nil {
+		return false
+	}
+	if filter.TextMatch == nil {
+		return true
+	}
+
+	contains := strings.Contains(strings.ToLower(prop.Text()), strings.ToLower(filter.TextMatch.Value))
+	if strings.EqualFold(filter.TextMatch.NegateCondition, "yes") {
+		return !contains
+	}
+	return contains
+}
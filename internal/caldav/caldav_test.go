@@ -0,0 +1,160 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantKind int
+		wantName string
+	}{
+		{"/caldav/", KindRoot, ""},
+		{"/caldav", KindRoot, ""},
+		{"/caldav/principal/", KindPrincipal, ""},
+		{"/caldav/calendars", KindHome, ""},
+		{"/caldav/calendars/tasks/", KindCollection, ""},
+		{"/caldav/calendars/tasks/task-3.ics", KindResource, "task-3"},
+		{"/caldav/calendars/tasks/abc-123.ics", KindResource, "abc-123"},
+		{"/caldav/calendars/tasks/abc.txt", KindUnknown, ""},
+		{"/caldav/calendars/tasks/.ics", KindUnknown, ""},
+		{"/caldav/calendars/tasks/a/b.ics", KindUnknown, ""},
+		{"/caldav/calendars/tasks/" + strings.Repeat("a", maxResourceName+1) + ".ics", KindUnknown, ""},
+		{"/caldav/otra/", KindUnknown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			kind, name := Resolve(tt.path)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestResolveHref(t *testing.T) {
+	name, ok := ResolveHref(" https://example.com/caldav/calendars/tasks/task-7.ics ")
+	assert.True(t, ok)
+	assert.Equal(t, "task-7", name)
+
+	_, ok = ResolveHref("/caldav/calendars/tasks/")
+	assert.False(t, ok)
+}
+
+func TestResourceNames(t *testing.T) {
+	clientID := "a b"
+	assert.Equal(t, "task-5", ResourceName(5, nil))
+	assert.Equal(t, "a b", ResourceName(5, &clientID))
+	assert.Equal(t, "/caldav/calendars/tasks/task-5.ics", ResourceHref(5, nil))
+	assert.Equal(t, "/caldav/calendars/tasks/a%20b.ics", ResourceHref(5, &clientID))
+
+	tests := []struct {
+		name   string
+		wantID uint
+		wantOK bool
+	}{
+		{"task-5", 5, true},
+		{"task-05", 0, false},
+		{"task-0", 0, false},
+		{"task-", 0, false},
+		{"task-x", 0, false},
+		{"task-99999999999", 0, false},
+		{"otra-5", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := TaskIDFromName(tt.name)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+// Una tarea serializada con Calendar se vuelve a leer con ParseTodo
+func TestCalendarRoundTrip(t *testing.T) {
+	for _, completed := range []bool{false, true} {
+		task := &domain.Task{ID: 9, Title: "Revisar; presupuesto, " + strings.Repeat("ñ", 80), Completed: completed, Version: 2}
+
+		todo, err := ParseTodo([]byte(Calendar(task)))
+		require.NoError(t, err)
+		assert.Equal(t, "task-9@gin-tasks-api", todo.UID)
+		assert.Equal(t, task.Title, todo.Title)
+		assert.Equal(t, completed, todo.Completed)
+	}
+}
+
+func TestParseTodo(t *testing.T) {
+	calendar := func(components ...string) []byte {
+		return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(components, "") + "END:VCALENDAR\r\n")
+	}
+	vtodo := func(props ...string) string {
+		return "BEGIN:VTODO\r\n" + strings.Join(props, "\r\n") + "\r\nEND:VTODO\r\n"
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *Todo
+		wantErr error
+	}{
+		{"pendiente", calendar(vtodo("UID:abc", "SUMMARY: Comprar pan ")), &Todo{UID: "abc", Title: "Comprar pan"}, nil},
+		{"STATUS COMPLETED", calendar(vtodo("UID:abc", "SUMMARY:x", "STATUS:completed")), &Todo{UID: "abc", Title: "x", Completed: true}, nil},
+		{"STATUS tiene prioridad", calendar(vtodo("SUMMARY:x", "STATUS:NEEDS-ACTION", "COMPLETED:20240101T000000Z")), &Todo{Title: "x"}, nil},
+		{"solo COMPLETED", calendar(vtodo("SUMMARY:x", "COMPLETED:20240101T000000Z")), &Todo{Title: "x", Completed: true}, nil},
+		{"con VTIMEZONE", calendar("BEGIN:VTIMEZONE\r\nTZID:Europe/Madrid\r\nEND:VTIMEZONE\r\n", vtodo("SUMMARY:x")), &Todo{Title: "x"}, nil},
+		{"excepciones con el mismo UID", calendar(vtodo("UID:a", "SUMMARY:primera"), vtodo("UID:a", "SUMMARY:segunda")), &Todo{UID: "a", Title: "primera"}, nil},
+		{"dos tareas", calendar(vtodo("UID:a", "SUMMARY:x"), vtodo("UID:b", "SUMMARY:y")), nil, ErrInvalidTodo},
+		{"sin SUMMARY", calendar(vtodo("UID:a")), nil, ErrInvalidTodo},
+		{"SUMMARY demasiado largo", calendar(vtodo("SUMMARY:" + strings.Repeat("a", 201))), nil, ErrInvalidTodo},
+		{"no es VCALENDAR", []byte(vtodo("SUMMARY:x")), nil, ErrInvalidTodo},
+		{"iCalendar inválido", []byte("BEGIN:VCALENDAR\r\n"), nil, ErrInvalidTodo},
+		{"VEVENT", calendar("BEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VEVENT\r\n"), nil, ErrUnsupportedComponent},
+		{"sin componentes", calendar(), nil, ErrUnsupportedComponent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := ParseTodo(tt.data)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, todo)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	calendar, err := ical.Parse(strings.NewReader(Calendar(&domain.Task{ID: 1, Title: "Comprar Pan"})))
+	require.NoError(t, err)
+
+	todoFilter := func(filters ...PropFilter) *CompFilter {
+		return &CompFilter{Name: "VCALENDAR", CompFilters: []CompFilter{{Name: "VTODO", PropFilters: filters}}}
+	}
+	tests := []struct {
+		name   string
+		filter *CompFilter
+		want   bool
+	}{
+		{"sin filtro", nil, true},
+		{"VTODO", todoFilter(), true},
+		{"VEVENT", &CompFilter{Name: "VCALENDAR", CompFilters: []CompFilter{{Name: "VEVENT"}}}, false},
+		{"sin VEVENT", &CompFilter{Name: "VCALENDAR", CompFilters: []CompFilter{{Name: "VEVENT", IsNotDefined: &struct{}{}}}}, true},
+		{"text-match sin mayúsculas", todoFilter(PropFilter{Name: "SUMMARY", TextMatch: &TextMatch{Value: "pan"}}), true},
+		{"text-match negado", todoFilter(PropFilter{Name: "SUMMARY", TextMatch: &TextMatch{Value: "pan", NegateCondition: "yes"}}), false},
+		{"propiedad existente", todoFilter(PropFilter{Name: "STATUS"}), true},
+		{"propiedad inexistente", todoFilter(PropFilter{Name: "DUE"}), false},
+		{"COMPLETED no definido", todoFilter(PropFilter{Name: "COMPLETED", IsNotDefined: &struct{}{}}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.filter, calendar))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package caldav_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/app"
+	"github.com/alexroel/gin-tasks-api/internal/caldav"
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/alexroel/gin-tasks-api/internal/handler"
+	"github.com/alexroel/gin-tasks-api/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	email    = "ana@example.com"
+	password = "secreto123"
+)
+
+// server levanta la aplicación con una base de datos SQLite temporal, registra un usuario y
+// le crea una contraseña de aplicación. Devuelve el router, el JWT y la contraseña.
+func server(t *testing.T) (http.Handler, string, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GIN_MODE", gin.TestMode)
+	t.Setenv("JWT_SECRET", "secreto-de-pruebas-de-caldav")
+	t.Setenv("DB_DRIVER", config.DriverSQLite)
+	t.Setenv("URL_DATABASE", filepath.Join(t.TempDir(), "tasks.db"))
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	db := testutil.NewDB(t, cfg)
+	application, err := app.New(cfg, db)
+	require.NoError(t, err)
+	router := application.Router()
+
+	credentials := `{"full_name":"Ana Pérez","email":"` + email + `","password":"` + password + `"}`
+	w := serve(router, http.MethodPost, "/api/auth/signup", credentials, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = serve(router, http.MethodPost, "/api/auth/login", credentials, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var login struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+
+	w = serve(router, http.MethodPost, "/api/auth/app-passwords", `{"name":"Thunderbird"}`,
+		map[string]string{"Authorization": "Bearer " + login.Data.Token})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var appPassword struct {
+		Data struct {
+			Password string `json:"password"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &appPassword))
+	require.NotEmpty(t, appPassword.Data.Password)
+
+	return router, login.Data.Token, appPassword.Data.Password
+}
+
+func serve(router http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// dav envía una petición CalDAV autenticada con la contraseña de aplicación
+func dav(router http.Handler, appPassword, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetBasicAuth(email, appPassword)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func todo(uid, summary string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:" + uid + "\r\nSUMMARY:" + summary + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+}
+
+// Solo las contraseñas de aplicación sirven para CalDAV; la contraseña de la cuenta y el JWT no
+func TestBasicAuth(t *testing.T) {
+	router, token, appPassword := server(t)
+
+	tests := []struct {
+		name       string
+		auth       func(req *http.Request)
+		wantStatus int
+	}{
+		{"sin credenciales", func(req *http.Request) {}, http.StatusUnauthorized},
+		{"JWT", func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }, http.StatusUnauthorized},
+		{"contraseña de la cuenta", func(req *http.Request) { req.SetBasicAuth(email, password) }, http.StatusUnauthorized},
+		{"contraseña incorrecta", func(req *http.Request) { req.SetBasicAuth(email, appPassword+"x") }, http.StatusUnauthorized},
+		{"otro usuario", func(req *http.Request) { req.SetBasicAuth("otro@example.com", appPassword) }, http.StatusUnauthorized},
+		{"contraseña de aplicación", func(req *http.Request) { req.SetBasicAuth(email, appPassword) }, http.StatusMultiStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(handler.MethodPropfind, caldav.PrincipalPath, nil)
+			req.Header.Set("Depth", "0")
+			tt.auth(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Basic realm=")
+			}
+		})
+	}
+
+	// OPTIONS no requiere autenticación para que los clientes descubran el servidor
+	w := serve(router, http.MethodOptions, caldav.RootPath, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("DAV"), "calendar-access")
+}
+
+func TestPropfindAndReport(t *testing.T) {
+	router, _, appPassword := server(t)
+	href := caldav.CollectionPath + "compra.ics"
+
+	w := dav(router, appPassword, http.MethodPut, href, todo("compra", "Comprar pan"), map[string]string{"If-None-Match": "*"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = dav(router, appPassword, handler.MethodPropfind, caldav.CollectionPath,
+		`<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getetag/><d:no-existe/></d:prop></d:propfind>`,
+		map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	body := w.Body.String()
+	assert.Contains(t, body, "<d:href>"+caldav.CollectionPath+"</d:href>")
+	assert.Contains(t, body, "<c:calendar/>")
+	assert.Contains(t, body, "<d:href>"+href+"</d:href>")
+	assert.Contains(t, body, "<d:getetag>")
+	assert.Contains(t, body, "<d:no-existe/>")
+	assert.Contains(t, body, "HTTP/1.1 404 Not Found")
+
+	w = dav(router, appPassword, handler.MethodPropfind, caldav.CollectionPath, `<d:propfind xmlns:d="DAV:">`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = dav(router, appPassword, handler.MethodReport, caldav.CollectionPath, `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <d:href>`+href+`</d:href>
+  <d:href>`+caldav.CollectionPath+`falta.ics</d:href>
+</c:calendar-multiget>`, map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	body = w.Body.String()
+	assert.Contains(t, body, "SUMMARY:Comprar pan")
+	assert.Contains(t, body, "<d:href>"+caldav.CollectionPath+"falta.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status>")
+
+	w = dav(router, appPassword, handler.MethodReport, caldav.CollectionPath, `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>
+</c:calendar-query>`, map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), href)
+
+	w = dav(router, appPassword, handler.MethodReport, caldav.CollectionPath, `<d:expand-property xmlns:d="DAV:"/>`, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// Volver a crear un recurso eliminado restaura la tarea con el contenido nuevo
+func TestPutRestoresDeletedResource(t *testing.T) {
+	router, _, appPassword := server(t)
+	href := caldav.CollectionPath + "compra.ics"
+
+	w := dav(router, appPassword, http.MethodPut, href, todo("compra", "Comprar pan"), nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = dav(router, appPassword, http.MethodDelete, href, "", nil)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = dav(router, appPassword, http.MethodGet, href, "", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = dav(router, appPassword, http.MethodPut, href, todo("compra", "Comprar pan integral"), map[string]string{"If-None-Match": "*"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = dav(router, appPassword, http.MethodGet, href, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	restored, err := caldav.ParseTodo(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, &caldav.Todo{UID: "compra", Title: "Comprar pan integral"}, restored)
+}
@@ -0,0 +1,333 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Espacios de nombres XML de WebDAV, CalDAV y las extensiones de Apple
+const (
+	NSDAV            = "DAV:"
+	NSCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NSCalendarServer = "http://calendarserver.org/ns/"
+)
+
+// prefixes asigna el prefijo con el que se escribe cada espacio de nombres conocido
+var prefixes = map[string]string{
+	NSDAV:            "d",
+	NSCalDAV:         "c",
+	NSCalendarServer: "cs",
+}
+
+var ErrInvalidXML = errors.New("cuerpo XML inválido")
+
+// Property es una propiedad WebDAV con su valor ya serializado como XML
+type Property struct {
+	Name  xml.Name
+	Inner string
+}
+
+// Prop crea una propiedad cuyo valor es XML ya escapado
+func Prop(space, local, inner string) Property {
+	return Property{Name: xml.Name{Space: space, Local: local}, Inner: inner}
+}
+
+// TextProp crea una propiedad cuyo valor es texto que se escapa al escribirlo
+func TextProp(space, local, text string) Property {
+	return Prop(space, local, escape(text))
+}
+
+// Href serializa un elemento DAV:href
+func Href(href string) string {
+	return "<d:href>" + escape(href) + "</d:href>"
+}
+
+// PropRequest representa las propiedades que pide un PROPFIND o un REPORT.
+// Con AllProp se devuelven todas las propiedades salvo las costosas, como calendar-data.
+type PropRequest struct {
+	AllProp  bool
+	PropName bool
+	Names    []xml.Name
+}
+
+// Wants indica si la petición incluye la propiedad
+func (r *PropRequest) Wants(space, local string) bool {
+	for _, name := range r.Names {
+		if name.Space == space && name.Local == local {
+			return true
+		}
+	}
+	return false
+}
+
+// propContainer lee los nombres de las propiedades de un elemento DAV:prop
+type propContainer struct {
+	Names []xml.Name
+}
+
+func (p *propContainer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			p.Names = append(p.Names, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// propSelection son los elementos con los que PROPFIND y los REPORT eligen propiedades
+type propSelection struct {
+	AllProp  *struct{}      `xml:"DAV: allprop"`
+	PropName *struct{}      `xml:"DAV: propname"`
+	Prop     *propContainer `xml:"DAV: prop"`
+}
+
+func (s *propSelection) request() PropRequest {
+	request := PropRequest{AllProp: s.AllProp != nil, PropName: s.PropName != nil}
+	if s.Prop != nil {
+		request.Names = s.Prop.Names
+	}
+	if !request.PropName && len(request.Names) == 0 {
+		request.AllProp = true
+	}
+	return request
+}
+
+// ParsePropfind interpreta el cuerpo de un PROPFIND. Un cuerpo vacío equivale a allprop.
+func ParsePropfind(body []byte) (*PropRequest, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &PropRequest{AllProp: true}, nil
+	}
+	var propfind struct {
+		XMLName xml.Name `xml:"DAV: propfind"`
+		propSelection
+	}
+	if err := xml.Unmarshal(body, &propfind); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXML, err)
+	}
+	request := propfind.request()
+	return &request, nil
+}
+
+// Tipos de REPORT soportados
+const (
+	ReportCalendarQuery    = "calendar-query"
+	ReportCalendarMultiget = "calendar-multiget"
+	ReportSyncCollection   = "sync-collection"
+)
+
+var ErrUnsupportedReport = errors.New("tipo de REPORT no soportado")
+
+// Report representa un REPORT de CalDAV o WebDAV Sync
+type Report struct {
+	Type   string
+	Props  PropRequest
+	Filter *CompFilter
+	Hrefs  []string
+	// SyncToken está vacío en la primera sincronización
+	SyncToken string
+	// Limit es la cantidad máxima de resultados de un sync-collection, o 0 si no se indicó
+	Limit int
+}
+
+// CompFilter es un filtro de componente de calendar-query
+type CompFilter struct {
+	Name         string       `xml:"name,attr"`
+	IsNotDefined *struct{}    `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *struct{}    `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters  []CompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	PropFilters  []PropFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+}
+
+// PropFilter es un filtro de propiedad de calendar-query
+type PropFilter struct {
+	Name         string     `xml:"name,attr"`
+	IsNotDefined *struct{}  `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TextMatch    *TextMatch `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+}
+
+// TextMatch compara el valor de una propiedad con un texto
+type TextMatch struct {
+	Value           string `xml:",chardata"`
+	NegateCondition string `xml:"negate-condition,attr"`
+}
+
+// ParseReport interpreta el cuerpo de un REPORT
+func ParseReport(body []byte) (*Report, error) {
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	switch root {
+	case xml.Name{Space: NSCalDAV, Local: ReportCalendarQuery}:
+		var query struct {
+			propSelection
+			Filter *CompFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+		}
+		if err := xml.Unmarshal(body, &query); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXML, err)
+		}
+		report.Type = ReportCalendarQuery
+		report.Props = query.request()
+		report.Filter = query.Filter
+	case xml.Name{Space: NSCalDAV, Local: ReportCalendarMultiget}:
+		var multiget struct {
+			propSelection
+			Hrefs []string `xml:"DAV: href"`
+		}
+		if err := xml.Unmarshal(body, &multiget); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXML, err)
+		}
+		report.Type = ReportCalendarMultiget
+		report.Props = multiget.request()
+		report.Hrefs = multiget.Hrefs
+	case xml.Name{Space: NSDAV, Local: ReportSyncCollection}:
+		var sync struct {
+			propSelection
+			SyncToken string `xml:"DAV: sync-token"`
+			NResults  int    `xml:"DAV: limit>nresults"`
+		}
+		if err := xml.Unmarshal(body, &sync); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXML, err)
+		}
+		report.Type = ReportSyncCollection
+		report.Props = sync.request()
+		report.SyncToken = strings.TrimSpace(sync.SyncToken)
+		report.Limit = sync.NResults
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedReport, root.Local)
+	}
+	return report, nil
+}
+
+// rootElement obtiene el nombre del elemento raíz de un documento XML
+func rootElement(body []byte) (xml.Name, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return xml.Name{}, fmt.Errorf("%w: documento vacío", ErrInvalidXML)
+		}
+		if err != nil {
+			return xml.Name{}, fmt.Errorf("%w: %v", ErrInvalidXML, err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
+// Multistatus construye una respuesta 207 Multi-Status
+type Multistatus struct {
+	buf bytes.Buffer
+}
+
+// NewMultistatus crea una respuesta Multi-Status vacía
+func NewMultistatus() *Multistatus {
+	m := &Multistatus{}
+	m.buf.WriteString(xml.Header)
+	m.buf.WriteString(`<d:multistatus`)
+	for _, space := range []string{NSDAV, NSCalDAV, NSCalendarServer} {
+		fmt.Fprintf(&m.buf, ` xmlns:%s="%s"`, prefixes[space], space)
+	}
+	m.buf.WriteString(">")
+	return m
+}
+
+// Response agrega un recurso con las propiedades encontradas (200) y las que no existen (404)
+func (m *Multistatus) Response(href string, found []Property, missing []xml.Name) {
+	m.buf.WriteString("<d:response>")
+	m.buf.WriteString(Href(href))
+	if len(found) > 0 {
+		m.buf.WriteString("<d:propstat><d:prop>")
+		for _, prop := range found {
+			writeElement(&m.buf, prop.Name, prop.Inner)
+		}
+		m.buf.WriteString("</d:prop>")
+		m.buf.WriteString(statusLine(http.StatusOK))
+		m.buf.WriteString("</d:propstat>")
+	}
+	if len(missing) > 0 {
+		m.buf.WriteString("<d:propstat><d:prop>")
+		for _, name := range missing {
+			writeElement(&m.buf, name, "")
+		}
+		m.buf.WriteString("</d:prop>")
+		m.buf.WriteString(statusLine(http.StatusNotFound))
+		m.buf.WriteString("</d:propstat>")
+	}
+	m.buf.WriteString("</d:response>")
+}
+
+// Status agrega un recurso con un estado y sin propiedades, por ejemplo 404 para un recurso eliminado
+func (m *Multistatus) Status(href string, status int) {
+	m.buf.WriteString("<d:response>")
+	m.buf.WriteString(Href(href))
+	m.buf.WriteString(statusLine(status))
+	m.buf.WriteString("</d:response>")
+}
+
+// SyncToken agrega el token de sincronización de un sync-collection
+func (m *Multistatus) SyncToken(token string) {
+	m.buf.WriteString("<d:sync-token>" + escape(token) + "</d:sync-token>")
+}
+
+// Bytes cierra la respuesta y devuelve el documento
+func (m *Multistatus) Bytes() []byte {
+	m.buf.WriteString("</d:multistatus>")
+	return m.buf.Bytes()
+}
+
+// ErrorBody construye el cuerpo DAV:error de una precondición que no se cumplió,
+// por ejemplo valid-sync-token o supported-calendar-component
+func ErrorBody(space, condition string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	writeElement(&buf, xml.Name{Space: space, Local: condition}, "")
+	buf.WriteString("</d:error>")
+	return buf.Bytes()
+}
+
+// writeElement escribe un elemento con el prefijo de su espacio de nombres
+func writeElement(buf *bytes.Buffer, name xml.Name, inner string) {
+	tag := name.Local
+	attrs := ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		attrs = ` xmlns="` + escape(name.Space) + `"`
+	}
+	if inner == "" {
+		buf.WriteString("<" + tag + attrs + "/>")
+		return
+	}
+	buf.WriteString("<" + tag + attrs + ">" + inner + "</" + tag + ">")
+}
+
+// statusLine serializa el estado HTTP de un elemento DAV:status
+func statusLine(status int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+// escape escapa un texto para incluirlo en el XML
+func escape(text string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
@@ -0,0 +1,180 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePropfind(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want PropRequest
+	}{
+		{"cuerpo vacío", " \n", PropRequest{AllProp: true}},
+		{"allprop", `<d:propfind xmlns:d="DAV:"><d:allprop/></d:propfind>`, PropRequest{AllProp: true}},
+		{"propname", `<propfind xmlns="DAV:"><propname/></propfind>`, PropRequest{PropName: true}},
+		{"prop", `<?xml version="1.0"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:displayname/><c:calendar-home-set/><d:resourcetype><d:collection/></d:resourcetype></d:prop>
+</d:propfind>`, PropRequest{Names: []xml.Name{
+			{Space: NSDAV, Local: "displayname"},
+			{Space: NSCalDAV, Local: "calendar-home-set"},
+			{Space: NSDAV, Local: "resourcetype"},
+		}}},
+		{"prop vacío", `<d:propfind xmlns:d="DAV:"><d:prop/></d:propfind>`, PropRequest{AllProp: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := ParsePropfind([]byte(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *request)
+		})
+	}
+
+	request, err := ParsePropfind([]byte(`<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`))
+	require.NoError(t, err)
+	assert.True(t, request.Wants(NSDAV, "getetag"))
+	assert.False(t, request.Wants(NSCalDAV, "getetag"))
+
+	for _, body := range []string{`<d:propfind xmlns:d="DAV:">`, `<propfind/>`, `texto`} {
+		_, err := ParsePropfind([]byte(body))
+		assert.ErrorIs(t, err, ErrInvalidXML, body)
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Report
+	}{
+		{"calendar-query", `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO">
+        <c:prop-filter name="SUMMARY"><c:text-match negate-condition="yes">pan</c:text-match></c:prop-filter>
+        <c:prop-filter name="COMPLETED"><c:is-not-defined/></c:prop-filter>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`, Report{
+			Type:  ReportCalendarQuery,
+			Props: PropRequest{Names: []xml.Name{{Space: NSDAV, Local: "getetag"}, {Space: NSCalDAV, Local: "calendar-data"}}},
+			Filter: &CompFilter{Name: "VCALENDAR", CompFilters: []CompFilter{{Name: "VTODO", PropFilters: []PropFilter{
+				{Name: "SUMMARY", TextMatch: &TextMatch{Value: "pan", NegateCondition: "yes"}},
+				{Name: "COMPLETED", IsNotDefined: &struct{}{}},
+			}}}},
+		}},
+		{"calendar-multiget", `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/></d:prop>
+  <d:href>/caldav/calendars/tasks/task-1.ics</d:href>
+  <d:href>/caldav/calendars/tasks/abc.ics</d:href>
+</c:calendar-multiget>`, Report{
+			Type:  ReportCalendarMultiget,
+			Props: PropRequest{Names: []xml.Name{{Space: NSDAV, Local: "getetag"}}},
+			Hrefs: []string{"/caldav/calendars/tasks/task-1.ics", "/caldav/calendars/tasks/abc.ics"},
+		}},
+		{"sync-collection", `<d:sync-collection xmlns:d="DAV:">
+  <d:sync-token> urn:gin-tasks-api:sync:abc </d:sync-token>
+  <d:sync-level>1</d:sync-level>
+  <d:limit><d:nresults>50</d:nresults></d:limit>
+  <d:prop><d:getetag/></d:prop>
+</d:sync-collection>`, Report{
+			Type:      ReportSyncCollection,
+			Props:     PropRequest{Names: []xml.Name{{Space: NSDAV, Local: "getetag"}}},
+			SyncToken: "urn:gin-tasks-api:sync:abc",
+			Limit:     50,
+		}},
+		{"sync-collection inicial", `<d:sync-collection xmlns:d="DAV:"><d:sync-token/><d:allprop/></d:sync-collection>`, Report{
+			Type:  ReportSyncCollection,
+			Props: PropRequest{AllProp: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := ParseReport([]byte(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *report)
+		})
+	}
+}
+
+func TestParseReportErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"vacío", "", ErrInvalidXML},
+		{"XML mal formado", `<c:calendar-query xmlns:c="urn:ietf:params:xml:ns:caldav"><c:filter>`, ErrInvalidXML},
+		{"tipo desconocido", `<d:expand-property xmlns:d="DAV:"/>`, ErrUnsupportedReport},
+		{"espacio de nombres incorrecto", `<d:calendar-query xmlns:d="DAV:"/>`, ErrUnsupportedReport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseReport([]byte(tt.body))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// La respuesta Multi-Status es XML válido y separa las propiedades encontradas de las que no existen
+func TestMultistatus(t *testing.T) {
+	m := NewMultistatus()
+	m.Response("/caldav/calendars/tasks/", []Property{
+		TextProp(NSDAV, "displayname", "Tareas <mías>"),
+		Prop(NSDAV, "resourcetype", "<d:collection/>"),
+		TextProp("urn:example", "color", "#fff"),
+	}, []xml.Name{{Space: NSCalDAV, Local: "calendar-description"}})
+	m.Status("/caldav/calendars/tasks/task-1.ics", http.StatusNotFound)
+	m.SyncToken("urn:gin-tasks-api:sync:1")
+	body := m.Bytes()
+
+	var doc struct {
+		XMLName   xml.Name `xml:"DAV: multistatus"`
+		Responses []struct {
+			Href      string `xml:"DAV: href"`
+			Status    string `xml:"DAV: status"`
+			Propstats []struct {
+				Prop struct {
+					Inner string `xml:",innerxml"`
+				} `xml:"DAV: prop"`
+				Status string `xml:"DAV: status"`
+			} `xml:"DAV: propstat"`
+		} `xml:"DAV: response"`
+		SyncToken string `xml:"DAV: sync-token"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &doc), string(body))
+
+	require.Len(t, doc.Responses, 2)
+	collection := doc.Responses[0]
+	assert.Equal(t, "/caldav/calendars/tasks/", collection.Href)
+	require.Len(t, collection.Propstats, 2)
+	assert.Equal(t, "HTTP/1.1 200 OK", collection.Propstats[0].Status)
+	assert.Equal(t, `<d:displayname>Tareas &lt;mías&gt;</d:displayname><d:resourcetype><d:collection/></d:resourcetype><color xmlns="urn:example">#fff</color>`,
+		collection.Propstats[0].Prop.Inner)
+	assert.Equal(t, "HTTP/1.1 404 Not Found", collection.Propstats[1].Status)
+	assert.Equal(t, "<c:calendar-description/>", collection.Propstats[1].Prop.Inner)
+
+	assert.Equal(t, "/caldav/calendars/tasks/task-1.ics", doc.Responses[1].Href)
+	assert.Equal(t, "HTTP/1.1 404 Not Found", doc.Responses[1].Status)
+	assert.Empty(t, doc.Responses[1].Propstats)
+	assert.Equal(t, "urn:gin-tasks-api:sync:1", doc.SyncToken)
+}
+
+func TestErrorBody(t *testing.T) {
+	var doc struct {
+		XMLName   xml.Name `xml:"DAV: error"`
+		Condition struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	}
+	require.NoError(t, xml.Unmarshal(ErrorBody(NSCalDAV, "valid-calendar-data"), &doc))
+	assert.Equal(t, xml.Name{Space: NSCalDAV, Local: "valid-calendar-data"}, doc.Condition.XMLName)
+}
@@ -0,0 +1,333 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/events"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait es el tiempo máximo para escribir un mensaje
+	writeWait = 10 * time.Second
+	// pongWait es el tiempo máximo sin recibir un pong antes de cerrar la conexión
+	pongWait = 60 * time.Second
+	// pingPeriod es la frecuencia de los pings; debe ser menor que pongWait
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize es el tamaño máximo de un mensaje del cliente
+	maxMessageSize = 64 * 1024
+	// sendBuffer es la cantidad de mensajes pendientes que admite un cliente lento antes de desconectarlo
+	sendBuffer = 64
+	// maxChannels es la cantidad máxima de canales a los que se puede suscribir una conexión
+	maxChannels = 50
+)
+
+// Client representa una conexión WebSocket de un usuario autenticado
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	tasks  service.TaskService
+	events *events.Hub
+	userID uint
+	email  string
+
+	outbox    chan Outbound
+	done      chan struct{}
+	closeOnce sync.Once
+	channels  map[string]struct{}
+}
+
+// NewClient crea un cliente para una conexión ya establecida
+func NewClient(hub *Hub, conn *websocket.Conn, tasks service.TaskService, eventsHub *events.Hub, userID uint, email string) *Client {
+	return &Client{
+		hub:      hub,
+		conn:     conn,
+		tasks:    tasks,
+		events:   eventsHub,
+		userID:   userID,
+		email:    email,
+		outbox:   make(chan Outbound, sendBuffer),
+		done:     make(chan struct{}),
+		channels: make(map[string]struct{}),
+	}
+}
+
+// Run atiende la conexión hasta que el cliente se desconecta o el contexto se cancela
+func (c *Client) Run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Los cambios de tareas del usuario se reenvían como en el stream SSE
+	sub, _, _ := c.events.Subscribe(c.userID, "")
+	defer c.events.Unsubscribe(sub)
+
+	go c.writePump(ctx, sub)
+	c.readPump(ctx)
+
+	// Desconexión: abandonar los canales para actualizar la presencia
+	for channel := range c.channels {
+		c.hub.leave(c, channel)
+	}
+	c.close()
+}
+
+// send encola un mensaje. Si el cliente no consume sus mensajes a tiempo se desconecta.
+func (c *Client) send(message Outbound) {
+	select {
+	case <-c.done:
+	case c.outbox <- message:
+	default:
+		log.Printf("Cliente WebSocket lento desconectado (usuario %d)\n", c.userID)
+		c.close()
+	}
+}
+
+// close marca la conexión como terminada; writePump envía el cierre y libera la conexión
+func (c *Client) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// readPump lee los mensajes del cliente y los procesa en orden
+func (c *Client) readPump(ctx context.Context) {
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var message Inbound
+		if err := c.conn.ReadJSON(&message); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.send(Outbound{Type: TypeError, Code: CodeInvalid, Error: "mensaje JSON inválido"})
+				continue
+			}
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Println("Error al leer del WebSocket:", err)
+			}
+			return
+		}
+
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		c.handle(ctx, message)
+	}
+}
+
+// writePump escribe los mensajes encolados, los eventos de tareas y los pings
+func (c *Client) writePump(ctx context.Context, sub *events.Subscription) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.writeClose(websocket.CloseGoingAway, "el servidor cerró la conexión")
+			return
+		case <-c.done:
+			c.writeClose(websocket.CloseTryAgainLater, "conexión cerrada")
+			return
+		case message := <-c.outbox:
+			if !c.write(message) {
+				c.close()
+				return
+			}
+		case event, open := <-sub.Events():
+			if !open {
+				c.writeClose(websocket.CloseTryAgainLater, "demasiados eventos pendientes")
+				c.close()
+				return
+			}
+			if !c.write(Outbound{Type: TypeEvent, Event: event.Type, Data: event.Data}) {
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// write envía un mensaje respetando el tiempo máximo de escritura
+func (c *Client) write(message Outbound) bool {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteJSON(message) == nil
+}
+
+// writeClose envía el mensaje de cierre del protocolo WebSocket
+func (c *Client) writeClose(code int, reason string) {
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
+
+// handle procesa un mensaje del cliente
+func (c *Client) handle(ctx context.Context, message Inbound) {
+	switch message.Type {
+	case TypeSubscribe:
+		c.subscribe(ctx, message)
+	case TypeUnsubscribe:
+		if _, exists := c.channels[message.Channel]; exists {
+			delete(c.channels, message.Channel)
+			c.hub.leave(c, message.Channel)
+		}
+		c.send(Outbound{Type: TypeUnsubscribed, ID: message.ID, Channel: message.Channel})
+	case TypeTyping:
+		if _, exists := c.channels[message.Channel]; !exists {
+			c.sendError(message.ID, CodeInvalid, "no estás suscrito al canal "+message.Channel)
+			return
+		}
+		c.hub.broadcast(message.Channel, Outbound{
+			Type:    TypeTyping,
+			Channel: message.Channel,
+			UserID:  c.userID,
+			Data:    message.Data,
+		}, c)
+	case TypeMutation:
+		c.mutate(ctx, message)
+	case TypePing:
+		c.send(Outbound{Type: TypePong, ID: message.ID})
+	default:
+		c.sendError(message.ID, CodeInvalid, "tipo de mensaje desconocido: "+message.Type)
+	}
+}
+
+// subscribe suscribe la conexión al canal de una tarea si el usuario puede verla
+func (c *Client) subscribe(ctx context.Context, message Inbound) {
+	taskID, err := parseTaskChannel(message.Channel)
+	if err != nil {
+		c.sendError(message.ID, CodeInvalid, err.Error())
+		return
+	}
+	if _, exists := c.channels[message.Channel]; !exists && len(c.channels) >= maxChannels {
+		c.sendError(message.ID, CodeInvalid, "se alcanzó el máximo de canales por conexión")
+		return
+	}
+
+	task, err := c.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		c.sendServiceError(message.ID, err)
+		return
+	}
+	if task.UserID != c.userID {
+		c.sendServiceError(message.ID, service.ErrTaskUnauthorized)
+		return
+	}
+
+	c.channels[message.Channel] = struct{}{}
+	presence := c.hub.join(c, message.Channel)
+	c.send(Outbound{Type: TypeSubscribed, ID: message.ID, Channel: message.Channel, Presence: presence})
+}
+
+// mutate aplica una modificación de tarea a través de TaskService, con sus mismas validaciones y permisos
+func (c *Client) mutate(ctx context.Context, message Inbound) {
+	var (
+		task *domain.Task
+		err  error
+	)
+
+	switch message.Action {
+	case ActionCreate:
+		var req domain.CreateTask
+		if err := decodeData(message.Data, &req); err != nil {
+			c.sendError(message.ID, CodeInvalid, err.Error())
+			return
+		}
+		task, err = c.tasks.Create(ctx, c.userID, &req)
+	case ActionUpdate:
+		var req domain.UpdateTask
+		if err := decodeData(message.Data, &req); err != nil {
+			c.sendError(message.ID, CodeInvalid, err.Error())
+			return
+		}
+		task, err = c.tasks.Update(ctx, message.TaskID, c.userID, &req, message.Version)
+	case ActionStatus:
+		var req struct {
+			Completed bool `json:"completed"`
+		}
+		if err := decodeData(message.Data, &req); err != nil {
+			c.sendError(message.ID, CodeInvalid, err.Error())
+			return
+		}
+		task, err = c.tasks.UpdateStatus(ctx, message.TaskID, c.userID, req.Completed, message.Version)
+	case ActionMove:
+		var req domain.MoveTask
+		if err := decodeData(message.Data, &req); err != nil {
+			c.sendError(message.ID, CodeInvalid, err.Error())
+			return
+		}
+		task, err = c.tasks.Move(ctx, message.TaskID, c.userID, &req)
+	case ActionDelete:
+		err = c.tasks.Delete(ctx, message.TaskID, c.userID, message.Version)
+	default:
+		c.sendError(message.ID, CodeInvalid, "acción desconocida: "+message.Action)
+		return
+	}
+
+	if err != nil {
+		c.sendServiceError(message.ID, err)
+		return
+	}
+
+	result := Outbound{Type: TypeResult, ID: message.ID}
+	if task != nil {
+		result.Data = task.ToResponse()
+	}
+	c.send(result)
+}
+
+// sendError envía un error asociado a un mensaje del cliente
+func (c *Client) sendError(id, code, message string) {
+	c.send(Outbound{Type: TypeError, ID: id, Code: code, Error: message})
+}
+
+// sendServiceError traduce un error de TaskService a un código de error del protocolo
+func (c *Client) sendServiceError(id string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrMoveAnchorNotFound):
+		c.sendError(id, CodeNotFound, err.Error())
+	case errors.Is(err, service.ErrTaskUnauthorized):
+		c.sendError(id, CodeForbidden, err.Error())
+	case errors.Is(err, service.ErrPreconditionFailed):
+		c.sendError(id, CodePreconditionFailed, err.Error())
+	case errors.Is(err, service.ErrConcurrentModification):
+		c.sendError(id, CodeConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidMove):
+		c.sendError(id, CodeInvalid, err.Error())
+	default:
+		c.sendError(id, CodeInternal, "error al procesar la operación")
+		log.Println("Error en una operación del WebSocket:", err)
+	}
+}
+
+// decodeData decodifica los datos de una mutación y los valida con las mismas reglas que los handlers HTTP
+func decodeData(data json.RawMessage, target interface{}) error {
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return errors.New("datos inválidos: " + err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		return errors.New("datos inválidos: " + err.Error())
+	}
+	return nil
+}
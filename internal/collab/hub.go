@@ -0,0 +1,86 @@
+package collab
+
+import (
+	"sort"
+	"sync"
+)
+
+// Hub mantiene los canales de esta instancia y los clientes suscritos a cada uno.
+// La presencia y los indicadores de escritura son locales a la instancia.
+type Hub struct {
+	mu       sync.RWMutex
+	channels map[string]map[*Client]struct{}
+}
+
+// NewHub crea un Hub sin canales
+func NewHub() *Hub {
+	return &Hub{channels: make(map[string]map[*Client]struct{})}
+}
+
+// join agrega el cliente al canal y anuncia la nueva presencia
+func (h *Hub) join(client *Client, channel string) []Presence {
+	h.mu.Lock()
+	if h.channels[channel] == nil {
+		h.channels[channel] = make(map[*Client]struct{})
+	}
+	h.channels[channel][client] = struct{}{}
+	presence := h.presence(channel)
+	h.mu.Unlock()
+
+	h.broadcast(channel, Outbound{Type: TypePresence, Channel: channel, Presence: presence}, client)
+	return presence
+}
+
+// leave quita el cliente del canal y anuncia la nueva presencia
+func (h *Hub) leave(client *Client, channel string) {
+	h.mu.Lock()
+	clients, exists := h.channels[channel]
+	if !exists {
+		h.mu.Unlock()
+		return
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.channels, channel)
+	}
+	presence := h.presence(channel)
+	h.mu.Unlock()
+
+	h.broadcast(channel, Outbound{Type: TypePresence, Channel: channel, Presence: presence}, client)
+}
+
+// broadcast envía un mensaje a todos los clientes del canal excepto a except
+func (h *Hub) broadcast(channel string, message Outbound, except *Client) {
+	h.mu.RLock()
+	recipients := make([]*Client, 0, len(h.channels[channel]))
+	for client := range h.channels[channel] {
+		if client != except {
+			recipients = append(recipients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range recipients {
+		client.send(message)
+	}
+}
+
+// presence resume los usuarios conectados a un canal; debe llamarse con el bloqueo tomado
+func (h *Hub) presence(channel string) []Presence {
+	byUser := make(map[uint]*Presence)
+	for client := range h.channels[channel] {
+		entry, exists := byUser[client.userID]
+		if !exists {
+			entry = &Presence{UserID: client.userID, Email: client.email}
+			byUser[client.userID] = entry
+		}
+		entry.Connections++
+	}
+
+	presence := make([]Presence, 0, len(byUser))
+	for _, entry := range byUser {
+		presence = append(presence, *entry)
+	}
+	sort.Slice(presence, func(i, j int) bool { return presence[i].UserID < presence[j].UserID })
+	return presence
+}
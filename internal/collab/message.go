@@ -0,0 +1,95 @@
+// Package collab implementa el canal WebSocket de edición colaborativa de tareas:
+// presencia, indicadores de escritura y modificaciones de tareas en tiempo real.
+package collab
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tipos de mensaje enviados por el cliente
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeTyping      = "typing"
+	TypeMutation    = "mutation"
+	TypePing        = "ping"
+)
+
+// Tipos de mensaje enviados por el servidor
+const (
+	TypeSubscribed   = "subscribed"
+	TypeUnsubscribed = "unsubscribed"
+	TypePresence     = "presence"
+	TypeResult       = "result"
+	TypeError        = "error"
+	TypeEvent        = "event"
+	TypePong         = "pong"
+)
+
+// Acciones de las mutaciones de tareas
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionStatus = "status"
+	ActionDelete = "delete"
+	ActionMove   = "move"
+)
+
+// Códigos de error enviados al cliente
+const (
+	CodeInvalid            = "invalid"
+	CodeNotFound           = "not_found"
+	CodeForbidden          = "forbidden"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeInternal           = "internal"
+)
+
+// Inbound representa un mensaje recibido del cliente
+type Inbound struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Channel string          `json:"channel,omitempty"`
+	Action  string          `json:"action,omitempty"`
+	TaskID  uint            `json:"task_id,omitempty"`
+	Version uint            `json:"version,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Outbound representa un mensaje enviado al cliente
+type Outbound struct {
+	Type     string      `json:"type"`
+	ID       string      `json:"id,omitempty"`
+	Channel  string      `json:"channel,omitempty"`
+	Event    string      `json:"event,omitempty"`
+	UserID   uint        `json:"user_id,omitempty"`
+	Presence []Presence  `json:"presence,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Code     string      `json:"code,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Presence representa a un usuario que está viendo un canal
+type Presence struct {
+	UserID      uint   `json:"user_id"`
+	Email       string `json:"email"`
+	Connections int    `json:"connections"`
+}
+
+// taskChannelPrefix es el prefijo de los canales de tareas: "task:<id>"
+const taskChannelPrefix = "task:"
+
+// parseTaskChannel obtiene el ID de tarea de un nombre de canal
+func parseTaskChannel(channel string) (uint, error) {
+	if !strings.HasPrefix(channel, taskChannelPrefix) {
+		return 0, fmt.Errorf("canal desconocido %q: usa task:<id>", channel)
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(channel, taskChannelPrefix), 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("canal inválido %q", channel)
+	}
+	return uint(id), nil
+}
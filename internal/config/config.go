@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,18 +18,58 @@ type Config struct {
 	Port    string
 
 	// Base de datos
+	DBDriver    string
 	URLDatabase string
 
 	// JWT
 	JWTSecret   string
 	JWTExpireIn time.Duration
-}
 
-// AppConfig es la instancia global de configuración
-var AppConfig *Config
+	// Papelera
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Orden manual de tareas
+	RebalanceInterval time.Duration
+
+	// Concurrencia optimista
+	RequireIfMatch bool
+
+	// Idempotencia
+	IdempotencyTTL           time.Duration
+	IdempotencyPurgeInterval time.Duration
+	IdempotencyLease         time.Duration
+	IdempotencyMaxBody       int64
+
+	// Eventos en tiempo real
+	EventsBackend      string
+	EventsReplaySize   int
+	EventsHeartbeat    time.Duration
+	EventsPollInterval time.Duration
+
+	// Canal de colaboración (WebSocket)
+	WSAllowedOrigins []string
+
+	// Webhooks
+	WebhookDispatchInterval time.Duration
+	WebhookMaxAttempts      int
+	WebhookDisableAfter     int
+
+	// Importación de tareas
+	ImportMaxBytes       int64
+	ImportAsyncThreshold int
+
+	// GraphQL
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+
+	// gRPC
+	GRPCPort        string
+	GRPCGatewayPort string
+}
 
 // LoadConfig carga las variables de entorno y valida la configuración
-func LoadConfig() error {
+func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Advertencia: No se pudo cargar el archivo .env, usando variables de entorno del sistema")
 	}
@@ -37,46 +78,234 @@ func LoadConfig() error {
 	jwtExpireStr := getEnv("JWT_EXPIRE_IN", "24h")
 	jwtExpire, err := time.ParseDuration(jwtExpireStr)
 	if err != nil {
-		return errors.New("JWT_EXPIRE_IN tiene un formato inválido: " + jwtExpireStr)
+		return nil, errors.New("JWT_EXPIRE_IN tiene un formato inválido: " + jwtExpireStr)
 	}
 
-	// Obtener puerto y asegurar formato correcto
-	port := getEnv("PORT", "8080")
-	if !strings.HasPrefix(port, ":") {
-		port = ":" + port
+	// Parsear retención de la papelera
+	trashRetention, err := getEnvDuration("TRASH_RETENTION", "720h")
+	if err != nil {
+		return nil, err
+	}
+	trashPurgeInterval, err := getEnvDuration("TRASH_PURGE_INTERVAL", "1h")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear frecuencia de reequilibrio de posiciones
+	rebalanceInterval, err := getEnvDuration("POSITION_REBALANCE_INTERVAL", "1h")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear tiempo de vida de las claves de idempotencia
+	idempotencyTTL, err := getEnvDuration("IDEMPOTENCY_TTL", "24h")
+	if err != nil {
+		return nil, err
+	}
+	idempotencyPurgeInterval, err := getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", "1h")
+	if err != nil {
+		return nil, err
+	}
+	idempotencyMaxBody, err := getEnvInt("IDEMPOTENCY_MAX_BODY", "1048576")
+	if err != nil {
+		return nil, err
+	}
+	idempotencyLease, err := getEnvDuration("IDEMPOTENCY_LEASE", "1m")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear configuración de eventos en tiempo real
+	eventsReplaySize, err := getEnvInt("EVENTS_REPLAY_SIZE", "1000")
+	if err != nil {
+		return nil, err
+	}
+	eventsHeartbeat, err := getEnvDuration("EVENTS_HEARTBEAT", "25s")
+	if err != nil {
+		return nil, err
+	}
+	eventsPollInterval, err := getEnvDuration("EVENTS_POLL_INTERVAL", "1s")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear configuración de webhooks
+	webhookDispatchInterval, err := getEnvDuration("WEBHOOK_DISPATCH_INTERVAL", "5s")
+	if err != nil {
+		return nil, err
+	}
+	webhookMaxAttempts, err := getEnvInt("WEBHOOK_MAX_ATTEMPTS", "8")
+	if err != nil {
+		return nil, err
+	}
+	webhookDisableAfter, err := getEnvInt("WEBHOOK_DISABLE_AFTER", "15")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear configuración de importación
+	importMaxBytes, err := getEnvInt("IMPORT_MAX_BYTES", "10485760")
+	if err != nil {
+		return nil, err
+	}
+	importAsyncThreshold, err := getEnvInt("IMPORT_ASYNC_THRESHOLD", "1000")
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsear límites de GraphQL
+	graphQLMaxDepth, err := getEnvInt("GRAPHQL_MAX_DEPTH", "8")
+	if err != nil {
+		return nil, err
 	}
+	graphQLMaxComplexity, err := getEnvInt("GRAPHQL_MAX_COMPLEXITY", "5000")
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtener puerto y asegurar formato correcto
+	port := getEnvPort("PORT", "8080")
 
-	AppConfig = &Config{
+	cfg := &Config{
 		// Aplicación
 		GinMode: getEnv("GIN_MODE", "debug"),
 		Port:    port,
 
 		// Base de datos
+		DBDriver:    getEnv("DB_DRIVER", DriverPostgres),
 		URLDatabase: getEnv("URL_DATABASE", ""),
 
 		// JWT
 		JWTSecret:   getEnv("JWT_SECRET", ""),
 		JWTExpireIn: jwtExpire,
+
+		// Papelera
+		TrashRetention:     trashRetention,
+		TrashPurgeInterval: trashPurgeInterval,
+
+		// Orden manual de tareas
+		RebalanceInterval: rebalanceInterval,
+
+		// Concurrencia optimista
+		RequireIfMatch: getEnv("REQUIRE_IF_MATCH", "false") == "true",
+
+		// Idempotencia
+		IdempotencyTTL:           idempotencyTTL,
+		IdempotencyPurgeInterval: idempotencyPurgeInterval,
+		IdempotencyLease:         idempotencyLease,
+		IdempotencyMaxBody:       int64(idempotencyMaxBody),
+
+		// Eventos en tiempo real
+		EventsBackend:      getEnv("EVENTS_BACKEND", "memory"),
+		EventsReplaySize:   eventsReplaySize,
+		EventsHeartbeat:    eventsHeartbeat,
+		EventsPollInterval: eventsPollInterval,
+
+		// Canal de colaboración (WebSocket)
+		WSAllowedOrigins: getEnvList("WS_ALLOWED_ORIGINS"),
+
+		// Webhooks
+		WebhookDispatchInterval: webhookDispatchInterval,
+		WebhookMaxAttempts:      webhookMaxAttempts,
+		WebhookDisableAfter:     webhookDisableAfter,
+
+		// Importación de tareas
+		ImportMaxBytes:       int64(importMaxBytes),
+		ImportAsyncThreshold: importAsyncThreshold,
+
+		// GraphQL
+		GraphQLMaxDepth:      graphQLMaxDepth,
+		GraphQLMaxComplexity: graphQLMaxComplexity,
+
+		// gRPC
+		GRPCPort:        getEnvPort("GRPC_PORT", "9090"),
+		GRPCGatewayPort: getEnvPort("GRPC_GATEWAY_PORT", ""),
 	}
 
 	// Validar configuración crítica
-	if err := validateConfig(); err != nil {
-		return err
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 
 	log.Println("Configuración cargada correctamente")
-	return nil
+	return cfg, nil
 }
 
-// validateConfig valida que las variables críticas estén configuradas
-func validateConfig() error {
-	if AppConfig.JWTSecret == "" {
+// validate comprueba que las variables críticas estén configuradas
+func (c *Config) validate() error {
+	if c.JWTSecret == "" {
 		return errors.New("JWT_SECRET es requerido y no puede estar vacío")
 	}
-	if len(AppConfig.JWTSecret) < 10 {
+	if len(c.JWTSecret) < 10 {
 		return errors.New("JWT_SECRET debe tener al menos 10 caracteres")
 	}
-	if AppConfig.URLDatabase == "" {
+	if c.TrashRetention < 0 {
+		return errors.New("TRASH_RETENTION no puede ser negativo")
+	}
+	if c.TrashPurgeInterval <= 0 {
+		return errors.New("TRASH_PURGE_INTERVAL debe ser mayor que cero")
+	}
+	if c.RebalanceInterval <= 0 {
+		return errors.New("POSITION_REBALANCE_INTERVAL debe ser mayor que cero")
+	}
+	if c.IdempotencyTTL <= 0 {
+		return errors.New("IDEMPOTENCY_TTL debe ser mayor que cero")
+	}
+	if c.IdempotencyPurgeInterval <= 0 {
+		return errors.New("IDEMPOTENCY_PURGE_INTERVAL debe ser mayor que cero")
+	}
+	if c.IdempotencyLease <= 0 {
+		return errors.New("IDEMPOTENCY_LEASE debe ser mayor que cero")
+	}
+	if c.IdempotencyMaxBody < 1 {
+		return errors.New("IDEMPOTENCY_MAX_BODY debe ser al menos 1")
+	}
+	if c.DBDriver != DriverPostgres && c.DBDriver != DriverMySQL && c.DBDriver != DriverSQLite {
+		return errors.New("DB_DRIVER debe ser postgres, mysql o sqlite")
+	}
+	switch c.EventsBackend {
+	case "memory", "database":
+	case "postgres":
+		if c.DBDriver != DriverPostgres {
+			return errors.New("EVENTS_BACKEND=postgres requiere DB_DRIVER=postgres; con otros motores use database")
+		}
+	default:
+		return errors.New("EVENTS_BACKEND debe ser memory, postgres o database")
+	}
+	if c.EventsReplaySize < 0 {
+		return errors.New("EVENTS_REPLAY_SIZE no puede ser negativo")
+	}
+	if c.EventsHeartbeat <= 0 {
+		return errors.New("EVENTS_HEARTBEAT debe ser mayor que cero")
+	}
+	if c.EventsPollInterval <= 0 {
+		return errors.New("EVENTS_POLL_INTERVAL debe ser mayor que cero")
+	}
+	if c.WebhookDispatchInterval <= 0 {
+		return errors.New("WEBHOOK_DISPATCH_INTERVAL debe ser mayor que cero")
+	}
+	if c.WebhookMaxAttempts < 1 {
+		return errors.New("WEBHOOK_MAX_ATTEMPTS debe ser al menos 1")
+	}
+	if c.WebhookDisableAfter < 1 {
+		return errors.New("WEBHOOK_DISABLE_AFTER debe ser al menos 1")
+	}
+	if c.ImportMaxBytes < 1 {
+		return errors.New("IMPORT_MAX_BYTES debe ser mayor que cero")
+	}
+	if c.ImportAsyncThreshold < 1 {
+		return errors.New("IMPORT_ASYNC_THRESHOLD debe ser al menos 1")
+	}
+	if c.GraphQLMaxDepth < 1 {
+		return errors.New("GRAPHQL_MAX_DEPTH debe ser al menos 1")
+	}
+	if c.GraphQLMaxComplexity < 1 {
+		return errors.New("GRAPHQL_MAX_COMPLEXITY debe ser al menos 1")
+	}
+	if c.GRPCGatewayPort != "" && c.GRPCPort == "" {
+		return errors.New("GRPC_GATEWAY_PORT requiere GRPC_PORT")
+	}
+	if c.URLDatabase == "" {
 		return errors.New("URL_DATABASE es requerido y no puede estar vacío")
 	}
 	return nil
@@ -89,3 +318,43 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvPort obtiene un puerto con el formato ":puerto"; un valor vacío se mantiene vacío
+func getEnvPort(key, defaultValue string) string {
+	port := getEnv(key, defaultValue)
+	if port != "" && !strings.HasPrefix(port, ":") {
+		port = ":" + port
+	}
+	return port
+}
+
+// getEnvDuration obtiene una variable de entorno como time.Duration
+func getEnvDuration(key, defaultValue string) (time.Duration, error) {
+	value := getEnv(key, defaultValue)
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New(key + " tiene un formato inválido: " + value)
+	}
+	return duration, nil
+}
+
+// getEnvInt obtiene una variable de entorno como entero
+func getEnvInt(key, defaultValue string) (int, error) {
+	value := getEnv(key, defaultValue)
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(key + " debe ser un número entero: " + value)
+	}
+	return number, nil
+}
+
+// getEnvList obtiene una variable de entorno como lista separada por comas
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
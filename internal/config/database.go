@@ -3,67 +3,150 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/glebarez/sqlite"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// DB es la instancia global de la base de datos que se utilizará en la aplicación
-var DB *gorm.DB
+// Motores de base de datos soportados en DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
+// sqlitePragmas se aplican a cada conexión SQLite salvo que el DSN ya los defina:
+// claves foráneas activas, espera ante bloqueos y WAL para que las lecturas no bloqueen
+// a las escrituras. _txlock=immediate toma el bloqueo de escritura al iniciar la transacción
+// y evita que dos transacciones que leen y luego escriben fallen con "database is locked".
+var sqlitePragmas = []struct{ name, param string }{
+	{"foreign_keys", "_pragma=foreign_keys(1)"},
+	{"busy_timeout", "_pragma=busy_timeout(5000)"},
+	{"journal_mode", "_pragma=journal_mode(WAL)"},
+	{"_txlock", "_txlock=immediate"},
+}
 
-// ConnectDB establece la conexión con la base de datos PostgreSQL
-func ConnectDB() error {
-	dsn := AppConfig.URLDatabase
+// ConnectDB abre la conexión con la base de datos del motor configurado en DB_DRIVER
+func ConnectDB(cfg *Config) (*gorm.DB, error) {
+	dialector, err := openDialector(cfg.DBDriver, cfg.URLDatabase)
+	if err != nil {
+		return nil, err
+	}
 
 	// Configurar logger de GORM según el modo
 	var gormLogger logger.Interface
-	if AppConfig.GinMode == "debug" {
+	if cfg.GinMode == "debug" {
 		gormLogger = logger.Default.LogMode(logger.Info)
 	} else {
 		gormLogger = logger.Default.LogMode(logger.Silent)
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: gormLogger,
 	})
 	if err != nil {
-		return fmt.Errorf("error al conectar a la base de datos: %w", err)
+		return nil, fmt.Errorf("error al conectar a la base de datos: %w", err)
 	}
 
 	// Configurar connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("error al obtener instancia SQL: %w", err)
+		return nil, fmt.Errorf("error al obtener instancia SQL: %w", err)
 	}
 
 	sqlDB.SetMaxIdleConns(10)           // Conexiones inactivas en el pool
 	sqlDB.SetMaxOpenConns(100)          // Máximo de conexiones abiertas
 	sqlDB.SetConnMaxLifetime(time.Hour) // Tiempo máximo de vida de una conexión
+	if cfg.DBDriver == DriverSQLite && isSQLiteMemory(cfg.URLDatabase) {
+		// Cada conexión a :memory: es una base de datos distinta
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
 
-	DB = db
 	log.Println("Conexión a la base de datos exitosa")
-	return nil
+	return db, nil
+}
+
+// openDialector crea el dialecto de GORM para el motor y ajusta el DSN a lo que la aplicación necesita
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+
+	case DriverMySQL:
+		// Las columnas DATETIME se leen como time.Time y el texto se guarda en utf8mb4
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("URL_DATABASE no es un DSN de MySQL válido: %w", err)
+		}
+		cfg.ParseTime = true
+		if cfg.Params == nil {
+			cfg.Params = map[string]string{}
+		}
+		if _, ok := cfg.Params["charset"]; !ok {
+			cfg.Params["charset"] = "utf8mb4"
+		}
+		return mysql.Open(cfg.FormatDSN()), nil
+
+	case DriverSQLite:
+		// El archivo se crea al conectar, pero no su directorio
+		if path, _, _ := strings.Cut(dsn, "?"); !isSQLiteMemory(dsn) && !strings.HasPrefix(path, "file:") {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return nil, fmt.Errorf("no se pudo crear el directorio de la base de datos: %w", err)
+			}
+		}
+		return sqlite.Open(sqliteDSN(dsn)), nil
+
+	default:
+		return nil, fmt.Errorf("motor de base de datos no soportado: %s", driver)
+	}
 }
 
-// RunMigrations ejecuta las migraciones de la base de datos
-func RunMigrations() error {
-	err := DB.AutoMigrate(
-		&domain.User{},
-		&domain.Task{},
-	)
+// sqliteDSN agrega al DSN de SQLite los pragmas que no defina
+func sqliteDSN(dsn string) string {
+	path, query, _ := strings.Cut(dsn, "?")
+	params, err := url.ParseQuery(query)
 	if err != nil {
-		return fmt.Errorf("error al ejecutar las migraciones: %w", err)
+		return dsn
+	}
+	var extra []string
+	for _, pragma := range sqlitePragmas {
+		defined := params.Has(pragma.name)
+		for _, value := range params["_pragma"] {
+			if strings.HasPrefix(strings.ToLower(value), pragma.name) {
+				defined = true
+			}
+		}
+		if !defined && !(pragma.name == "journal_mode" && isSQLiteMemory(dsn)) {
+			extra = append(extra, pragma.param)
+		}
+	}
+	if len(extra) == 0 {
+		return dsn
 	}
-	log.Println("Migraciones ejecutadas correctamente")
-	return nil
+	if query != "" {
+		extra = append([]string{query}, extra...)
+	}
+	return path + "?" + strings.Join(extra, "&")
+}
+
+// isSQLiteMemory indica si el DSN de SQLite es una base de datos en memoria
+func isSQLiteMemory(dsn string) bool {
+	return strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory")
 }
 
 // CloseDB cierra la conexión con la base de datos
-func CloseDB() {
-	sqlDB, err := DB.DB()
+func CloseDB(db *gorm.DB) {
+	sqlDB, err := db.DB()
 	if err != nil {
 		log.Println("Error al obtener la instancia de la base de datos:", err)
 		return
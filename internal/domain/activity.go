@@ -0,0 +1,85 @@
+package domain
+
+import "encoding/json"
+
+// Acciones registradas en el historial de actividad
+const (
+	ActionTaskCreated       = "task.created"
+	ActionTaskUpdated       = "task.updated"
+	ActionTaskStatusChanged = "task.status_changed"
+	ActionTaskDeleted       = "task.deleted"
+	ActionTaskRestored      = "task.restored"
+	ActionTaskPurged        = "task.purged"
+	ActionTaskMoved         = "task.moved"
+	ActionTaskImported      = "task.imported"
+
+	ActionAuthLogin           = "auth.login"
+	ActionAuthLoginFailed     = "auth.login_failed"
+	ActionAuthPasswordChanged = "auth.password_changed"
+	ActionAuthAccountDeleted  = "auth.account_deleted"
+)
+
+// Tipos de entidad registrados en el historial de actividad
+const (
+	EntityTask = "task"
+	EntityUser = "user"
+)
+
+// Activity representa un registro inmutable del historial de actividad.
+// La tabla es de solo inserción: no tiene UpdatedAt ni DeletedAt.
+type Activity struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	ActorID    uint   `gorm:"index" json:"actor_id"`
+	Action     string `gorm:"type:varchar(50);not null;index" json:"action"`
+	EntityType string `gorm:"type:varchar(50);not null;index:idx_activity_entity" json:"entity_type"`
+	EntityID   uint   `gorm:"index:idx_activity_entity" json:"entity_id"`
+	Changes    string `gorm:"type:text" json:"changes"`
+	CreatedAt  int64  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla para Activity
+func (Activity) TableName() string {
+	return "activities"
+}
+
+// FieldChange representa el valor anterior y el nuevo de un campo modificado
+type FieldChange struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// ActivityResponse representa la respuesta de un registro de actividad
+type ActivityResponse struct {
+	ID         uint            `json:"id"`
+	ActorID    uint            `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uint            `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes,omitempty" swaggertype:"object"`
+	CreatedAt  int64           `json:"created_at"`
+}
+
+// ToResponse convierte un Activity a ActivityResponse
+func (a *Activity) ToResponse() ActivityResponse {
+	var changes json.RawMessage
+	if a.Changes != "" {
+		changes = json.RawMessage(a.Changes)
+	}
+	return ActivityResponse{
+		ID:         a.ID,
+		ActorID:    a.ActorID,
+		Action:     a.Action,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		Changes:    changes,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// ActivityPage representa una página del historial de actividad
+type ActivityPage struct {
+	Items []ActivityResponse `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "gorm.io/gorm"
+
+// AppPassword representa una contraseña de aplicación con la que los clientes que no
+// soportan JWT (por ejemplo CalDAV) se autentican con HTTP Basic.
+// Solo se guarda el hash; la contraseña se muestra una sola vez al crearla.
+type AppPassword struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"type:varchar(100);not null" json:"name"`
+	PasswordHash string         `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UserID       uint           `gorm:"not null;index" json:"user_id"`
+	User         User           `gorm:"foreignKey:UserID" json:"-"`
+	LastUsedAt   int64          `gorm:"not null;default:0" json:"last_used_at"`
+	CreatedAt    int64          `gorm:"autoCreateTime" json:"created_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica el nombre de la tabla para AppPassword
+func (AppPassword) TableName() string {
+	return "app_passwords"
+}
+
+// CreateAppPassword representa los datos necesarios para crear una contraseña de aplicación
+type CreateAppPassword struct {
+	Name string `json:"name" binding:"required,min=1,max=100" example:"Thunderbird"`
+}
+
+// AppPasswordResponse representa la respuesta de una contraseña de aplicación sin el secreto
+type AppPasswordResponse struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	LastUsedAt int64  `json:"last_used_at,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+// AppPasswordCreatedResponse incluye la contraseña, que solo se devuelve al crearla
+type AppPasswordCreatedResponse struct {
+	AppPasswordResponse
+	Password string `json:"password"`
+}
+
+// ToResponse convierte un AppPassword a AppPasswordResponse
+func (p *AppPassword) ToResponse() AppPasswordResponse {
+	return AppPasswordResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		LastUsedAt: p.LastUsedAt,
+		CreatedAt:  p.CreatedAt,
+	}
+}
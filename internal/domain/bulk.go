@@ -0,0 +1,50 @@
+package domain
+
+// Acciones soportadas por las operaciones masivas de tareas
+const (
+	BulkActionComplete = "complete"
+	BulkActionReopen   = "reopen"
+	BulkActionDelete   = "delete"
+	BulkActionCreate   = "create"
+	BulkActionMove     = "move"
+	BulkActionTag      = "tag"
+	BulkActionUntag    = "untag"
+)
+
+// Modos de ejecución de las operaciones masivas
+const (
+	// BulkModeAtomic aplica todos los elementos o ninguno
+	BulkModeAtomic = "atomic"
+	// BulkModePartial aplica los elementos válidos y reporta los que fallaron
+	BulkModePartial = "partial"
+)
+
+// MaxBulkItems es la cantidad máxima de elementos por operación masiva
+const MaxBulkItems = 500
+
+// BulkTaskRequest representa una operación sobre varias tareas a la vez
+type BulkTaskRequest struct {
+	Action string       `json:"action" binding:"required,oneof=complete reopen delete create move tag untag"`
+	Mode   string       `json:"mode" binding:"omitempty,oneof=atomic partial"`
+	IDs    []uint       `json:"ids" binding:"omitempty,max=500"`
+	Tasks  []CreateTask `json:"tasks" binding:"omitempty,max=500"`
+}
+
+// BulkItemResult representa el resultado de un elemento de la operación masiva.
+// Para create se identifica por Index (posición en tasks); para el resto, por ID.
+type BulkItemResult struct {
+	ID      uint          `json:"id,omitempty"`
+	Index   *int          `json:"index,omitempty"`
+	Success bool          `json:"success"`
+	Error   string        `json:"error,omitempty"`
+	Task    *TaskResponse `json:"task,omitempty"`
+}
+
+// BulkTaskResponse representa el resultado de una operación masiva
+type BulkTaskResponse struct {
+	Action    string           `json:"action"`
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
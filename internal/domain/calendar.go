@@ -0,0 +1,62 @@
+package domain
+
+import "gorm.io/gorm"
+
+// CalendarFeed representa una URL secreta de suscripción iCalendar con las tareas del usuario.
+// Solo se guarda el hash del token; el token completo se muestra al crear o rotar el feed.
+type CalendarFeed struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
+	Filter    string         `gorm:"type:varchar(500);not null;default:''" json:"filter"`
+	TokenHash string         `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	User      User           `gorm:"foreignKey:UserID" json:"-"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica el nombre de la tabla para CalendarFeed
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// CreateCalendarFeed representa los datos necesarios para crear un feed de calendario.
+// Filter es una expresión del lenguaje de filtros; vacía incluye todas las tareas.
+type CreateCalendarFeed struct {
+	Name   string `json:"name" binding:"required,min=1,max=100"`
+	Filter string `json:"filter" binding:"max=500"`
+}
+
+// UpdateCalendarFeed representa los datos necesarios para actualizar un feed de calendario
+type UpdateCalendarFeed struct {
+	Name   *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Filter *string `json:"filter,omitempty" binding:"omitempty,max=500"`
+}
+
+// CalendarFeedResponse representa la respuesta de un feed de calendario
+type CalendarFeedResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Filter    string `json:"filter"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// CalendarFeedURLResponse incluye la URL secreta del feed, que solo se devuelve al crearlo o rotar su token
+type CalendarFeedURLResponse struct {
+	CalendarFeedResponse
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// ToResponse convierte un CalendarFeed a CalendarFeedResponse
+func (f *CalendarFeed) ToResponse() CalendarFeedResponse {
+	return CalendarFeedResponse{
+		ID:        f.ID,
+		Name:      f.Name,
+		Filter:    f.Filter,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
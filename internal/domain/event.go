@@ -0,0 +1,17 @@
+package domain
+
+import "encoding/json"
+
+// Eventos en tiempo real publicados a los clientes conectados
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+)
+
+// TaskEvent representa el contenido de un evento en tiempo real de una tarea
+type TaskEvent struct {
+	ID     uint            `json:"id"`
+	Action string          `json:"action"`
+	Task   json.RawMessage `json:"task,omitempty" swaggertype:"object"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "gorm.io/gorm"
+
+// SavedFilter representa una lista inteligente: un filtro de tareas guardado con nombre
+type SavedFilter struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
+	Query     string         `gorm:"type:varchar(500);not null" json:"query"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	User      User           `gorm:"foreignKey:UserID" json:"-"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica el nombre de la tabla para SavedFilter
+func (SavedFilter) TableName() string {
+	return "saved_filters"
+}
+
+// CreateFilter representa los datos necesarios para guardar un filtro
+type CreateFilter struct {
+	Name  string `json:"name" binding:"required,min=1,max=100"`
+	Query string `json:"query" binding:"required,min=1,max=500"`
+}
+
+// UpdateFilter representa los datos necesarios para actualizar un filtro guardado
+type UpdateFilter struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Query *string `json:"query,omitempty" binding:"omitempty,min=1,max=500"`
+}
+
+// FilterResponse representa la respuesta de un filtro guardado
+type FilterResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	UserID    uint   `json:"user_id"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// ToResponse convierte un SavedFilter a FilterResponse
+func (f *SavedFilter) ToResponse() FilterResponse {
+	return FilterResponse{
+		ID:        f.ID,
+		Name:      f.Name,
+		Query:     f.Query,
+		UserID:    f.UserID,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+// IdempotencyKey guarda el resultado de una petición POST identificada por el encabezado Idempotency-Key.
+// Mientras la petición original está en curso StatusCode vale 0.
+type IdempotencyKey struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	UserID       uint   `gorm:"not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	Key          string `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key" json:"key"`
+	RequestHash  string `gorm:"type:varchar(64);not null" json:"request_hash"`
+	StatusCode   int    `gorm:"not null;default:0" json:"status_code"`
+	ResponseBody string `gorm:"type:text" json:"response_body"`
+	// ResponseHeaders son los encabezados de la respuesta que se repiten, en JSON
+	ResponseHeaders string `gorm:"type:text" json:"response_headers"`
+	CreatedAt       int64  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt       int64  `gorm:"not null;index" json:"expires_at"`
+	// LockedUntil es el fin de la reserva de la petición en curso: si vence sin que la petición
+	// termine, otro intento con la misma clave puede tomarla
+	LockedUntil int64 `gorm:"not null;default:0" json:"locked_until"`
+}
+
+// TableName especifica el nombre de la tabla para IdempotencyKey
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed indica si la petición original ya terminó y su respuesta está guardada
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,114 @@
+package domain
+
+import "encoding/json"
+
+// Estados de un trabajo de importación en segundo plano
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// MaxImportErrors es la cantidad máxima de errores por línea que se reportan
+const MaxImportErrors = 1000
+
+// ImportJob representa una importación grande que se ejecuta en segundo plano
+type ImportJob struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	UserID     uint   `gorm:"not null;index" json:"user_id"`
+	User       User   `gorm:"foreignKey:UserID" json:"-"`
+	Format     string `gorm:"type:varchar(20);not null" json:"format"`
+	Filename   string `gorm:"type:varchar(255)" json:"filename"`
+	Status     string `gorm:"type:varchar(20);not null;index" json:"status"`
+	Total      int    `gorm:"not null;default:0" json:"total"`
+	Processed  int    `gorm:"not null;default:0" json:"processed"`
+	Created    int    `gorm:"not null;default:0" json:"created"`
+	Errors     string `gorm:"type:text" json:"-"`
+	Ignored    string `gorm:"type:varchar(255)" json:"-"`
+	Error      string `gorm:"type:text" json:"error"`
+	CreatedAt  int64  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  int64  `gorm:"autoUpdateTime" json:"updated_at"`
+	FinishedAt int64  `gorm:"not null;default:0" json:"finished_at"`
+}
+
+// TableName especifica el nombre de la tabla para ImportJob
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
+
+// ImportOptions representa las opciones de POST /api/import, enviadas como campos del formulario multipart
+type ImportOptions struct {
+	Format  string `form:"format" binding:"omitempty,oneof=auto csv json todoist todotxt"`
+	Mapping string `form:"mapping" binding:"omitempty,max=500"`
+	DryRun  bool   `form:"dry_run"`
+}
+
+// ImportLineError describe un problema en una línea del archivo importado
+type ImportLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportedTask representa una tarea leída del archivo
+type ImportedTask struct {
+	Line      int    `json:"line"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+}
+
+// ImportResult representa el resultado de una importación o de su simulación (dry run).
+// Ignored lista los atributos del archivo (prioridad, proyecto, contexto, vencimiento)
+// que las tareas todavía no soportan y no se importan.
+type ImportResult struct {
+	Format          string            `json:"format"`
+	DryRun          bool              `json:"dry_run"`
+	Total           int               `json:"total"`
+	Created         int               `json:"created"`
+	Tasks           []ImportedTask    `json:"tasks,omitempty"`
+	Errors          []ImportLineError `json:"errors"`
+	ErrorsTruncated bool              `json:"errors_truncated,omitempty"`
+	Ignored         []string          `json:"ignored_fields,omitempty"`
+}
+
+// ImportJobResponse representa el estado de un trabajo de importación
+type ImportJobResponse struct {
+	ID         uint              `json:"id"`
+	Format     string            `json:"format"`
+	Filename   string            `json:"filename"`
+	Status     string            `json:"status"`
+	Total      int               `json:"total"`
+	Processed  int               `json:"processed"`
+	Created    int               `json:"created"`
+	Errors     []ImportLineError `json:"errors"`
+	Ignored    []string          `json:"ignored_fields,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	CreatedAt  int64             `json:"created_at"`
+	UpdatedAt  int64             `json:"updated_at"`
+	FinishedAt int64             `json:"finished_at,omitempty"`
+}
+
+// ToResponse convierte un ImportJob a ImportJobResponse
+func (j *ImportJob) ToResponse() ImportJobResponse {
+	response := ImportJobResponse{
+		ID:         j.ID,
+		Format:     j.Format,
+		Filename:   j.Filename,
+		Status:     j.Status,
+		Total:      j.Total,
+		Processed:  j.Processed,
+		Created:    j.Created,
+		Errors:     []ImportLineError{},
+		Error:      j.Error,
+		CreatedAt:  j.CreatedAt,
+		UpdatedAt:  j.UpdatedAt,
+		FinishedAt: j.FinishedAt,
+	}
+	if j.Errors != "" {
+		_ = json.Unmarshal([]byte(j.Errors), &response.Errors)
+	}
+	if j.Ignored != "" {
+		_ = json.Unmarshal([]byte(j.Ignored), &response.Ignored)
+	}
+	return response
+}
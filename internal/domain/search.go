@@ -0,0 +1,30 @@
+package domain
+
+// Idiomas soportados por la búsqueda de texto completo
+const (
+	SearchLangSpanish = "es"
+	SearchLangEnglish = "en"
+)
+
+// TaskSearchResult representa una tarea encontrada por la búsqueda junto con su relevancia
+type TaskSearchResult struct {
+	Task    Task
+	Rank    float64
+	Snippet string
+}
+
+// TaskSearchResponse representa la respuesta de una tarea encontrada por la búsqueda
+type TaskSearchResponse struct {
+	TaskResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// ToResponse convierte un TaskSearchResult a TaskSearchResponse
+func (r *TaskSearchResult) ToResponse() TaskSearchResponse {
+	return TaskSearchResponse{
+		TaskResponse: r.Task.ToResponse(),
+		Rank:         r.Rank,
+		Snippet:      r.Snippet,
+	}
+}
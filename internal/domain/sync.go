@@ -0,0 +1,89 @@
+package domain
+
+// Operaciones que un cliente puede enviar en una sincronización
+const (
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+// Resultado de cada mutación enviada por el cliente
+const (
+	// SyncStatusApplied indica que todos los campos de la mutación se aplicaron
+	SyncStatusApplied = "applied"
+	// SyncStatusMerged indica que se aplicó una parte de los campos y el resto perdió el conflicto
+	SyncStatusMerged = "merged"
+	// SyncStatusRejected indica que la mutación no se aplicó
+	SyncStatusRejected = "rejected"
+)
+
+// Límites de la sincronización
+const (
+	MaxSyncMutations = 500
+	DefaultSyncLimit = 500
+	MaxSyncLimit     = 1000
+)
+
+// SyncChange representa el estado actual de una tarea que cambió desde el token del cliente.
+// Si Deleted es true la tarea fue eliminada y Task es nulo (tombstone).
+type SyncChange struct {
+	ID       uint          `json:"id"`
+	ClientID *string       `json:"client_id,omitempty"`
+	Deleted  bool          `json:"deleted"`
+	Task     *TaskResponse `json:"task,omitempty"`
+}
+
+// SyncChanges representa una página de cambios de la sincronización.
+// Si Reset es true el cliente debe descartar su copia local y reconstruirla con esta página y las siguientes.
+type SyncChanges struct {
+	Changes []SyncChange `json:"changes"`
+	Token   string       `json:"token"`
+	HasMore bool         `json:"has_more"`
+	Reset   bool         `json:"reset"`
+}
+
+// SyncFields representa los campos de una tarea que el cliente modificó sin conexión.
+// Los campos omitidos no se modifican.
+type SyncFields struct {
+	Title     *string `json:"title,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+}
+
+// SyncMutation representa un cambio hecho por el cliente sin conexión.
+// La tarea se identifica por ID o, si todavía no lo conoce, por el ClientID que generó al crearla.
+// Base contiene los valores que el cliente tenía antes de modificarla y permite detectar
+// qué campos cambiaron también en el servidor.
+type SyncMutation struct {
+	Op          string      `json:"op" example:"update"`
+	ClientID    string      `json:"client_id,omitempty" example:"0b5c1a7e-4f0e-4d7a-9d43-2f1f3a6b8c10"`
+	ID          uint        `json:"id,omitempty"`
+	BaseVersion uint        `json:"base_version,omitempty"`
+	UpdatedAt   int64       `json:"updated_at"`
+	Fields      SyncFields  `json:"fields"`
+	Base        *SyncFields `json:"base,omitempty"`
+}
+
+// SyncRequest representa un lote de mutaciones del cliente, que se aplican en orden
+type SyncRequest struct {
+	Mutations []SyncMutation `json:"mutations" binding:"required,min=1,max=500"`
+}
+
+// SyncMutationResult representa el resultado de una mutación del cliente.
+// Task es el estado de la tarea en el servidor después de aplicarla, si existe.
+type SyncMutationResult struct {
+	Index          int           `json:"index"`
+	ClientID       string        `json:"client_id,omitempty"`
+	ID             uint          `json:"id,omitempty"`
+	Status         string        `json:"status"`
+	RejectedFields []string      `json:"rejected_fields,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Task           *TaskResponse `json:"task,omitempty"`
+}
+
+// SyncResult representa el resultado de un lote de mutaciones
+type SyncResult struct {
+	Results  []SyncMutationResult `json:"results"`
+	Applied  int                  `json:"applied"`
+	Merged   int                  `json:"merged"`
+	Rejected int                  `json:"rejected"`
+}
@@ -7,10 +7,13 @@ type Task struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	Title     string         `gorm:"type:varchar(200);not null" json:"title"`
 	Completed bool           `gorm:"default:false" json:"completed"`
-	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	Position  string         `gorm:"type:varchar(255);not null;default:'';index" json:"position"`
+	UserID    uint           `gorm:"not null;index;uniqueIndex:idx_tasks_user_client,priority:1;index:idx_tasks_user_updated,priority:1" json:"user_id"`
 	User      User           `gorm:"foreignKey:UserID" json:"-"`
+	ClientID  *string        `gorm:"type:varchar(64);uniqueIndex:idx_tasks_user_client,priority:2" json:"client_id,omitempty"`
+	Version   uint           `gorm:"not null;default:1" json:"version"`
 	CreatedAt int64          `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updated_at"`
+	UpdatedAt int64          `gorm:"autoUpdateTime;index:idx_tasks_user_updated,priority:2" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
@@ -30,14 +33,39 @@ type UpdateTask struct {
 	Completed *bool   `json:"completed,omitempty"`
 }
 
+// TaskDocument representa los campos editables de una tarea sobre los que se aplica un PATCH.
+// El documento resultante se valida con las mismas reglas que CreateTask.
+type TaskDocument struct {
+	Title     string `json:"title" binding:"required,min=1,max=200"`
+	Completed bool   `json:"completed"`
+}
+
+// ToDocument convierte un Task al documento sobre el que se aplica un PATCH
+func (t *Task) ToDocument() TaskDocument {
+	return TaskDocument{
+		Title:     t.Title,
+		Completed: t.Completed,
+	}
+}
+
+// MoveTask representa el destino de una tarea al reordenarla.
+// AfterID es la tarea que quedará inmediatamente antes; BeforeID, la que quedará inmediatamente después.
+type MoveTask struct {
+	AfterID  *uint `json:"after_id,omitempty"`
+	BeforeID *uint `json:"before_id,omitempty"`
+}
+
 // TaskResponse representa la respuesta de una tarea con datos del usuario
 type TaskResponse struct {
-	ID        uint   `json:"id"`
-	Title     string `json:"title"`
-	Completed bool   `json:"completed"`
-	UserID    uint   `json:"user_id"`
-	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
+	ID        uint    `json:"id"`
+	Title     string  `json:"title"`
+	Completed bool    `json:"completed"`
+	Position  string  `json:"position"`
+	UserID    uint    `json:"user_id"`
+	ClientID  *string `json:"client_id,omitempty"`
+	Version   uint    `json:"version"`
+	CreatedAt int64   `json:"created_at"`
+	UpdatedAt int64   `json:"updated_at"`
 }
 
 // ToResponse convierte un Task a TaskResponse
@@ -46,8 +74,36 @@ func (t *Task) ToResponse() TaskResponse {
 		ID:        t.ID,
 		Title:     t.Title,
 		Completed: t.Completed,
+		Position:  t.Position,
 		UserID:    t.UserID,
+		ClientID:  t.ClientID,
+		Version:   t.Version,
 		CreatedAt: t.CreatedAt,
 		UpdatedAt: t.UpdatedAt,
 	}
 }
+
+// TaskStats resume un conjunto de tareas para detectar si cambió sin leerlas
+type TaskStats struct {
+	Count       int64
+	LastUpdated int64
+	VersionSum  int64
+}
+
+// TrashTaskResponse representa una tarea eliminada que se encuentra en la papelera
+type TrashTaskResponse struct {
+	TaskResponse
+	DeletedAt int64 `json:"deleted_at"`
+}
+
+// ToTrashResponse convierte un Task eliminado a TrashTaskResponse
+func (t *Task) ToTrashResponse() TrashTaskResponse {
+	var deletedAt int64
+	if t.DeletedAt.Valid {
+		deletedAt = t.DeletedAt.Time.Unix()
+	}
+	return TrashTaskResponse{
+		TaskResponse: t.ToResponse(),
+		DeletedAt:    deletedAt,
+	}
+}
@@ -8,7 +8,9 @@ type User struct {
 	FullName  string         `gorm:"type:varchar(100);not null" json:"full_name"`
 	Email     string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
+	IsAdmin   bool           `gorm:"not null;default:false" json:"is_admin"`
 	Tasks     []Task         `gorm:"foreignKey:UserID" json:"tasks,omitempty"`
+	Version   uint           `gorm:"not null;default:1" json:"version"`
 	CreatedAt int64          `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -33,6 +35,22 @@ type UserUpdate struct {
 	Password *string `json:"password,omitempty" binding:"omitempty,min=8"`
 }
 
+// UserDocument representa los campos editables del perfil sobre los que se aplica un PATCH.
+// La contraseña no forma parte del documento original: se agrega al patch para cambiarla.
+type UserDocument struct {
+	FullName string  `json:"full_name" binding:"required,min=2,max=100"`
+	Email    string  `json:"email" binding:"required,email"`
+	Password *string `json:"password,omitempty" binding:"omitempty,min=8"`
+}
+
+// ToDocument convierte un User al documento sobre el que se aplica un PATCH
+func (u *User) ToDocument() UserDocument {
+	return UserDocument{
+		FullName: u.FullName,
+		Email:    u.Email,
+	}
+}
+
 // UserLogin representa los datos necesarios para que un usuario inicie sesión
 type UserLogin struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -44,6 +62,8 @@ type UserResponse struct {
 	ID        uint   `json:"id"`
 	FullName  string `json:"full_name"`
 	Email     string `json:"email"`
+	IsAdmin   bool   `json:"is_admin"`
+	Version   uint   `json:"version"`
 	CreatedAt int64  `json:"created_at"`
 	UpdatedAt int64  `json:"updated_at"`
 }
@@ -54,6 +74,8 @@ func (u *User) ToResponse() UserResponse {
 		ID:        u.ID,
 		FullName:  u.FullName,
 		Email:     u.Email,
+		IsAdmin:   u.IsAdmin,
+		Version:   u.Version,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
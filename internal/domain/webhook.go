@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Eventos a los que se puede suscribir un webhook
+const (
+	WebhookEventAll           = "*"
+	WebhookEventTaskCreated   = "task.created"
+	WebhookEventTaskUpdated   = "task.updated"
+	WebhookEventTaskCompleted = "task.completed"
+	WebhookEventTaskReopened  = "task.reopened"
+	WebhookEventTaskMoved     = "task.moved"
+	WebhookEventTaskDeleted   = "task.deleted"
+	WebhookEventTaskRestored  = "task.restored"
+	WebhookEventTaskPurged    = "task.purged"
+)
+
+// WebhookEvents contiene los eventos válidos para suscribir un webhook
+var WebhookEvents = []string{
+	WebhookEventTaskCreated,
+	WebhookEventTaskUpdated,
+	WebhookEventTaskCompleted,
+	WebhookEventTaskReopened,
+	WebhookEventTaskMoved,
+	WebhookEventTaskDeleted,
+	WebhookEventTaskRestored,
+	WebhookEventTaskPurged,
+}
+
+// Estados de una entrega de webhook
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Webhook representa una URL suscrita a eventos de las tareas de un usuario
+type Webhook struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	URL          string         `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret       string         `gorm:"type:varchar(64);not null" json:"-"`
+	Events       string         `gorm:"type:varchar(500);not null" json:"events"`
+	Active       bool           `gorm:"not null;default:true" json:"active"`
+	FailureCount int            `gorm:"not null;default:0" json:"failure_count"`
+	DisabledAt   int64          `gorm:"not null;default:0" json:"disabled_at"`
+	UserID       uint           `gorm:"not null;index" json:"user_id"`
+	User         User           `gorm:"foreignKey:UserID" json:"-"`
+	CreatedAt    int64          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    int64          `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica el nombre de la tabla para Webhook
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// EventList devuelve los eventos suscritos como lista
+func (w *Webhook) EventList() []string {
+	return strings.Split(w.Events, ",")
+}
+
+// Subscribed indica si el webhook está suscrito al evento
+func (w *Webhook) Subscribed(event string) bool {
+	for _, subscribed := range w.EventList() {
+		if subscribed == WebhookEventAll || subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery representa un envío de un evento a un webhook. Las entregas pendientes
+// forman la cola persistente de reintentos; las terminadas, el historial de entregas.
+type WebhookDelivery struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	WebhookID     uint   `gorm:"not null;index" json:"webhook_id"`
+	Event         string `gorm:"type:varchar(50);not null" json:"event"`
+	Payload       string `gorm:"type:text;not null" json:"-"`
+	Status        string `gorm:"type:varchar(20);not null;index:idx_delivery_queue" json:"status"`
+	Attempts      int    `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt int64  `gorm:"not null;index:idx_delivery_queue" json:"next_attempt_at"`
+	ResponseCode  int    `gorm:"not null;default:0" json:"response_code"`
+	Error         string `gorm:"type:text" json:"error"`
+	DeliveredAt   int64  `gorm:"not null;default:0" json:"delivered_at"`
+	CreatedAt     int64  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     int64  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla para WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// CreateWebhook representa los datos necesarios para registrar un webhook
+type CreateWebhook struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+// UpdateWebhook representa los datos necesarios para actualizar un webhook.
+// Reactivar un webhook desactivado reinicia su contador de fallos.
+type UpdateWebhook struct {
+	URL    *string  `json:"url,omitempty" binding:"omitempty,url,max=2048"`
+	Events []string `json:"events,omitempty" binding:"omitempty,min=1"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// WebhookResponse representa la respuesta de un webhook
+type WebhookResponse struct {
+	ID           uint     `json:"id"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`
+	Active       bool     `json:"active"`
+	FailureCount int      `json:"failure_count"`
+	DisabledAt   int64    `json:"disabled_at,omitempty"`
+	CreatedAt    int64    `json:"created_at"`
+	UpdatedAt    int64    `json:"updated_at"`
+}
+
+// ToResponse convierte un Webhook a WebhookResponse
+func (w *Webhook) ToResponse() WebhookResponse {
+	return WebhookResponse{
+		ID:           w.ID,
+		URL:          w.URL,
+		Events:       w.EventList(),
+		Active:       w.Active,
+		FailureCount: w.FailureCount,
+		DisabledAt:   w.DisabledAt,
+		CreatedAt:    w.CreatedAt,
+		UpdatedAt:    w.UpdatedAt,
+	}
+}
+
+// WebhookSecretResponse representa un webhook recién creado junto con su secreto de firma.
+// El secreto solo se devuelve al crear el webhook.
+type WebhookSecretResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload representa el cuerpo JSON enviado en cada entrega
+type WebhookPayload struct {
+	Event     string          `json:"event"`
+	CreatedAt int64           `json:"created_at"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// DeliveryResponse representa una entrega en el historial de un webhook
+type DeliveryResponse struct {
+	ID            uint            `json:"id"`
+	WebhookID     uint            `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt int64           `json:"next_attempt_at,omitempty"`
+	ResponseCode  int             `json:"response_code,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	DeliveredAt   int64           `json:"delivered_at,omitempty"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt     int64           `json:"created_at"`
+}
+
+// ToResponse convierte un WebhookDelivery a DeliveryResponse
+func (d *WebhookDelivery) ToResponse() DeliveryResponse {
+	response := DeliveryResponse{
+		ID:           d.ID,
+		WebhookID:    d.WebhookID,
+		Event:        d.Event,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		ResponseCode: d.ResponseCode,
+		Error:        d.Error,
+		DeliveredAt:  d.DeliveredAt,
+		Payload:      json.RawMessage(d.Payload),
+		CreatedAt:    d.CreatedAt,
+	}
+	if d.Status == DeliveryStatusPending {
+		response.NextAttemptAt = d.NextAttemptAt
+	}
+	return response
+}
+
+// DeliveryPage representa una página del historial de entregas
+type DeliveryPage struct {
+	Items []DeliveryResponse `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
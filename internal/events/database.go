@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// eventLogRetention es el tiempo que se conservan los eventos en event_log
+	eventLogRetention = 5 * time.Minute
+	// eventLogBatch es la cantidad máxima de eventos que se leen en cada consulta
+	eventLogBatch = 500
+)
+
+// eventLogRecord es una fila de la tabla event_log
+type eventLogRecord struct {
+	ID        uint64 `gorm:"primaryKey"`
+	Payload   string
+	CreatedAt int64
+}
+
+// TableName especifica el nombre de la tabla de eventos
+func (eventLogRecord) TableName() string {
+	return "event_log"
+}
+
+// databasePubSub implementa PubSub con la tabla event_log: cada réplica inserta sus eventos
+// y consulta los nuevos periódicamente. Funciona con cualquier motor, por lo que sirve para
+// varias réplicas con MySQL o SQLite, donde no existe LISTEN/NOTIFY.
+type databasePubSub struct {
+	db       *gorm.DB
+	interval time.Duration
+}
+
+// NewDatabasePubSub crea un PubSub sobre la tabla event_log que consulta cada interval
+func NewDatabasePubSub(db *gorm.DB, interval time.Duration) PubSub {
+	return &databasePubSub{db: db, interval: interval}
+}
+
+// Publish guarda el evento en event_log
+func (p *databasePubSub) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.db.WithContext(ctx).Create(&eventLogRecord{Payload: string(payload), CreatedAt: time.Now().Unix()}).Error
+}
+
+// Subscribe entrega los eventos publicados a partir de ahora hasta que el contexto se cancela.
+// Un evento cuya inserción se confirma después de otra con un ID mayor puede perderse; los
+// clientes lo detectan al reanudar con Last-Event-ID, igual que con LISTEN/NOTIFY.
+func (p *databasePubSub) Subscribe(ctx context.Context) (<-chan Event, error) {
+	// La primera consulta se hace de forma síncrona para detectar errores de configuración
+	var lastID uint64
+	err := p.db.WithContext(ctx).Model(&eventLogRecord{}).Select("COALESCE(MAX(id), 0)").Scan(&lastID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event, 256)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		lastPurge := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var records []eventLogRecord
+			err := p.db.WithContext(ctx).Where("id > ?", lastID).Order("id").Limit(eventLogBatch).Find(&records).Error
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Println("Error al consultar los eventos:", err)
+				}
+				continue
+			}
+			for _, record := range records {
+				lastID = record.ID
+				var event Event
+				if err := json.Unmarshal([]byte(record.Payload), &event); err != nil {
+					log.Println("Evento inválido recibido:", err)
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Todas las réplicas purgan; borrar filas ya borradas no tiene efecto
+			if time.Since(lastPurge) >= eventLogRetention {
+				lastPurge = time.Now()
+				cutoff := time.Now().Add(-eventLogRetention).Unix()
+				if err := p.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&eventLogRecord{}).Error; err != nil && ctx.Err() == nil {
+					log.Println("Error al purgar los eventos:", err)
+				}
+			}
+		}
+	}()
+	return events, nil
+}
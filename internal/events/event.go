@@ -0,0 +1,31 @@
+// Package events distribuye eventos en tiempo real a los clientes conectados por SSE.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TypeResync indica al cliente que no se pudo reanudar desde Last-Event-ID
+// y que debe volver a cargar el estado completo
+const TypeResync = "resync"
+
+// Event representa un evento publicado para un usuario
+type Event struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	UserID uint            `json:"user_id"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// newEventID genera un ID único ordenable por fecha de publicación
+func newEventID() string {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%016x%s", time.Now().UnixNano(), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// subscriptionBuffer es la cantidad de eventos pendientes que admite un cliente lento
+// antes de que se cierre su conexión para que reanude con Last-Event-ID
+const subscriptionBuffer = 64
+
+// Subscription representa un cliente conectado que recibe los eventos de un usuario
+type Subscription struct {
+	userID uint
+	events chan Event
+}
+
+// Events devuelve el canal de eventos; se cierra cuando la suscripción termina
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub recibe los eventos del PubSub, los guarda en un buffer de reenvío acotado
+// y los entrega a los clientes conectados del usuario al que pertenecen
+type Hub struct {
+	pubsub     PubSub
+	replaySize int
+
+	mu          sync.Mutex
+	buffer      []Event
+	subscribers map[uint]map[*Subscription]struct{}
+}
+
+// NewHub crea un Hub que conserva los últimos replaySize eventos para reanudar conexiones
+func NewHub(pubsub PubSub, replaySize int) *Hub {
+	return &Hub{
+		pubsub:      pubsub,
+		replaySize:  replaySize,
+		buffer:      make([]Event, 0, replaySize),
+		subscribers: make(map[uint]map[*Subscription]struct{}),
+	}
+}
+
+// Run entrega los eventos recibidos del PubSub hasta que el contexto se cancela
+func (h *Hub) Run(ctx context.Context) error {
+	received, err := h.pubsub.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	for event := range received {
+		h.dispatch(event)
+	}
+	return nil
+}
+
+// Publish publica un evento para un usuario. La entrega a los clientes ocurre
+// cuando el evento vuelve a través del PubSub, igual que en las demás réplicas.
+func (h *Hub) Publish(ctx context.Context, userID uint, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return h.pubsub.Publish(ctx, Event{
+		ID:     newEventID(),
+		Type:   eventType,
+		UserID: userID,
+		Data:   payload,
+	})
+}
+
+// Subscribe registra un cliente del usuario. Si lastEventID no está vacío devuelve los eventos
+// posteriores que siguen en el buffer; ok es false si lastEventID ya no está en el buffer
+// y el cliente debe volver a cargar el estado completo.
+func (h *Hub) Subscribe(userID uint, lastEventID string) (sub *Subscription, replay []Event, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// El reenvío y el registro ocurren bajo el mismo bloqueo para no perder eventos entre ambos
+	ok = lastEventID == ""
+	if !ok {
+		for i, event := range h.buffer {
+			if event.ID != lastEventID {
+				continue
+			}
+			ok = true
+			for _, next := range h.buffer[i+1:] {
+				if next.UserID == userID {
+					replay = append(replay, next)
+				}
+			}
+			break
+		}
+	}
+
+	sub = &Subscription{userID: userID, events: make(chan Event, subscriptionBuffer)}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	return sub, replay, ok
+}
+
+// Unsubscribe elimina un cliente y cierra su canal
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// dispatch guarda el evento en el buffer y lo entrega a los clientes de su usuario
+func (h *Hub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.replaySize > 0 {
+		if len(h.buffer) == h.replaySize {
+			copy(h.buffer, h.buffer[1:])
+			h.buffer = h.buffer[:len(h.buffer)-1]
+		}
+		h.buffer = append(h.buffer, event)
+	}
+
+	for sub := range h.subscribers[event.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+			// Cliente lento: se desconecta para que reanude desde su último evento
+			h.remove(sub)
+		}
+	}
+}
+
+// remove elimina un cliente; debe llamarse con el bloqueo tomado
+func (h *Hub) remove(sub *Subscription) {
+	subs, exists := h.subscribers[sub.userID]
+	if !exists {
+		return
+	}
+	if _, exists := subs[sub]; !exists {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+	close(sub.events)
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// postgresChannel es el canal de LISTEN/NOTIFY por el que se distribuyen los eventos
+	postgresChannel = "task_events"
+	// maxNotifyPayload es el tamaño máximo del payload de NOTIFY en PostgreSQL
+	maxNotifyPayload = 8000
+	// reconnectDelay es la espera antes de volver a conectar el listener
+	reconnectDelay = 2 * time.Second
+)
+
+// postgresPubSub implementa PubSub con LISTEN/NOTIFY de PostgreSQL, de modo que
+// todas las réplicas conectadas a la misma base de datos reciben los eventos
+type postgresPubSub struct {
+	db  *gorm.DB
+	dsn string
+}
+
+// NewPostgresPubSub crea un PubSub sobre LISTEN/NOTIFY.
+// Publica con la conexión de GORM y escucha con una conexión dedicada a dsn.
+func NewPostgresPubSub(db *gorm.DB, dsn string) PubSub {
+	return &postgresPubSub{db: db, dsn: dsn}
+}
+
+// Publish envía el evento con pg_notify
+func (p *postgresPubSub) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		return fmt.Errorf("el evento %s supera el tamaño máximo de NOTIFY (%d bytes)", event.Type, maxNotifyPayload)
+	}
+	return p.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", postgresChannel, string(payload)).Error
+}
+
+// Subscribe escucha el canal hasta que el contexto se cancela, reconectando si se pierde la conexión.
+// Los eventos publicados mientras el listener está desconectado se pierden; los clientes
+// lo detectan al reanudar con Last-Event-ID y reciben un evento resync.
+func (p *postgresPubSub) Subscribe(ctx context.Context) (<-chan Event, error) {
+	// La primera conexión se valida de forma síncrona para detectar errores de configuración
+	conn, err := p.listen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event, 256)
+	go func() {
+		defer close(events)
+		for {
+			if conn != nil {
+				p.receive(ctx, conn, events)
+				conn.Close(context.Background())
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+
+			conn, err = p.listen(ctx)
+			if err != nil {
+				log.Println("Error al reconectar el listener de eventos:", err)
+			}
+		}
+	}()
+	return events, nil
+}
+
+// listen abre una conexión dedicada y se suscribe al canal
+func (p *postgresPubSub) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, p.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar el listener de eventos: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresChannel); err != nil {
+		conn.Close(context.Background())
+		return nil, fmt.Errorf("error al escuchar el canal %s: %w", postgresChannel, err)
+	}
+	return conn, nil
+}
+
+// receive entrega las notificaciones recibidas hasta que la conexión falla o el contexto se cancela
+func (p *postgresPubSub) receive(ctx context.Context, conn *pgx.Conn, events chan<- Event) {
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Println("Se perdió la conexión del listener de eventos:", err)
+			}
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Println("Evento inválido recibido:", err)
+			continue
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// PubSub distribuye los eventos entre todas las réplicas de la API.
+// Cada réplica recibe todos los eventos publicados, incluidos los propios.
+type PubSub interface {
+	Publish(ctx context.Context, event Event) error
+	Subscribe(ctx context.Context) (<-chan Event, error)
+}
+
+// memoryPubSub implementa PubSub dentro del proceso; sirve para una sola réplica
+type memoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewMemoryPubSub crea un PubSub en memoria
+func NewMemoryPubSub() PubSub {
+	return &memoryPubSub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish entrega el evento a todos los suscriptores
+func (p *memoryPubSub) Publish(ctx context.Context, event Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for subscriber := range p.subscribers {
+		select {
+		case subscriber <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe devuelve un canal con los eventos publicados hasta que el contexto se cancela
+func (p *memoryPubSub) Subscribe(ctx context.Context) (<-chan Event, error) {
+	subscriber := make(chan Event, 256)
+
+	p.mu.Lock()
+	p.subscribers[subscriber] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		delete(p.subscribers, subscriber)
+		p.mu.Unlock()
+		close(subscriber)
+	}()
+	return subscriber, nil
+}
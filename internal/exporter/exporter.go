@@ -0,0 +1,230 @@
+// Package exporter escribe tareas en los formatos de exportación: CSV, JSON, Markdown e iCalendar.
+//
+// Cada tarea se escribe en cuanto se recibe, por lo que la exportación no necesita cargar
+// todas las tareas en memoria.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+)
+
+// Formatos de exportación soportados
+const (
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "md"
+	FormatICS      = "ics"
+)
+
+// ProductID identifica a la API en los documentos iCalendar
+const ProductID = "-//gin-tasks-api//Tareas//ES"
+
+var ErrUnknownFormat = errors.New("formato de exportación no soportado: debe ser csv, json, md o ics")
+
+// Writer escribe una lista de tareas en un formato de exportación
+type Writer interface {
+	// Begin escribe el encabezado del documento
+	Begin() error
+	// Write escribe una tarea
+	Write(task *domain.Task) error
+	// End escribe el cierre del documento
+	End() error
+}
+
+// New crea el Writer del formato indicado
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatMarkdown:
+		return &markdownWriter{w: w}, nil
+	case FormatICS:
+		return &icsWriter{w: ical.NewWriter(w)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType devuelve el tipo de contenido de un formato
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatICS:
+		return ical.MediaType
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// csvWriter escribe las tareas como CSV con encabezado; las columnas title y completed
+// son las que reconoce la importación
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvWriter) Begin() error {
+	return e.w.Write([]string{"id", "title", "completed", "position", "created_at", "updated_at"})
+}
+
+func (e *csvWriter) Write(task *domain.Task) error {
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(task.ID), 10),
+		task.Title,
+		strconv.FormatBool(task.Completed),
+		task.Position,
+		formatUnix(task.CreatedAt),
+		formatUnix(task.UpdatedAt),
+	})
+}
+
+func (e *csvWriter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonWriter escribe un arreglo JSON de tareas con el mismo formato que GET /api/tasks
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonWriter) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonWriter) Write(task *domain.Task) error {
+	data, err := json.Marshal(task.ToResponse())
+	if err != nil {
+		return err
+	}
+	separator := ",\n  "
+	if e.count == 0 {
+		separator = "\n  "
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonWriter) End() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+// markdownWriter escribe las tareas como una lista de casillas de Markdown
+type markdownWriter struct {
+	w io.Writer
+}
+
+// markdownEscaper evita que el título rompa la lista o se interprete como formato
+var markdownEscaper = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", `\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+
+func (e *markdownWriter) Begin() error {
+	_, err := io.WriteString(e.w, "# Tareas\n\n")
+	return err
+}
+
+func (e *markdownWriter) Write(task *domain.Task) error {
+	mark := " "
+	if task.Completed {
+		mark = "x"
+	}
+	_, err := fmt.Fprintf(e.w, "- [%s] %s\n", mark, markdownEscaper.Replace(task.Title))
+	return err
+}
+
+func (e *markdownWriter) End() error {
+	return nil
+}
+
+// icsWriter escribe las tareas como componentes VTODO de un calendario
+type icsWriter struct {
+	w *ical.Writer
+}
+
+func (e *icsWriter) Begin() error {
+	BeginCalendar(e.w, "Tareas")
+	return e.w.Err()
+}
+
+func (e *icsWriter) Write(task *domain.Task) error {
+	WriteTodo(e.w, task)
+	return e.w.Err()
+}
+
+func (e *icsWriter) End() error {
+	e.w.End("VCALENDAR")
+	return e.w.Err()
+}
+
+// BeginCalendar abre un VCALENDAR con el nombre indicado
+func BeginCalendar(w *ical.Writer, name string) {
+	w.Begin("VCALENDAR")
+	w.Property("VERSION", "2.0")
+	w.Text("PRODID", ProductID)
+	w.Property("CALSCALE", "GREGORIAN")
+	w.Text("X-WR-CALNAME", name)
+}
+
+// WriteTodo escribe una tarea como componente VTODO. Las tareas todavía no tienen fecha
+// de vencimiento ni recurrencia, por lo que no se escriben DUE ni RRULE.
+func WriteTodo(w *ical.Writer, task *domain.Task) {
+	w.Begin("VTODO")
+	w.Text("UID", TaskUID(task))
+	w.Time("DTSTAMP", time.Unix(task.UpdatedAt, 0))
+	w.Time("CREATED", time.Unix(task.CreatedAt, 0))
+	w.Time("LAST-MODIFIED", time.Unix(task.UpdatedAt, 0))
+	w.Text("SUMMARY", task.Title)
+	w.Property("SEQUENCE", strconv.FormatUint(uint64(task.Version), 10))
+	if task.Completed {
+		w.Property("STATUS", "COMPLETED")
+		w.Property("PERCENT-COMPLETE", "100")
+	} else {
+		w.Property("STATUS", "NEEDS-ACTION")
+	}
+	w.End("VTODO")
+}
+
+// TaskUID devuelve el UID de una tarea: el identificador que le asignó el cliente que la creó
+// (sincronización o CalDAV) o, si no tiene, uno derivado de su ID
+func TaskUID(task *domain.Task) string {
+	if task.ClientID != nil {
+		return *task.ClientID
+	}
+	return TodoUID(task.ID)
+}
+
+// TodoUID devuelve el identificador único y estable de una tarea en iCalendar
+func TodoUID(id uint) string {
+	return fmt.Sprintf("task-%d@gin-tasks-api", id)
+}
+
+// formatUnix formatea una fecha Unix como RFC 3339 en UTC
+func formatUnix(seconds int64) string {
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,72 @@
+// Package filter implementa el lenguaje de expresiones para filtrar tareas.
+//
+// Una expresión es una lista de términos separados por espacios que deben cumplirse todos:
+//
+//	status:open created<7d title:"informe mensual" -deploy
+//
+// Cada término puede ser una palabra libre (se busca en el título), una frase entre comillas
+// o una condición campo+operador+valor. Un "-" al inicio niega el término.
+//
+// Los campos son status, title, created y updated. Las tareas todavía no tienen vencimiento,
+// etiquetas ni prioridad, así que due, tag y priority se rechazan con un error que lo explica.
+package filter
+
+import (
+	"fmt"
+	"time"
+)
+
+// Field identifica el campo de la tarea sobre el que se aplica un término
+type Field string
+
+// Campos soportados por el lenguaje de filtros
+const (
+	FieldText    Field = "text"
+	FieldStatus  Field = "status"
+	FieldTitle   Field = "title"
+	FieldCreated Field = "created"
+	FieldUpdated Field = "updated"
+)
+
+// Op representa el operador de comparación de un término
+type Op string
+
+// Operadores soportados por el lenguaje de filtros
+const (
+	OpEq  Op = ":"
+	OpLT  Op = "<"
+	OpLTE Op = "<="
+	OpGT  Op = ">"
+	OpGTE Op = ">="
+)
+
+// Query es el árbol sintáctico de una expresión: todos sus términos deben cumplirse
+type Query struct {
+	Terms []Term
+}
+
+// Term es una condición individual de la expresión.
+// Según el campo, el valor se encuentra en Text, Bool o Time.
+// Day indica que Time es el inicio de un día completo (una fecha absoluta o today)
+// y no un instante (una duración relativa como 7d).
+type Term struct {
+	Pos     int
+	Negated bool
+	Field   Field
+	Op      Op
+	Text    string
+	Bool    bool
+	Time    time.Time
+	Day     bool
+}
+
+// SyntaxError describe un error en la expresión y la posición (en caracteres, desde 1) donde ocurrió
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+// Error implementa la interfaz error
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("error de sintaxis en la posición %d: %s", e.Pos, e.Msg)
+}
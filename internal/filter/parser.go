@@ -0,0 +1,271 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Valores aceptados por el campo status
+var statusValues = map[string]bool{
+	"open":       false,
+	"pending":    false,
+	"abierta":    false,
+	"pendiente":  false,
+	"done":       true,
+	"completed":  true,
+	"closed":     true,
+	"completada": true,
+	"cerrada":    true,
+}
+
+// unsupportedFields son campos habituales en otros gestores de tareas que este modelo aún no tiene
+var unsupportedFields = map[string]string{
+	"due":      "vencimiento",
+	"tag":      "etiquetas",
+	"priority": "prioridad",
+}
+
+// parser recorre la expresión carácter a carácter
+type parser struct {
+	src []rune
+	pos int
+	now time.Time
+}
+
+// Parse analiza una expresión y devuelve su árbol sintáctico validado.
+// Las fechas relativas (7d, 2w, 12h, 1m) se resuelven respecto a now.
+func Parse(input string, now time.Time) (*Query, error) {
+	p := &parser{src: []rune(input), now: now}
+	query := &Query{}
+
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		query.Terms = append(query.Terms, term)
+	}
+
+	if len(query.Terms) == 0 {
+		return nil, &SyntaxError{Pos: 1, Msg: "la expresión está vacía"}
+	}
+	return query, nil
+}
+
+// parseTerm analiza un término: [-](palabra | "frase" | campo operador valor)
+func (p *parser) parseTerm() (Term, error) {
+	term := Term{Pos: p.pos + 1, Field: FieldText, Op: OpEq}
+
+	if p.peek() == '-' && p.pos+1 < len(p.src) && !unicode.IsSpace(p.src[p.pos+1]) {
+		term.Negated = true
+		p.pos++
+	}
+
+	// Frase libre entre comillas
+	if p.peek() == '"' {
+		text, err := p.readQuoted()
+		if err != nil {
+			return term, err
+		}
+		term.Text = strings.ToLower(text)
+		return term, nil
+	}
+
+	wordPos := p.pos + 1
+	word := p.readWhile(func(r rune) bool {
+		return !unicode.IsSpace(r) && r != '"' && !isOpChar(r)
+	})
+	if word == "" {
+		return term, &SyntaxError{Pos: p.pos + 1, Msg: fmt.Sprintf("carácter inesperado %q", p.peek())}
+	}
+
+	// Palabra libre
+	if p.eof() || unicode.IsSpace(p.peek()) {
+		term.Text = strings.ToLower(word)
+		return term, nil
+	}
+	if p.peek() == '"' {
+		return term, &SyntaxError{Pos: p.pos + 1, Msg: "se esperaba un operador antes de las comillas"}
+	}
+
+	// Condición campo operador valor
+	opPos := p.pos + 1
+	op := p.readOp()
+
+	valuePos := p.pos + 1
+	var value string
+	if p.peek() == '"' {
+		quoted, err := p.readQuoted()
+		if err != nil {
+			return term, err
+		}
+		value = quoted
+	} else {
+		value = p.readWhile(func(r rune) bool { return !unicode.IsSpace(r) })
+	}
+	if value == "" {
+		return term, &SyntaxError{Pos: valuePos, Msg: fmt.Sprintf("se esperaba un valor después de %q", word+string(op))}
+	}
+
+	term.Field = Field(strings.ToLower(word))
+	term.Op = op
+
+	switch term.Field {
+	case FieldStatus:
+		if op != OpEq {
+			return term, &SyntaxError{Pos: opPos, Msg: "el campo status solo admite el operador ':'"}
+		}
+		completed, ok := statusValues[strings.ToLower(value)]
+		if !ok {
+			return term, &SyntaxError{Pos: valuePos, Msg: fmt.Sprintf("valor de status inválido %q (use open o done)", value)}
+		}
+		term.Bool = completed
+
+	case FieldTitle:
+		if op != OpEq {
+			return term, &SyntaxError{Pos: opPos, Msg: "el campo title solo admite el operador ':'"}
+		}
+		term.Text = strings.ToLower(value)
+
+	case FieldCreated, FieldUpdated:
+		if err := p.parseTime(&term, value, valuePos); err != nil {
+			return term, err
+		}
+
+	default:
+		if what, ok := unsupportedFields[string(term.Field)]; ok {
+			return term, &SyntaxError{Pos: wordPos, Msg: fmt.Sprintf("el campo %q no está disponible: las tareas todavía no tienen %s (use status, title, created o updated)", word, what)}
+		}
+		return term, &SyntaxError{Pos: wordPos, Msg: fmt.Sprintf("campo desconocido %q (use status, title, created o updated)", word)}
+	}
+
+	return term, nil
+}
+
+// parseTime interpreta el valor de un campo de fecha.
+// Una fecha absoluta (2006-01-02, today, hoy) representa el día completo: con ':' equivale a ese día,
+// "<=" lo incluye y ">" empieza al día siguiente.
+// Una duración relativa (7d) expresa antigüedad: created<7d significa "creada hace menos de 7 días".
+func (p *parser) parseTime(term *Term, value string, valuePos int) error {
+	lower := strings.ToLower(value)
+
+	if lower == "today" || lower == "hoy" {
+		year, month, day := p.now.Date()
+		term.Time = time.Date(year, month, day, 0, 0, 0, 0, p.now.Location())
+		term.Day = true
+		return nil
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, p.now.Location()); err == nil {
+		term.Time = date
+		term.Day = true
+		return nil
+	}
+
+	if runes := []rune(lower); len(runes) >= 2 {
+		amount, err := strconv.Atoi(string(runes[:len(runes)-1]))
+		if err == nil && amount >= 0 {
+			switch unit := runes[len(runes)-1]; unit {
+			case 'h':
+				term.Time = p.now.Add(-time.Duration(amount) * time.Hour)
+			case 'd':
+				term.Time = p.now.AddDate(0, 0, -amount)
+			case 'w':
+				term.Time = p.now.AddDate(0, 0, -7*amount)
+			case 'm':
+				term.Time = p.now.AddDate(0, -amount, 0)
+			default:
+				return &SyntaxError{Pos: valuePos + len(runes) - 1, Msg: fmt.Sprintf("unidad de tiempo inválida %q (use h, d, w o m)", unit)}
+			}
+			term.Op = invertAge(term.Op)
+			return nil
+		}
+	}
+
+	return &SyntaxError{Pos: valuePos, Msg: fmt.Sprintf("fecha inválida %q (use AAAA-MM-DD, today o una duración como 7d)", value)}
+}
+
+// invertAge convierte una comparación de antigüedad en una comparación de fechas:
+// "antigüedad < 7d" equivale a "fecha > ahora-7d". Con ':' se interpreta como "dentro de los últimos 7d".
+func invertAge(op Op) Op {
+	switch op {
+	case OpLT:
+		return OpGT
+	case OpLTE, OpEq:
+		return OpGTE
+	case OpGT:
+		return OpLT
+	case OpGTE:
+		return OpLTE
+	}
+	return op
+}
+
+// readOp lee un operador: ':', '=', '<', '<=', '>' o '>='
+func (p *parser) readOp() Op {
+	r := p.src[p.pos]
+	p.pos++
+	switch r {
+	case '<', '>':
+		if p.peek() == '=' {
+			p.pos++
+			return Op(string(r) + "=")
+		}
+		return Op(string(r))
+	}
+	return OpEq
+}
+
+// readQuoted lee un texto entre comillas dobles
+func (p *parser) readQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // comilla de apertura
+	text := p.readWhile(func(r rune) bool { return r != '"' })
+	if p.eof() {
+		return "", &SyntaxError{Pos: start + 1, Msg: "comillas sin cerrar"}
+	}
+	p.pos++ // comilla de cierre
+	if text == "" {
+		return "", &SyntaxError{Pos: start + 1, Msg: "el texto entre comillas está vacío"}
+	}
+	return text, nil
+}
+
+// readWhile avanza mientras se cumpla la condición y devuelve el texto leído
+func (p *parser) readWhile(cond func(rune) bool) string {
+	start := p.pos
+	for !p.eof() && cond(p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// skipSpaces avanza hasta el siguiente carácter que no sea un espacio
+func (p *parser) skipSpaces() {
+	p.readWhile(unicode.IsSpace)
+}
+
+// peek devuelve el carácter actual sin avanzar
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// eof indica si se llegó al final de la expresión
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+// isOpChar indica si el carácter inicia un operador
+func isOpChar(r rune) bool {
+	return r == ':' || r == '<' || r == '>' || r == '='
+}
@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	midnight := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  []Term
+	}{
+		{"deploy", []Term{{Pos: 1, Field: FieldText, Op: OpEq, Text: "deploy"}}},
+		{"-Deploy", []Term{{Pos: 1, Negated: true, Field: FieldText, Op: OpEq, Text: "deploy"}}},
+		{`"Informe Mensual"`, []Term{{Pos: 1, Field: FieldText, Op: OpEq, Text: "informe mensual"}}},
+		{`title:"Informe Mensual"`, []Term{{Pos: 1, Field: FieldTitle, Op: OpEq, Text: "informe mensual"}}},
+		{"status:open", []Term{{Pos: 1, Field: FieldStatus, Op: OpEq, Bool: false}}},
+		{"status:done", []Term{{Pos: 1, Field: FieldStatus, Op: OpEq, Bool: true}}},
+		{"created:2026-10-18", []Term{{Pos: 1, Field: FieldCreated, Op: OpEq, Time: midnight, Day: true}}},
+		{"updated<=today", []Term{{Pos: 1, Field: FieldUpdated, Op: OpLTE, Time: midnight, Day: true}}},
+		{"created>hoy", []Term{{Pos: 1, Field: FieldCreated, Op: OpGT, Time: midnight, Day: true}}},
+		// Las duraciones expresan antigüedad: el operador se invierte y no es un día completo
+		{"created<7d", []Term{{Pos: 1, Field: FieldCreated, Op: OpGT, Time: testNow.AddDate(0, 0, -7)}}},
+		{"created>=12h", []Term{{Pos: 1, Field: FieldCreated, Op: OpLTE, Time: testNow.Add(-12 * time.Hour)}}},
+		{"updated:2w", []Term{{Pos: 1, Field: FieldUpdated, Op: OpGTE, Time: testNow.AddDate(0, 0, -14)}}},
+		{"created>1m", []Term{{Pos: 1, Field: FieldCreated, Op: OpLT, Time: testNow.AddDate(0, -1, 0)}}},
+		{"  status:open   -deploy ", []Term{
+			{Pos: 3, Field: FieldStatus, Op: OpEq},
+			{Pos: 17, Negated: true, Field: FieldText, Op: OpEq, Text: "deploy"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := Parse(tt.input, testNow)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query.Terms)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{"", 1, "la expresión está vacía"},
+		{"   ", 1, "la expresión está vacía"},
+		{`"sin cerrar`, 1, "comillas sin cerrar"},
+		{`""`, 1, "el texto entre comillas está vacío"},
+		{"status:", 8, `se esperaba un valor después de "status:"`},
+		{"status<open", 7, "el campo status solo admite el operador ':'"},
+		{"status:maybe", 8, `valor de status inválido "maybe" (use open o done)`},
+		{"title>abc", 6, "el campo title solo admite el operador ':'"},
+		{"created<7x", 10, `unidad de tiempo inválida 'x' (use h, d, w o m)`},
+		{"created:ayer", 9, `fecha inválida "ayer" (use AAAA-MM-DD, today o una duración como 7d)`},
+		{"owner:ana", 1, `campo desconocido "owner" (use status, title, created o updated)`},
+		{"ok due<7d", 4, `el campo "due" no está disponible: las tareas todavía no tienen vencimiento (use status, title, created o updated)`},
+		{"tag:work", 1, `el campo "tag" no está disponible: las tareas todavía no tienen etiquetas (use status, title, created o updated)`},
+		{"priority>=high", 1, `el campo "priority" no está disponible: las tareas todavía no tienen prioridad (use status, title, created o updated)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, testNow)
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "se esperaba un SyntaxError, se obtuvo %v", err)
+			assert.Equal(t, tt.pos, syntaxErr.Pos)
+			assert.Equal(t, tt.msg, syntaxErr.Msg)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// listLimits indica, para cada campo que devuelve una lista paginada, el tamaño que se asume
+// si la consulta no indica limit y el máximo que aceptan los resolvers.
+// Los nombres coinciden en todos los tipos del esquema (Query.tasks, User.tasks, SavedFilter.tasks).
+var listLimits = map[string]struct{ def, max int }{
+	"tasks":    {defaultTaskLimit, maxTaskLimit},
+	"trash":    {defaultTaskLimit, maxTaskLimit},
+	"search":   {defaultSearchLimit, maxSearchLimit},
+	"activity": {defaultActivityLimit, maxActivityLimit},
+}
+
+// unboundedListSize es el tamaño que se asume para las listas sin limit (filtros guardados)
+const unboundedListSize = 10
+
+// Complexity estima el costo de una operación antes de ejecutarla: cada campo cuesta 1
+// y los campos seleccionados dentro de una lista se multiplican por su tamaño máximo.
+// Devuelve un error si la consulta no se puede analizar; la validación la hace el esquema.
+func Complexity(query, operationName string, variables map[string]interface{}) (int, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0, err
+	}
+	operation := doc.Operations.ForName(operationName)
+	if operation == nil {
+		return 0, nil
+	}
+	c := &complexity{fragments: doc.Fragments, variables: variables, visiting: map[string]bool{}}
+	return c.selectionSet(operation.SelectionSet), nil
+}
+
+type complexity struct {
+	fragments ast.FragmentDefinitionList
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+func (c *complexity) selectionSet(set ast.SelectionSet) int {
+	total := 0
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *ast.Field:
+			total += 1 + c.listSize(s)*c.selectionSet(s.SelectionSet)
+		case *ast.InlineFragment:
+			total += c.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			// Los ciclos entre fragmentos son inválidos; se ignoran aquí y los rechaza el esquema
+			fragment := c.fragments.ForName(s.Name)
+			if fragment == nil || c.visiting[s.Name] {
+				continue
+			}
+			c.visiting[s.Name] = true
+			total += c.selectionSet(fragment.SelectionSet)
+			delete(c.visiting, s.Name)
+		}
+	}
+	return total
+}
+
+// listSize devuelve por cuánto se multiplica la selección del campo
+func (c *complexity) listSize(field *ast.Field) int {
+	if field.Name == "savedFilters" {
+		return unboundedListSize
+	}
+	limits, ok := listLimits[field.Name]
+	if !ok {
+		return 1
+	}
+	arg := field.Arguments.ForName("limit")
+	if arg == nil {
+		return limits.def
+	}
+	size := limits.def
+	switch arg.Value.Kind {
+	case ast.IntValue:
+		if value, err := strconv.Atoi(arg.Value.Raw); err == nil {
+			size = value
+		}
+	case ast.Variable:
+		switch value := c.variables[arg.Value.Raw].(type) {
+		case float64:
+			size = int(value)
+		case int:
+			size = value
+		}
+	}
+	if size < 1 {
+		return 1
+	}
+	if size > limits.max {
+		return limits.max
+	}
+	return size
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+)
+
+// Códigos de error informados en extensions.code, equivalentes a los estados HTTP de la API REST
+const (
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeNotFound           = "NOT_FOUND"
+	CodeForbidden          = "FORBIDDEN"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeConflict           = "CONFLICT"
+	CodeComplexityLimit    = "COMPLEXITY_LIMIT"
+	CodeInternal           = "INTERNAL"
+)
+
+// Error es un error de un resolver con su código en extensions
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions agrega el código del error a la respuesta GraphQL
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// newError crea un error con código
+func newError(code, message string) *Error {
+	return &Error{Message: message, Code: code}
+}
+
+// toError traduce los errores de los servicios a errores GraphQL con código.
+// Los errores inesperados se registran en el log y no exponen su detalle al cliente.
+func toError(err error) error {
+	var syntaxErr *filter.SyntaxError
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound),
+		errors.Is(err, service.ErrFilterNotFound):
+		return newError(CodeNotFound, err.Error())
+	case errors.Is(err, service.ErrTaskUnauthorized),
+		errors.Is(err, service.ErrFilterUnauthorized):
+		return newError(CodeForbidden, err.Error())
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return newError(CodePreconditionFailed, err.Error())
+	case errors.Is(err, service.ErrConcurrentModification):
+		return newError(CodeConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidMove),
+		errors.Is(err, service.ErrMoveAnchorNotFound),
+		errors.Is(err, service.ErrEmptySearchQuery),
+		errors.Is(err, service.ErrUnsupportedLang),
+		errors.As(err, &syntaxErr):
+		return newError(CodeBadUserInput, err.Error())
+	default:
+		log.Printf("Error en resolver GraphQL: %v", err)
+		return newError(CodeInternal, "error interno del servidor")
+	}
+}
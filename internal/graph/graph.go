@@ -0,0 +1,80 @@
+// Package graph expone las tareas, los filtros guardados y el historial de actividad
+// mediante GraphQL, reutilizando los mismos servicios que la API REST.
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Request representa una petición GraphQL
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Server ejecuta consultas GraphQL en nombre de un usuario autenticado
+type Server struct {
+	schema        *graphql.Schema
+	resolver      *Resolver
+	maxComplexity int
+}
+
+// NewServer construye el esquema y valida que los resolvers lo implementen por completo.
+// maxDepth limita el anidamiento de las consultas y maxComplexity su costo estimado.
+func NewServer(taskService service.TaskService, filterService service.FilterService, activityService service.ActivityService, authService service.AuthServiceInterface, maxDepth, maxComplexity int) (*Server, error) {
+	resolver := &Resolver{
+		tasks:    taskService,
+		filters:  filterService,
+		activity: activityService,
+		users:    authService,
+	}
+	schema, err := graphql.ParseSchema(schemaSDL, resolver,
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxDepth),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{schema: schema, resolver: resolver, maxComplexity: maxComplexity}, nil
+}
+
+// Exec ejecuta la consulta para el usuario indicado.
+// Las consultas cuyo costo estimado supera el máximo se rechazan sin ejecutarse.
+func (s *Server) Exec(ctx context.Context, userID uint, req *Request) *graphql.Response {
+	if cost, err := Complexity(req.Query, req.OperationName, req.Variables); err == nil && cost > s.maxComplexity {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{{
+			Message:    "la consulta es demasiado compleja",
+			Extensions: map[string]interface{}{"code": CodeComplexityLimit, "complexity": cost, "max": s.maxComplexity},
+		}}}
+	}
+	// Los errores de sintaxis los informa el propio esquema al ejecutar la consulta
+	ctx = withRequest(ctx, userID, s.resolver.newLoaders(userID))
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+type contextKey struct{}
+
+// requestContext contiene el usuario autenticado y los loaders de la petición en curso
+type requestContext struct {
+	userID  uint
+	loaders *loaders
+}
+
+// withRequest agrega el usuario y los loaders al contexto de la petición
+func withRequest(ctx context.Context, userID uint, l *loaders) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestContext{userID: userID, loaders: l})
+}
+
+// fromContext obtiene los datos de la petición; el handler siempre los agrega antes de ejecutar
+func fromContext(ctx context.Context) *requestContext {
+	return ctx.Value(contextKey{}).(*requestContext)
+}
@@ -0,0 +1,284 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTasks implementa las operaciones de TaskService que usan los resolvers sobre una lista
+// en memoria y cuenta las consultas; las demás no se usan y provocan un panic
+type fakeTasks struct {
+	service.TaskService
+	tasks []domain.Task
+
+	mu           sync.Mutex
+	listCalls    int
+	getByIDsArgs [][]uint
+}
+
+func (f *fakeTasks) GetByUserID(ctx context.Context, userID uint) ([]domain.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listCalls++
+	var tasks []domain.Task
+	for _, task := range f.tasks {
+		if task.UserID == userID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (f *fakeTasks) GetByIDs(ctx context.Context, userID uint, ids []uint) ([]domain.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getByIDsArgs = append(f.getByIDsArgs, ids)
+	var tasks []domain.Task
+	for _, task := range f.tasks {
+		for _, id := range ids {
+			if task.ID == id && task.UserID == userID {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+func (f *fakeTasks) Update(ctx context.Context, id, userID uint, req *domain.UpdateTask, version uint) (*domain.Task, error) {
+	for i := range f.tasks {
+		if f.tasks[i].ID != id {
+			continue
+		}
+		if f.tasks[i].UserID != userID {
+			return nil, service.ErrTaskUnauthorized
+		}
+		task := f.tasks[i]
+		if req.Title != nil {
+			task.Title = *req.Title
+		}
+		return &task, nil
+	}
+	return nil, service.ErrTaskNotFound
+}
+
+// fakeUsers implementa GetUserByID y cuenta las consultas
+type fakeUsers struct {
+	service.AuthServiceInterface
+	users map[uint]*domain.User
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *fakeUsers) GetUserByID(ctx context.Context, userID uint) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.users[userID], nil
+}
+
+type fakeFilters struct {
+	service.FilterService
+	filters []domain.SavedFilter
+}
+
+func (f *fakeFilters) GetByID(ctx context.Context, id, userID uint) (*domain.SavedFilter, error) {
+	for i := range f.filters {
+		if f.filters[i].ID != id {
+			continue
+		}
+		if f.filters[i].UserID != userID {
+			return nil, service.ErrFilterUnauthorized
+		}
+		return &f.filters[i], nil
+	}
+	return nil, service.ErrFilterNotFound
+}
+
+type fakeActivity struct {
+	service.ActivityService
+	items []domain.ActivityResponse
+}
+
+func (f *fakeActivity) GetUserFeed(ctx context.Context, userID uint, page, limit int) (*domain.ActivityPage, error) {
+	var items []domain.ActivityResponse
+	for _, item := range f.items {
+		if item.ActorID == userID {
+			items = append(items, item)
+		}
+	}
+	return &domain.ActivityPage{Items: items, Page: page, Limit: limit, Total: int64(len(items))}, nil
+}
+
+// Los datos de prueba: Ana (1) tiene tres tareas y un filtro, Beto (2) una tarea y un filtro
+func newTestServer(t *testing.T, maxComplexity int) (*Server, *fakeTasks, *fakeUsers) {
+	t.Helper()
+	tasks := &fakeTasks{tasks: []domain.Task{
+		{ID: 1, Title: "Informe", UserID: 1},
+		{ID: 2, Title: "Compras", UserID: 1},
+		{ID: 3, Title: "Llamar", UserID: 1},
+		{ID: 4, Title: "Privada", UserID: 2},
+	}}
+	users := &fakeUsers{users: map[uint]*domain.User{
+		1: {ID: 1, FullName: "Ana", Email: "ana@example.com"},
+		2: {ID: 2, FullName: "Beto", Email: "beto@example.com"},
+	}}
+	filters := &fakeFilters{filters: []domain.SavedFilter{
+		{ID: 1, Name: "Pendientes", Query: "completed:false", UserID: 1},
+		{ID: 2, Name: "Ajeno", Query: "completed:true", UserID: 2},
+	}}
+	activity := &fakeActivity{items: []domain.ActivityResponse{
+		{ID: 1, ActorID: 1, Action: domain.ActionTaskCreated, EntityType: domain.EntityTask, EntityID: 1},
+		{ID: 2, ActorID: 1, Action: domain.ActionTaskCreated, EntityType: domain.EntityTask, EntityID: 2},
+		{ID: 3, ActorID: 1, Action: domain.ActionTaskCreated, EntityType: domain.EntityTask, EntityID: 3},
+		// Una tarea ajena nunca se resuelve aunque aparezca en el historial
+		{ID: 4, ActorID: 1, Action: domain.ActionTaskUpdated, EntityType: domain.EntityTask, EntityID: 4},
+	}}
+
+	server, err := NewServer(tasks, filters, activity, users, 10, maxComplexity)
+	require.NoError(t, err)
+	return server, tasks, users
+}
+
+// exec ejecuta una consulta y decodifica data en out
+func exec(t *testing.T, server *Server, userID uint, query string, out interface{}) *graphql.Response {
+	t.Helper()
+	resp := server.Exec(context.Background(), userID, &Request{Query: query})
+	if out != nil && len(resp.Data) > 0 {
+		require.NoError(t, json.Unmarshal(resp.Data, out), string(resp.Data))
+	}
+	return resp
+}
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      int
+	}{
+		{"campo simple", `{ me { id email } }`, nil, 3},
+		{"lista con el límite por defecto", `{ tasks { id } }`, nil, 1 + defaultTaskLimit},
+		{"límite explícito", `{ tasks(limit: 5) { id title } }`, nil, 1 + 5*2},
+		{"límite en una variable", `query($n: Int) { search(query: "x", limit: $n) { id } }`, map[string]interface{}{"n": float64(7)}, 1 + 7},
+		{"límite por encima del máximo", `{ activity(limit: 1000) { id } }`, nil, 1 + maxActivityLimit},
+		{"límite menor que uno", `{ trash(limit: 0) { id } }`, nil, 1 + 1},
+		{"listas anidadas", `{ tasks(limit: 10) { owner { tasks(limit: 10) { id } } } }`, nil, 1 + 10*(1+1*(1+10*1))},
+		{"filtros guardados", `{ savedFilters { id } }`, nil, 1 + unboundedListSize},
+		{"fragmentos", `{ tasks(limit: 2) { ...campos } } fragment campos on Task { id title }`, nil, 1 + 2*2},
+		{"fragmento en línea", `{ me { ... on User { id } } }`, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, err := Complexity(tt.query, "", tt.variables)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cost)
+		})
+	}
+
+	_, err := Complexity(`{ tasks {`, "", nil)
+	assert.Error(t, err)
+}
+
+// Una consulta que supera el costo máximo se rechaza sin ejecutar ningún resolver
+func TestExecRejectsComplexQuery(t *testing.T) {
+	server, tasks, _ := newTestServer(t, 1000)
+
+	resp := exec(t, server, 1, `{ tasks(limit: 500) { id owner { tasks(limit: 500) { id } } } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, CodeComplexityLimit, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, 1000, resp.Errors[0].Extensions["max"])
+	assert.Greater(t, resp.Errors[0].Extensions["complexity"], 1000)
+	assert.Empty(t, resp.Data)
+	assert.Zero(t, tasks.listCalls)
+
+	resp = exec(t, server, 1, `{ tasks(limit: 10) { id } }`, nil)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, 1, tasks.listCalls)
+}
+
+// Las tareas de una lista resuelven su dueño con una sola consulta de usuarios, y las tareas
+// del historial con una sola consulta por lotes
+func TestLoadersBatchLookups(t *testing.T) {
+	server, tasks, users := newTestServer(t, 10000)
+
+	var data struct {
+		Tasks []struct {
+			ID    string
+			Owner struct{ Email string }
+		}
+	}
+	resp := exec(t, server, 1, `{ tasks { id owner { email } } }`, &data)
+	require.Empty(t, resp.Errors)
+	require.Len(t, data.Tasks, 3)
+	for _, task := range data.Tasks {
+		assert.Equal(t, "ana@example.com", task.Owner.Email)
+	}
+	assert.Equal(t, 1, users.calls)
+
+	var feed struct {
+		Activity []struct {
+			EntityID string
+			Task     *struct{ Title string }
+		}
+	}
+	resp = exec(t, server, 1, `{ activity { entityId task { title } } }`, &feed)
+	require.Empty(t, resp.Errors)
+	require.Len(t, feed.Activity, 4)
+	require.Len(t, tasks.getByIDsArgs, 1)
+	assert.ElementsMatch(t, []uint{1, 2, 3, 4}, tasks.getByIDsArgs[0])
+	assert.Equal(t, "Informe", feed.Activity[0].Task.Title)
+	assert.Nil(t, feed.Activity[3].Task)
+
+}
+
+// Los resolvers solo ven los datos del usuario con el que se ejecuta la consulta
+func TestResolversUseAuthenticatedUser(t *testing.T) {
+	server, _, _ := newTestServer(t, 10000)
+
+	var me struct {
+		Me struct {
+			Email string
+			Tasks []struct{ Title string }
+		}
+	}
+	resp := exec(t, server, 2, `{ me { email tasks { title } } }`, &me)
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, "beto@example.com", me.Me.Email)
+	assert.Equal(t, []struct{ Title string }{{"Privada"}}, me.Me.Tasks)
+
+	var lookup struct {
+		Own         *struct{ Title string }
+		Other       *struct{ Title string }
+		OwnFilter   *struct{ Name string }
+		OtherFilter *struct{ Name string }
+	}
+	resp = exec(t, server, 1, `{
+  own: task(id: 1) { title }
+  other: task(id: 4) { title }
+  ownFilter: savedFilter(id: 1) { name }
+  otherFilter: savedFilter(id: 2) { name }
+}`, &lookup)
+	require.Empty(t, resp.Errors)
+	require.NotNil(t, lookup.Own)
+	assert.Equal(t, "Informe", lookup.Own.Title)
+	assert.Nil(t, lookup.Other)
+	require.NotNil(t, lookup.OwnFilter)
+	assert.Equal(t, "Pendientes", lookup.OwnFilter.Name)
+	assert.Nil(t, lookup.OtherFilter)
+
+	resp = exec(t, server, 1, `mutation { updateTask(id: 4, input: {title: "Mía"}) { id } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, CodeForbidden, resp.Errors[0].Extensions["code"])
+
+	resp = exec(t, server, 2, `mutation { updateTask(id: 4, input: {title: "Mía"}) { title } }`, nil)
+	assert.Empty(t, resp.Errors)
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaders agrupa las cargas por ID de una petición para evitar consultas N+1.
+// Se crean por petición: la caché nunca se comparte entre usuarios.
+type loaders struct {
+	tasks *dataloader.Loader[uint, *domain.Task]
+	users *dataloader.Loader[uint, *domain.User]
+}
+
+// newLoaders crea los loaders del usuario autenticado
+func (r *Resolver) newLoaders(userID uint) *loaders {
+	return &loaders{
+		tasks: dataloader.NewBatchedLoader(r.batchTasks(userID)),
+		users: dataloader.NewBatchedLoader(r.batchUsers(userID)),
+	}
+}
+
+// batchTasks carga en una sola consulta las tareas pedidas durante la ventana del loader.
+// Las tareas que no existen, están en la papelera o pertenecen a otro usuario resultan en nil.
+func (r *Resolver) batchTasks(userID uint) dataloader.BatchFunc[uint, *domain.Task] {
+	return func(ctx context.Context, ids []uint) []*dataloader.Result[*domain.Task] {
+		results := make([]*dataloader.Result[*domain.Task], len(ids))
+		tasks, err := r.tasks.GetByIDs(ctx, userID, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*domain.Task]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[uint]*domain.Task, len(tasks))
+		for i := range tasks {
+			byID[tasks[i].ID] = &tasks[i]
+		}
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*domain.Task]{Data: byID[id]}
+		}
+		return results
+	}
+}
+
+// batchUsers carga los usuarios pedidos durante la ventana del loader.
+// Solo se expone el usuario autenticado: es el dueño de todas las tareas visibles
+// y el autor de su historial, así que cualquier otro ID resulta en nil.
+func (r *Resolver) batchUsers(userID uint) dataloader.BatchFunc[uint, *domain.User] {
+	return func(ctx context.Context, ids []uint) []*dataloader.Result[*domain.User] {
+		results := make([]*dataloader.Result[*domain.User], len(ids))
+		var user *domain.User
+		var err error
+		for i, id := range ids {
+			if id == userID && user == nil && err == nil {
+				user, err = r.users.GetUserByID(ctx, userID)
+			}
+			switch {
+			case id != userID:
+				results[i] = &dataloader.Result[*domain.User]{}
+			case err != nil:
+				results[i] = &dataloader.Result[*domain.User]{Error: err}
+			default:
+				results[i] = &dataloader.Result[*domain.User]{Data: user}
+			}
+		}
+		return results
+	}
+}
+
+// primeTasks guarda en la caché del loader las tareas ya leídas por una consulta de lista
+func (l *loaders) primeTasks(ctx context.Context, tasks []domain.Task) {
+	for i := range tasks {
+		l.tasks.Prime(ctx, tasks[i].ID, &tasks[i])
+	}
+}
@@ -0,0 +1,364 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/gin-gonic/gin/binding"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Límites de tamaño de las listas, iguales a los de la API REST
+const (
+	defaultTaskLimit     = 50
+	maxTaskLimit         = 500
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
+	defaultActivityLimit = 20
+	maxActivityLimit     = 100
+)
+
+// Resolver es la raíz de las consultas y mutaciones del esquema
+type Resolver struct {
+	tasks    service.TaskService
+	filters  service.FilterService
+	activity service.ActivityService
+	users    service.AuthServiceInterface
+}
+
+// limit normaliza el límite pedido entre 1 y max
+func limit(value int32, max int) int {
+	if value < 1 {
+		return 1
+	}
+	if int(value) > max {
+		return max
+	}
+	return int(value)
+}
+
+// validate valida una petición con las mismas reglas de binding que la API REST
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return newError(CodeBadUserInput, "Datos inválidos: "+err.Error())
+	}
+	return nil
+}
+
+// taskList convierte las tareas en resolvers, limitadas a n, y las agrega a la caché del loader
+func (r *Resolver) taskList(ctx context.Context, tasks []domain.Task, n int) []*taskResolver {
+	if len(tasks) > n {
+		tasks = tasks[:n]
+	}
+	fromContext(ctx).loaders.primeTasks(ctx, tasks)
+	resolvers := make([]*taskResolver, len(tasks))
+	for i := range tasks {
+		resolvers[i] = &taskResolver{root: r, task: &tasks[i]}
+	}
+	return resolvers
+}
+
+// loadUser obtiene un usuario mediante el loader de usuarios
+func (r *Resolver) loadUser(ctx context.Context, id uint) (*userResolver, error) {
+	user, err := fromContext(ctx).loaders.users.Load(ctx, id)()
+	if err != nil {
+		return nil, toError(err)
+	}
+	if user == nil {
+		return nil, newError(CodeNotFound, "usuario no encontrado")
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// ownTask obtiene una tarea del usuario autenticado
+func (r *Resolver) ownTask(ctx context.Context, id graphql.ID) (*domain.Task, error) {
+	taskID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	task, err := fromContext(ctx).loaders.tasks.Load(ctx, taskID)()
+	if err != nil {
+		return nil, toError(err)
+	}
+	return task, nil
+}
+
+// Me obtiene el usuario autenticado
+func (r *Resolver) Me(ctx context.Context) (*userResolver, error) {
+	return r.loadUser(ctx, fromContext(ctx).userID)
+}
+
+// Task obtiene una tarea del usuario por ID
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	task, err := r.ownTask(ctx, args.ID)
+	if err != nil || task == nil {
+		return nil, err
+	}
+	return &taskResolver{root: r, task: task}, nil
+}
+
+// tasksArgs son los argumentos de las listas de tareas
+type tasksArgs struct {
+	Filter *string
+	Limit  int32
+}
+
+// Tasks obtiene las tareas del usuario, opcionalmente filtradas
+func (r *Resolver) Tasks(ctx context.Context, args tasksArgs) ([]*taskResolver, error) {
+	userID := fromContext(ctx).userID
+	var tasks []domain.Task
+	var err error
+	if args.Filter != nil && *args.Filter != "" {
+		tasks, err = r.tasks.Filter(ctx, userID, *args.Filter)
+	} else {
+		tasks, err = r.tasks.GetByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, toError(err)
+	}
+	return r.taskList(ctx, tasks, limit(args.Limit, maxTaskLimit)), nil
+}
+
+// Trash obtiene las tareas en la papelera.
+// No pasan por la caché del loader, que solo contiene tareas activas.
+func (r *Resolver) Trash(ctx context.Context, args struct{ Limit int32 }) ([]*taskResolver, error) {
+	tasks, err := r.tasks.GetTrash(ctx, fromContext(ctx).userID)
+	if err != nil {
+		return nil, toError(err)
+	}
+	if n := limit(args.Limit, maxTaskLimit); len(tasks) > n {
+		tasks = tasks[:n]
+	}
+	resolvers := make([]*taskResolver, len(tasks))
+	for i := range tasks {
+		resolvers[i] = &taskResolver{root: r, task: &tasks[i]}
+	}
+	return resolvers, nil
+}
+
+// Search busca tareas por texto
+func (r *Resolver) Search(ctx context.Context, args struct {
+	Query string
+	Lang  *string
+	Limit int32
+}) ([]*taskResolver, error) {
+	var lang string
+	if args.Lang != nil {
+		lang = *args.Lang
+	}
+	n := limit(args.Limit, maxSearchLimit)
+	results, err := r.tasks.Search(ctx, fromContext(ctx).userID, args.Query, lang, n)
+	if err != nil {
+		return nil, toError(err)
+	}
+	tasks := make([]domain.Task, len(results))
+	for i := range results {
+		tasks[i] = results[i].Task
+	}
+	return r.taskList(ctx, tasks, n), nil
+}
+
+// SavedFilters obtiene los filtros guardados del usuario
+func (r *Resolver) SavedFilters(ctx context.Context) ([]*savedFilterResolver, error) {
+	filters, err := r.filters.GetByUserID(ctx, fromContext(ctx).userID)
+	if err != nil {
+		return nil, toError(err)
+	}
+	resolvers := make([]*savedFilterResolver, len(filters))
+	for i := range filters {
+		resolvers[i] = &savedFilterResolver{root: r, filter: &filters[i]}
+	}
+	return resolvers, nil
+}
+
+// SavedFilter obtiene un filtro guardado del usuario por ID
+func (r *Resolver) SavedFilter(ctx context.Context, args struct{ ID graphql.ID }) (*savedFilterResolver, error) {
+	filterID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := r.filters.GetByID(ctx, filterID, fromContext(ctx).userID)
+	if errors.Is(err, service.ErrFilterNotFound) || errors.Is(err, service.ErrFilterUnauthorized) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &savedFilterResolver{root: r, filter: filter}, nil
+}
+
+// Activity obtiene el historial de actividad del usuario
+func (r *Resolver) Activity(ctx context.Context, args struct {
+	Page  int32
+	Limit int32
+}) ([]*activityResolver, error) {
+	page := 1
+	if args.Page > 1 {
+		page = int(args.Page)
+	}
+	result, err := r.activity.GetUserFeed(ctx, fromContext(ctx).userID, page, limit(args.Limit, maxActivityLimit))
+	if err != nil {
+		return nil, toError(err)
+	}
+	resolvers := make([]*activityResolver, len(result.Items))
+	for i := range result.Items {
+		resolvers[i] = &activityResolver{root: r, activity: &result.Items[i]}
+	}
+	return resolvers, nil
+}
+
+// CreateTask crea una tarea
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Input domain.CreateTask }) (*taskResolver, error) {
+	if err := validate(&args.Input); err != nil {
+		return nil, err
+	}
+	task, err := r.tasks.Create(ctx, fromContext(ctx).userID, &args.Input)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &taskResolver{root: r, task: task}, nil
+}
+
+// expectedVersion convierte la versión esperada opcional; 0 indica que no se verifica
+func expectedVersion(version *int32) (uint, error) {
+	if version == nil {
+		return 0, nil
+	}
+	if *version < 1 {
+		return 0, newError(CodeBadUserInput, "la versión debe ser mayor que cero")
+	}
+	return uint(*version), nil
+}
+
+// UpdateTask actualiza una tarea
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   domain.UpdateTask
+	Version *int32
+}) (*taskResolver, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(args.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(&args.Input); err != nil {
+		return nil, err
+	}
+	task, err := r.tasks.Update(ctx, taskID, fromContext(ctx).userID, &args.Input, version)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &taskResolver{root: r, task: task}, nil
+}
+
+// DeleteTask mueve una tarea a la papelera
+func (r *Resolver) DeleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (bool, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	version, err := expectedVersion(args.Version)
+	if err != nil {
+		return false, err
+	}
+	if err := r.tasks.Delete(ctx, taskID, fromContext(ctx).userID, version); err != nil {
+		return false, toError(err)
+	}
+	return true, nil
+}
+
+// RestoreTask restaura una tarea de la papelera
+func (r *Resolver) RestoreTask(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := r.tasks.Restore(ctx, taskID, fromContext(ctx).userID)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &taskResolver{root: r, task: task}, nil
+}
+
+// MoveTask reordena una tarea entre afterId y beforeId
+func (r *Resolver) MoveTask(ctx context.Context, args struct {
+	ID       graphql.ID
+	AfterID  *graphql.ID
+	BeforeID *graphql.ID
+}) (*taskResolver, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	var req domain.MoveTask
+	if args.AfterID != nil {
+		afterID, err := parseID(*args.AfterID)
+		if err != nil {
+			return nil, err
+		}
+		req.AfterID = &afterID
+	}
+	if args.BeforeID != nil {
+		beforeID, err := parseID(*args.BeforeID)
+		if err != nil {
+			return nil, err
+		}
+		req.BeforeID = &beforeID
+	}
+	task, err := r.tasks.Move(ctx, taskID, fromContext(ctx).userID, &req)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &taskResolver{root: r, task: task}, nil
+}
+
+// CreateSavedFilter guarda un filtro
+func (r *Resolver) CreateSavedFilter(ctx context.Context, args struct{ Input domain.CreateFilter }) (*savedFilterResolver, error) {
+	if err := validate(&args.Input); err != nil {
+		return nil, err
+	}
+	filter, err := r.filters.Create(ctx, fromContext(ctx).userID, &args.Input)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &savedFilterResolver{root: r, filter: filter}, nil
+}
+
+// UpdateSavedFilter actualiza un filtro guardado
+func (r *Resolver) UpdateSavedFilter(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input domain.UpdateFilter
+}) (*savedFilterResolver, error) {
+	filterID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(&args.Input); err != nil {
+		return nil, err
+	}
+	filter, err := r.filters.Update(ctx, filterID, fromContext(ctx).userID, &args.Input)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return &savedFilterResolver{root: r, filter: filter}, nil
+}
+
+// DeleteSavedFilter elimina un filtro guardado
+func (r *Resolver) DeleteSavedFilter(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	filterID, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.filters.Delete(ctx, filterID, fromContext(ctx).userID); err != nil {
+		return false, toError(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Timestamp es una marca de tiempo en segundos Unix, igual que en la API REST
+type Timestamp int64
+
+// ImplementsGraphQLType asocia el tipo con el escalar Timestamp del esquema
+func (Timestamp) ImplementsGraphQLType(name string) bool {
+	return name == "Timestamp"
+}
+
+// UnmarshalGraphQL convierte un argumento de entrada en Timestamp
+func (t *Timestamp) UnmarshalGraphQL(input interface{}) error {
+	switch value := input.(type) {
+	case int32:
+		*t = Timestamp(value)
+	case int64:
+		*t = Timestamp(value)
+	case float64:
+		*t = Timestamp(value)
+	default:
+		return fmt.Errorf("valor de Timestamp inválido: %v", input)
+	}
+	return nil
+}
+
+// toID convierte un ID numérico al tipo ID de GraphQL
+func toID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// parseID convierte un ID de GraphQL en un ID numérico
+func parseID(id graphql.ID) (uint, error) {
+	value, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || value == 0 {
+		return 0, newError(CodeBadUserInput, "ID inválido: "+string(id))
+	}
+	return uint(value), nil
+}
+
+// userResolver resuelve los campos del tipo User
+type userResolver struct {
+	root *Resolver
+	user *domain.User
+}
+
+func (u *userResolver) ID() graphql.ID       { return toID(u.user.ID) }
+func (u *userResolver) FullName() string     { return u.user.FullName }
+func (u *userResolver) Email() string        { return u.user.Email }
+func (u *userResolver) Version() int32       { return int32(u.user.Version) }
+func (u *userResolver) CreatedAt() Timestamp { return Timestamp(u.user.CreatedAt) }
+func (u *userResolver) UpdatedAt() Timestamp { return Timestamp(u.user.UpdatedAt) }
+
+// Tasks obtiene las tareas del usuario
+func (u *userResolver) Tasks(ctx context.Context, args tasksArgs) ([]*taskResolver, error) {
+	return u.root.Tasks(ctx, args)
+}
+
+// SavedFilters obtiene los filtros guardados del usuario
+func (u *userResolver) SavedFilters(ctx context.Context) ([]*savedFilterResolver, error) {
+	return u.root.SavedFilters(ctx)
+}
+
+// taskResolver resuelve los campos del tipo Task
+type taskResolver struct {
+	root *Resolver
+	task *domain.Task
+}
+
+func (t *taskResolver) ID() graphql.ID       { return toID(t.task.ID) }
+func (t *taskResolver) Title() string        { return t.task.Title }
+func (t *taskResolver) Completed() bool      { return t.task.Completed }
+func (t *taskResolver) Position() string     { return t.task.Position }
+func (t *taskResolver) Version() int32       { return int32(t.task.Version) }
+func (t *taskResolver) ClientID() *string    { return t.task.ClientID }
+func (t *taskResolver) CreatedAt() Timestamp { return Timestamp(t.task.CreatedAt) }
+func (t *taskResolver) UpdatedAt() Timestamp { return Timestamp(t.task.UpdatedAt) }
+
+// Owner obtiene el dueño de la tarea mediante el loader de usuarios
+func (t *taskResolver) Owner(ctx context.Context) (*userResolver, error) {
+	return t.root.loadUser(ctx, t.task.UserID)
+}
+
+// savedFilterResolver resuelve los campos del tipo SavedFilter
+type savedFilterResolver struct {
+	root   *Resolver
+	filter *domain.SavedFilter
+}
+
+func (f *savedFilterResolver) ID() graphql.ID       { return toID(f.filter.ID) }
+func (f *savedFilterResolver) Name() string         { return f.filter.Name }
+func (f *savedFilterResolver) Query() string        { return f.filter.Query }
+func (f *savedFilterResolver) CreatedAt() Timestamp { return Timestamp(f.filter.CreatedAt) }
+func (f *savedFilterResolver) UpdatedAt() Timestamp { return Timestamp(f.filter.UpdatedAt) }
+
+// Tasks obtiene las tareas que cumplen el filtro
+func (f *savedFilterResolver) Tasks(ctx context.Context, args struct{ Limit int32 }) ([]*taskResolver, error) {
+	rc := fromContext(ctx)
+	tasks, err := f.root.filters.GetTasks(ctx, f.filter.ID, rc.userID)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return f.root.taskList(ctx, tasks, limit(args.Limit, maxTaskLimit)), nil
+}
+
+// activityResolver resuelve los campos del tipo Activity
+type activityResolver struct {
+	root     *Resolver
+	activity *domain.ActivityResponse
+}
+
+func (a *activityResolver) ID() graphql.ID       { return toID(a.activity.ID) }
+func (a *activityResolver) Action() string       { return a.activity.Action }
+func (a *activityResolver) EntityType() string   { return a.activity.EntityType }
+func (a *activityResolver) EntityID() graphql.ID { return toID(a.activity.EntityID) }
+func (a *activityResolver) CreatedAt() Timestamp { return Timestamp(a.activity.CreatedAt) }
+
+// Changes devuelve los cambios registrados como JSON, o null si la acción no los tiene
+func (a *activityResolver) Changes() *string {
+	if len(a.activity.Changes) == 0 {
+		return nil
+	}
+	changes := string(a.activity.Changes)
+	return &changes
+}
+
+// Actor obtiene el usuario que realizó la acción mediante el loader de usuarios
+func (a *activityResolver) Actor(ctx context.Context) (*userResolver, error) {
+	user, err := fromContext(ctx).loaders.users.Load(ctx, a.activity.ActorID)()
+	if err != nil {
+		return nil, toError(err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	return &userResolver{root: a.root, user: user}, nil
+}
+
+// Task obtiene la tarea afectada mediante el loader de tareas, que agrupa
+// en una sola consulta las tareas de todos los registros de la página
+func (a *activityResolver) Task(ctx context.Context) (*taskResolver, error) {
+	if a.activity.EntityType != domain.EntityTask {
+		return nil, nil
+	}
+	task, err := fromContext(ctx).loaders.tasks.Load(ctx, a.activity.EntityID)()
+	if err != nil {
+		return nil, toError(err)
+	}
+	if task == nil {
+		return nil, nil
+	}
+	return &taskResolver{root: a.root, task: task}, nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	activityService service.ActivityService
+}
+
+// NewActivityHandler crea una nueva instancia de ActivityHandler
+func NewActivityHandler(activityService service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// TaskActivity godoc
+// @Summary      Historial de una tarea
+// @Description  Obtiene el historial de cambios de una tarea del usuario autenticado
+// @Tags         Activity
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la tarea"
+// @Param        page query int false "Número de página" default(1)
+// @Param        limit query int false "Registros por página (máx. 100)" default(20)
+// @Success      200 {object} utils.Response{data=domain.ActivityPage} "Historial obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Router       /tasks/{id}/activity [get]
+func (h *ActivityHandler) TaskActivity(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la tarea
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de tarea inválido")
+		return
+	}
+
+	page, limit := getPagination(c)
+	activities, err := h.activityService.GetTaskActivity(c.Request.Context(), uint(taskID), userID, page, limit)
+	if err != nil {
+		switch err {
+		case service.ErrTaskNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
+		case service.ErrTaskUnauthorized:
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para ver esta tarea")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener el historial: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Historial obtenido exitosamente", activities)
+}
+
+// Feed godoc
+// @Summary      Actividad del usuario
+// @Description  Obtiene el historial de acciones realizadas por el usuario autenticado
+// @Tags         Activity
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Número de página" default(1)
+// @Param        limit query int false "Registros por página (máx. 100)" default(20)
+// @Success      200 {object} utils.Response{data=domain.ActivityPage} "Actividad obtenida"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /activity [get]
+func (h *ActivityHandler) Feed(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	page, limit := getPagination(c)
+	activities, err := h.activityService.GetUserFeed(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener la actividad: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Actividad obtenida exitosamente", activities)
+}
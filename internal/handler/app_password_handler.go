@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AppPasswordHandler struct {
+	appPasswordService service.AppPasswordService
+}
+
+// NewAppPasswordHandler crea una nueva instancia de AppPasswordHandler
+func NewAppPasswordHandler(appPasswordService service.AppPasswordService) *AppPasswordHandler {
+	return &AppPasswordHandler{appPasswordService: appPasswordService}
+}
+
+// Create godoc
+// @Summary      Crear contraseña de aplicación
+// @Description  Genera una contraseña para clientes que se autentican con HTTP Basic (por ejemplo CalDAV). La contraseña solo se muestra en esta respuesta.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateAppPassword true "Nombre de la contraseña"
+// @Success      201 {object} utils.Response{data=domain.AppPasswordCreatedResponse} "Contraseña creada"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /auth/app-passwords [post]
+func (h *AppPasswordHandler) Create(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.CreateAppPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	password, secret, err := h.appPasswordService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al crear la contraseña de aplicación: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Contraseña de aplicación creada exitosamente", domain.AppPasswordCreatedResponse{
+		AppPasswordResponse: password.ToResponse(),
+		Password:            secret,
+	})
+}
+
+// GetAll godoc
+// @Summary      Listar contraseñas de aplicación
+// @Description  Obtiene las contraseñas de aplicación del usuario autenticado, sin sus secretos
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]domain.AppPasswordResponse} "Lista de contraseñas"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /auth/app-passwords [get]
+func (h *AppPasswordHandler) GetAll(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	passwords, err := h.appPasswordService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener las contraseñas de aplicación: "+err.Error())
+		return
+	}
+
+	// Convertir a respuesta
+	passwordsResponse := make([]domain.AppPasswordResponse, 0, len(passwords))
+	for _, password := range passwords {
+		passwordsResponse = append(passwordsResponse, password.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Contraseñas de aplicación obtenidas exitosamente", passwordsResponse)
+}
+
+// Delete godoc
+// @Summary      Revocar contraseña de aplicación
+// @Description  Revoca una contraseña de aplicación; los clientes que la usan dejan de autenticarse
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la contraseña"
+// @Success      200 {object} utils.Response "Contraseña revocada"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Contraseña no encontrada"
+// @Router       /auth/app-passwords/{id} [delete]
+func (h *AppPasswordHandler) Delete(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la contraseña
+	passwordID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de contraseña inválido")
+		return
+	}
+
+	if err := h.appPasswordService.Delete(c.Request.Context(), uint(passwordID), userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAppPasswordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "Contraseña de aplicación no encontrada")
+		case errors.Is(err, service.ErrAppPasswordUnauthorized):
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para revocar esta contraseña de aplicación")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al revocar la contraseña de aplicación: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Contraseña de aplicación revocada exitosamente", nil)
+}
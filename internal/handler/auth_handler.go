@@ -92,6 +92,44 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// Refresh godoc
+// @Summary      Renovar token
+// @Description  Emite un nuevo token JWT para el usuario autenticado, con la expiración completa.
+// @Description  El token actual debe seguir siendo válido: un token expirado requiere iniciar sesión de nuevo.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=object{token=string,user=domain.UserResponse}} "Token renovado"
+// @Failure      401 {object} utils.Response "No autenticado o usuario eliminado"
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	token, user, err := h.authService.RefreshToken(c.Request.Context(), userID)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no encontrado")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al renovar el token: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token renovado exitosamente", gin.H{
+		"token": token,
+		"user": gin.H{
+			"id":        user.ID,
+			"full_name": user.FullName,
+			"email":     user.Email,
+		},
+	})
+}
+
 // Profile godoc
 // @Summary      Obtener perfil
 // @Description  Obtiene la información del usuario autenticado
@@ -99,7 +137,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        If-None-Match header string false "ETag del perfil conocido por el cliente"
 // @Success      200 {object} utils.Response{data=domain.UserResponse} "Perfil obtenido"
+// @Success      304 "El perfil no cambió"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      404 {object} utils.Response "Usuario no encontrado"
 // @Router       /auth/profile [get]
@@ -118,6 +158,10 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, versionETag(user.Version)) {
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Perfil obtenido exitosamente", user.ToResponse())
 }
 
@@ -129,9 +173,13 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        request body domain.UserUpdate true "Datos a actualizar"
+// @Param        If-Match header string false "ETag de la versión que se modifica"
 // @Success      200 {object} utils.Response{data=domain.UserResponse} "Perfil actualizado"
 // @Failure      400 {object} utils.Response "Datos inválidos"
 // @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      409 {object} utils.Response "Modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      428 {object} utils.Response "If-Match requerido"
 // @Router       /auth/profile [put]
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	// Obtener ID del usuario del contexto
@@ -147,12 +195,77 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.UpdateProfile(c.Request.Context(), userID, &req)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(c.Request.Context(), userID, &req, version)
+	if err != nil {
+		switch err {
+		case service.ErrPreconditionFailed:
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		case service.ErrConcurrentModification:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	c.Header("ETag", versionETag(user.Version))
+	utils.SuccessResponse(c, http.StatusOK, "Perfil actualizado exitosamente", user.ToResponse())
+}
+
+// PatchProfile godoc
+// @Summary      Modificar perfil parcialmente
+// @Description  Aplica un JSON Merge Patch (RFC 7396) o un JSON Patch (RFC 6902) al perfil del usuario autenticado. Para cambiar la contraseña se agrega el campo password.
+// @Tags         Auth
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.UserDocument true "Merge patch o lista de operaciones JSON Patch"
+// @Param        If-Match header string false "ETag de la versión que se modifica"
+// @Success      200 {object} utils.Response{data=domain.UserResponse} "Perfil actualizado"
+// @Failure      400 {object} utils.Response "Patch inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      409 {object} utils.Response "Operación test fallida o modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      415 {object} utils.Response "Tipo de contenido no soportado"
+// @Failure      422 {object} utils.Response "El documento resultante no es válido"
+// @Failure      428 {object} utils.Response "If-Match requerido"
+// @Router       /auth/profile [patch]
+func (h *AuthHandler) PatchProfile(c *gin.Context) {
+	// Obtener ID del usuario del contexto
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "No se pudo leer el cuerpo de la petición")
+		return
+	}
+
+	version, err := ifMatchVersion(c)
 	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	user, err := h.authService.PatchProfile(c.Request.Context(), userID, c.ContentType(), body, version)
+	if err != nil {
+		if handlePatchError(c, err) {
+			return
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	c.Header("ETag", versionETag(user.Version))
 	utils.SuccessResponse(c, http.StatusOK, "Perfil actualizado exitosamente", user.ToResponse())
 }
 
@@ -163,8 +276,12 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        If-Match header string false "ETag de la versión que se elimina"
 // @Success      200 {object} utils.Response "Cuenta eliminada"
 // @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      409 {object} utils.Response "Modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      428 {object} utils.Response "If-Match requerido"
 // @Failure      500 {object} utils.Response "Error interno"
 // @Router       /auth/profile [delete]
 func (h *AuthHandler) DeleteAccount(c *gin.Context) {
@@ -175,8 +292,21 @@ func (h *AuthHandler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.DeleteAccount(c.Request.Context(), userID); err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al eliminar la cuenta: "+err.Error())
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	if err := h.authService.DeleteAccount(c.Request.Context(), userID, version); err != nil {
+		switch err {
+		case service.ErrPreconditionFailed:
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		case service.ErrConcurrentModification:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al eliminar la cuenta: "+err.Error())
+		}
 		return
 	}
 
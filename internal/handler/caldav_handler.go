@@ -0,0 +1,618 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/caldav"
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/exporter"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCalDAVBody es el tamaño máximo del cuerpo de una petición CalDAV
+const maxCalDAVBody = 1 << 20
+
+// Métodos HTTP de WebDAV que no define net/http
+const (
+	MethodPropfind = "PROPFIND"
+	MethodReport   = "REPORT"
+)
+
+// calDAVMethods son los métodos que acepta el servidor CalDAV
+var calDAVMethods = []string{http.MethodOptions, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, MethodPropfind, MethodReport}
+
+// CalDAVHandler expone las tareas del usuario como una colección CalDAV de VTODO.
+// Las lecturas y escrituras pasan por TaskService, igual que en la API REST.
+type CalDAVHandler struct {
+	taskService service.TaskService
+}
+
+// NewCalDAVHandler crea una nueva instancia de CalDAVHandler
+func NewCalDAVHandler(taskService service.TaskService) *CalDAVHandler {
+	return &CalDAVHandler{taskService: taskService}
+}
+
+// Options responde a OPTIONS anunciando las capacidades DAV. No requiere autenticación.
+func (h *CalDAVHandler) Options(c *gin.Context) {
+	c.Header("DAV", "1, 3, calendar-access")
+	c.Header("Allow", strings.Join(calDAVMethods, ", "))
+	c.Status(http.StatusOK)
+}
+
+// WellKnown redirige /.well-known/caldav a la raíz del servidor (RFC 6764)
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, caldav.RootPath)
+}
+
+// Serve atiende las peticiones autenticadas bajo /caldav según el método y la ruta
+func (h *CalDAVHandler) Serve(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.String(http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	kind, name := caldav.Resolve(c.Request.URL.Path)
+	if kind == caldav.KindUnknown {
+		c.String(http.StatusNotFound, "Recurso no encontrado")
+		return
+	}
+
+	switch c.Request.Method {
+	case MethodPropfind:
+		h.propfind(c, userID, kind, name)
+	case MethodReport:
+		h.report(c, userID, kind)
+	case http.MethodGet, http.MethodHead:
+		h.get(c, userID, kind, name)
+	case http.MethodPut:
+		h.put(c, userID, kind, name)
+	case http.MethodDelete:
+		h.delete(c, userID, kind, name)
+	default:
+		c.Header("Allow", strings.Join(calDAVMethods, ", "))
+		c.String(http.StatusMethodNotAllowed, "Método no soportado")
+	}
+}
+
+// propfind devuelve las propiedades del recurso y, con Depth 1, las de sus hijos.
+// Depth infinity se trata como 1.
+func (h *CalDAVHandler) propfind(c *gin.Context, userID uint, kind int, name string) {
+	body, ok := readCalDAVBody(c)
+	if !ok {
+		return
+	}
+	req, err := caldav.ParsePropfind(body)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	props := &calDAVProps{handler: h, c: c, userID: userID, req: req}
+	ms := caldav.NewMultistatus()
+	depth := c.GetHeader("Depth")
+
+	switch kind {
+	case caldav.KindResource:
+		task, err := h.findTask(c, userID, name)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		props.task(ms, task)
+	case caldav.KindCollection:
+		if err := props.collection(ms); err != nil {
+			h.handleError(c, err)
+			return
+		}
+		if depth != "0" {
+			tasks, err := h.taskService.GetByUserID(ctx, userID)
+			if err != nil {
+				h.handleError(c, err)
+				return
+			}
+			for i := range tasks {
+				props.task(ms, &tasks[i])
+			}
+		}
+	case caldav.KindHome:
+		props.home(ms)
+		if depth != "0" {
+			if err := props.collection(ms); err != nil {
+				h.handleError(c, err)
+				return
+			}
+		}
+	case caldav.KindPrincipal:
+		props.principal(ms)
+	case caldav.KindRoot:
+		props.root(ms)
+		if depth != "0" {
+			props.principal(ms)
+			props.home(ms)
+		}
+	}
+
+	writeMultistatus(c, ms)
+}
+
+// report atiende calendar-query, calendar-multiget y sync-collection sobre la colección
+func (h *CalDAVHandler) report(c *gin.Context, userID uint, kind int) {
+	if kind != caldav.KindCollection {
+		writeDAVError(c, http.StatusForbidden, caldav.NSDAV, "supported-report")
+		return
+	}
+	body, ok := readCalDAVBody(c)
+	if !ok {
+		return
+	}
+	report, err := caldav.ParseReport(body)
+	if errors.Is(err, caldav.ErrUnsupportedReport) {
+		writeDAVError(c, http.StatusForbidden, caldav.NSDAV, "supported-report")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	props := &calDAVProps{handler: h, c: c, userID: userID, req: &report.Props}
+	ms := caldav.NewMultistatus()
+
+	switch report.Type {
+	case caldav.ReportCalendarQuery:
+		tasks, err := h.taskService.GetByUserID(ctx, userID)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		for i := range tasks {
+			calendar, err := ical.Parse(strings.NewReader(caldav.Calendar(&tasks[i])))
+			if err != nil {
+				h.handleError(c, err)
+				return
+			}
+			if !caldav.Match(report.Filter, calendar) {
+				continue
+			}
+			props.task(ms, &tasks[i])
+		}
+
+	case caldav.ReportCalendarMultiget:
+		for _, href := range report.Hrefs {
+			name, ok := caldav.ResolveHref(href)
+			if !ok {
+				ms.Status(href, http.StatusNotFound)
+				continue
+			}
+			task, err := h.findTask(c, userID, name)
+			if errors.Is(err, service.ErrTaskNotFound) {
+				ms.Status(href, http.StatusNotFound)
+				continue
+			}
+			if err != nil {
+				h.handleError(c, err)
+				return
+			}
+			props.task(ms, task)
+		}
+
+	case caldav.ReportSyncCollection:
+		if !h.syncCollection(c, userID, report, props, ms) {
+			return
+		}
+	}
+
+	writeMultistatus(c, ms)
+}
+
+// syncCollection agrega a la respuesta las tareas que cambiaron desde el token (RFC 6578).
+// Un token inválido o demasiado antiguo responde 403 valid-sync-token para que el cliente
+// vuelva a sincronizar desde cero. Devuelve false si ya se respondió.
+func (h *CalDAVHandler) syncCollection(c *gin.Context, userID uint, report *caldav.Report, props *calDAVProps, ms *caldav.Multistatus) bool {
+	token := ""
+	if report.SyncToken != "" {
+		var ok bool
+		if token, ok = strings.CutPrefix(report.SyncToken, caldav.SyncTokenPrefix); !ok || token == "" {
+			writeDAVError(c, http.StatusForbidden, caldav.NSDAV, "valid-sync-token")
+			return false
+		}
+	}
+
+	limit := domain.DefaultSyncLimit
+	if report.Limit > 0 && report.Limit < limit {
+		limit = report.Limit
+	}
+
+	changes, err := h.taskService.Changes(c.Request.Context(), userID, token, limit)
+	if errors.Is(err, service.ErrInvalidSyncToken) || (err == nil && token != "" && changes.Reset) {
+		writeDAVError(c, http.StatusForbidden, caldav.NSDAV, "valid-sync-token")
+		return false
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return false
+	}
+
+	for _, change := range changes.Changes {
+		if change.Deleted {
+			ms.Status(caldav.ResourceHref(change.ID, change.ClientID), http.StatusNotFound)
+			continue
+		}
+		task := taskFromResponse(change.Task)
+		props.task(ms, task)
+	}
+	// 507 en la colección indica que la respuesta está truncada y hay que pedir más cambios
+	if changes.HasMore {
+		ms.Status(caldav.CollectionPath, http.StatusInsufficientStorage)
+	}
+	ms.SyncToken(caldav.SyncTokenPrefix + changes.Token)
+	return true
+}
+
+// get devuelve una tarea como VCALENDAR o, en la colección, todas las tareas del usuario
+func (h *CalDAVHandler) get(c *gin.Context, userID uint, kind int, name string) {
+	switch kind {
+	case caldav.KindResource:
+		task, err := h.findTask(c, userID, name)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		c.Header("ETag", versionETag(task.Version))
+		c.Header("Last-Modified", time.Unix(task.UpdatedAt, 0).UTC().Format(http.TimeFormat))
+		c.Data(http.StatusOK, caldav.ContentType, []byte(caldav.Calendar(task)))
+
+	case caldav.KindCollection:
+		c.Header("Content-Type", ical.MediaType)
+		if c.Request.Method == http.MethodHead {
+			c.Status(http.StatusOK)
+			return
+		}
+		buffer := bufio.NewWriterSize(c.Writer, 32<<10)
+		writer, _ := exporter.New(exporter.FormatICS, buffer)
+		err := h.taskService.Export(c.Request.Context(), userID, "", writer)
+		if err == nil {
+			err = buffer.Flush()
+		}
+		if err != nil {
+			if c.Writer.Written() {
+				// La respuesta ya empezó: solo queda interrumpirla
+				log.Println("Error al exportar la colección CalDAV:", err)
+				c.Abort()
+				return
+			}
+			h.handleError(c, err)
+		}
+
+	default:
+		c.Header("Allow", "OPTIONS, PROPFIND")
+		c.String(http.StatusMethodNotAllowed, "El recurso no admite GET")
+	}
+}
+
+// put crea o actualiza una tarea a partir de una VTODO. Solo se guardan el título (SUMMARY)
+// y el estado (STATUS o COMPLETED); el resto de propiedades se ignora, por lo que la respuesta
+// no incluye ETag y el cliente debe volver a leer el recurso.
+func (h *CalDAVHandler) put(c *gin.Context, userID uint, kind int, name string) {
+	if kind != caldav.KindResource {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		c.String(http.StatusMethodNotAllowed, "Solo se pueden escribir recursos de la colección")
+		return
+	}
+	body, ok := readCalDAVBody(c)
+	if !ok {
+		return
+	}
+	todo, err := caldav.ParseTodo(body)
+	if errors.Is(err, caldav.ErrUnsupportedComponent) {
+		writeDAVError(c, http.StatusForbidden, caldav.NSCalDAV, "supported-calendar-component")
+		return
+	}
+	if err != nil {
+		writeDAVError(c, http.StatusForbidden, caldav.NSCalDAV, "valid-calendar-data")
+		return
+	}
+
+	ctx := c.Request.Context()
+	task, err := h.findTask(c, userID, name)
+	if err != nil && !errors.Is(err, service.ErrTaskNotFound) && !errors.Is(err, errTaskDeleted) {
+		h.handleError(c, err)
+		return
+	}
+	exists := err == nil
+
+	if exists && c.GetHeader("If-None-Match") == "*" {
+		c.Status(http.StatusPreconditionFailed)
+		return
+	}
+	if !exists && c.GetHeader("If-Match") != "" {
+		c.Status(http.StatusPreconditionFailed)
+		return
+	}
+
+	update := &domain.UpdateTask{Title: &todo.Title, Completed: &todo.Completed}
+
+	if exists {
+		version, err := ifMatchVersion(c)
+		if err == nil {
+			_, err = h.taskService.Update(ctx, task.ID, userID, update, version)
+		}
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	// Volver a crear un recurso eliminado restaura la tarea que tenía ese nombre
+	if task != nil {
+		if _, err := h.taskService.RestoreAndUpdate(ctx, task.ID, userID, update); err != nil {
+			h.handleError(c, err)
+			return
+		}
+		c.Status(http.StatusCreated)
+		return
+	}
+
+	// Los nombres task-<id> identifican a las tareas creadas desde la API
+	if _, reserved := caldav.TaskIDFromName(name); reserved {
+		c.String(http.StatusForbidden, "El nombre del recurso está reservado")
+		return
+	}
+
+	result, err := h.taskService.Sync(ctx, userID, &domain.SyncRequest{Mutations: []domain.SyncMutation{{
+		Op:       domain.SyncOpCreate,
+		ClientID: name,
+		Fields:   domain.SyncFields{Title: &todo.Title, Completed: &todo.Completed},
+	}}})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if created := result.Results[0]; created.Status == domain.SyncStatusRejected {
+		c.String(http.StatusConflict, created.Error)
+		return
+	}
+	c.Status(http.StatusCreated)
+}
+
+// delete elimina una tarea; queda en la papelera como cualquier tarea eliminada
+func (h *CalDAVHandler) delete(c *gin.Context, userID uint, kind int, name string) {
+	if kind != caldav.KindResource {
+		c.Header("Allow", "OPTIONS, PROPFIND, REPORT")
+		c.String(http.StatusMethodNotAllowed, "Solo se pueden eliminar recursos de la colección")
+		return
+	}
+
+	task, err := h.findTask(c, userID, name)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	version, err := ifMatchVersion(c)
+	if err == nil {
+		err = h.taskService.Delete(c.Request.Context(), task.ID, userID, version)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// errTaskDeleted indica que el nombre pertenece a una tarea que está en la papelera
+var errTaskDeleted = errors.New("la tarea fue eliminada")
+
+// findTask obtiene la tarea del usuario que corresponde al nombre de un recurso. Si el nombre
+// pertenece a una tarea eliminada devuelve la tarea junto con errTaskDeleted.
+func (h *CalDAVHandler) findTask(c *gin.Context, userID uint, name string) (*domain.Task, error) {
+	ctx := c.Request.Context()
+
+	task, err := h.taskService.GetByClientID(ctx, userID, name)
+	if err == nil {
+		if task.DeletedAt.Valid {
+			return task, errTaskDeleted
+		}
+		return task, nil
+	}
+	if !errors.Is(err, service.ErrTaskNotFound) {
+		return nil, err
+	}
+
+	id, ok := caldav.TaskIDFromName(name)
+	if !ok {
+		return nil, service.ErrTaskNotFound
+	}
+	task, err = h.taskService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Las tareas de otros usuarios no existen para este cliente, y las que tienen client_id usan ese nombre
+	if task.UserID != userID || task.ClientID != nil {
+		return nil, service.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// handleError traduce los errores del servicio de tareas a respuestas CalDAV
+func (h *CalDAVHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, errTaskDeleted):
+		c.String(http.StatusNotFound, "Recurso no encontrado")
+	case errors.Is(err, service.ErrTaskUnauthorized):
+		c.String(http.StatusForbidden, "No tienes permiso para acceder a esta tarea")
+	case errors.Is(err, service.ErrPreconditionFailed):
+		c.Status(http.StatusPreconditionFailed)
+	case errors.Is(err, service.ErrConcurrentModification):
+		c.String(http.StatusConflict, err.Error())
+	default:
+		c.String(http.StatusInternalServerError, "Error interno: "+err.Error())
+	}
+}
+
+// calDAVProps escribe las propiedades de cada tipo de recurso según lo que pidió el cliente
+type calDAVProps struct {
+	handler *CalDAVHandler
+	c       *gin.Context
+	userID  uint
+	req     *caldav.PropRequest
+	// syncToken se calcula una sola vez por petición
+	syncToken string
+}
+
+// add escribe un recurso con las propiedades pedidas. lazy contiene las propiedades que solo
+// se incluyen si se piden de forma explícita, como calendar-data.
+func (p *calDAVProps) add(ms *caldav.Multistatus, href string, all []caldav.Property, lazy ...caldav.Property) {
+	if p.req.PropName {
+		names := make([]caldav.Property, 0, len(all)+len(lazy))
+		for _, prop := range append(all, lazy...) {
+			names = append(names, caldav.Prop(prop.Name.Space, prop.Name.Local, ""))
+		}
+		ms.Response(href, names, nil)
+		return
+	}
+	if p.req.AllProp {
+		ms.Response(href, all, nil)
+		return
+	}
+
+	available := make(map[xml.Name]caldav.Property, len(all)+len(lazy))
+	for _, prop := range append(all, lazy...) {
+		available[prop.Name] = prop
+	}
+	var found []caldav.Property
+	var missing []xml.Name
+	for _, name := range p.req.Names {
+		if prop, ok := available[name]; ok {
+			found = append(found, prop)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	ms.Response(href, found, missing)
+}
+
+// principalProps son las propiedades comunes que apuntan al principal del usuario
+func (p *calDAVProps) principalProps() []caldav.Property {
+	return []caldav.Property{
+		caldav.Prop(caldav.NSDAV, "current-user-principal", caldav.Href(caldav.PrincipalPath)),
+		caldav.Prop(caldav.NSDAV, "principal-URL", caldav.Href(caldav.PrincipalPath)),
+		caldav.Prop(caldav.NSCalDAV, "calendar-home-set", caldav.Href(caldav.HomePath)),
+	}
+}
+
+func (p *calDAVProps) root(ms *caldav.Multistatus) {
+	p.add(ms, caldav.RootPath, append(p.principalProps(),
+		caldav.Prop(caldav.NSDAV, "resourcetype", "<d:collection/>"),
+	))
+}
+
+func (p *calDAVProps) principal(ms *caldav.Multistatus) {
+	email := p.c.GetString("userEmail")
+	p.add(ms, caldav.PrincipalPath, append(p.principalProps(),
+		caldav.Prop(caldav.NSDAV, "resourcetype", "<d:principal/>"),
+		caldav.TextProp(caldav.NSDAV, "displayname", email),
+		caldav.Prop(caldav.NSCalDAV, "calendar-user-address-set", caldav.Href("mailto:"+email)),
+	))
+}
+
+func (p *calDAVProps) home(ms *caldav.Multistatus) {
+	p.add(ms, caldav.HomePath, append(p.principalProps(),
+		caldav.Prop(caldav.NSDAV, "resourcetype", "<d:collection/>"),
+	))
+}
+
+func (p *calDAVProps) collection(ms *caldav.Multistatus) error {
+	// El token solo se calcula si se piden getctag o sync-token
+	if p.req.AllProp || p.req.Wants(caldav.NSDAV, "sync-token") || p.req.Wants(caldav.NSCalendarServer, "getctag") {
+		if p.syncToken == "" {
+			token, err := p.handler.taskService.SyncToken(p.c.Request.Context(), p.userID)
+			if err != nil {
+				return err
+			}
+			p.syncToken = token
+		}
+	}
+
+	p.add(ms, caldav.CollectionPath, append(p.principalProps(),
+		caldav.Prop(caldav.NSDAV, "resourcetype", "<d:collection/><c:calendar/>"),
+		caldav.TextProp(caldav.NSDAV, "displayname", caldav.CollectionName),
+		caldav.Prop(caldav.NSDAV, "owner", caldav.Href(caldav.PrincipalPath)),
+		caldav.Prop(caldav.NSDAV, "current-user-privilege-set",
+			"<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>"+
+				"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>"+
+				"<d:privilege><d:unbind/></d:privilege><d:privilege><d:read-current-user-privilege-set/></d:privilege>"),
+		caldav.Prop(caldav.NSDAV, "supported-report-set",
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>"+
+				"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"+
+				"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>"),
+		caldav.Prop(caldav.NSCalDAV, "supported-calendar-component-set", `<c:comp name="VTODO"/>`),
+		caldav.Prop(caldav.NSCalDAV, "supported-calendar-data", `<c:calendar-data content-type="text/calendar" version="2.0"/>`),
+		caldav.TextProp(caldav.NSDAV, "sync-token", caldav.SyncTokenPrefix+p.syncToken),
+		caldav.TextProp(caldav.NSCalendarServer, "getctag", p.syncToken),
+	))
+	return nil
+}
+
+func (p *calDAVProps) task(ms *caldav.Multistatus, task *domain.Task) {
+	p.add(ms, caldav.ResourceHref(task.ID, task.ClientID), []caldav.Property{
+		caldav.Prop(caldav.NSDAV, "resourcetype", ""),
+		caldav.TextProp(caldav.NSDAV, "getetag", versionETag(task.Version)),
+		caldav.TextProp(caldav.NSDAV, "getcontenttype", caldav.ContentType),
+		caldav.TextProp(caldav.NSDAV, "getlastmodified", time.Unix(task.UpdatedAt, 0).UTC().Format(http.TimeFormat)),
+	}, caldav.TextProp(caldav.NSCalDAV, "calendar-data", caldav.Calendar(task)))
+}
+
+// readCalDAVBody lee el cuerpo de la petición con un límite de tamaño. Devuelve false si ya se respondió.
+func readCalDAVBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCalDAVBody))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.String(http.StatusRequestEntityTooLarge, "El cuerpo de la petición es demasiado grande")
+			return nil, false
+		}
+		c.String(http.StatusBadRequest, "Error al leer el cuerpo de la petición: "+err.Error())
+		return nil, false
+	}
+	return body, true
+}
+
+// writeMultistatus responde 207 Multi-Status
+func writeMultistatus(c *gin.Context, ms *caldav.Multistatus) {
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", ms.Bytes())
+}
+
+// writeDAVError responde con una precondición DAV:error
+func writeDAVError(c *gin.Context, status int, space, condition string) {
+	c.Data(status, "application/xml; charset=utf-8", caldav.ErrorBody(space, condition))
+}
+
+// taskFromResponse reconstruye la tarea de un cambio de sincronización para serializarla
+func taskFromResponse(response *domain.TaskResponse) *domain.Task {
+	return &domain.Task{
+		ID:        response.ID,
+		Title:     response.Title,
+		Completed: response.Completed,
+		Position:  response.Position,
+		UserID:    response.UserID,
+		ClientID:  response.ClientID,
+		Version:   response.Version,
+		CreatedAt: response.CreatedAt,
+		UpdatedAt: response.UpdatedAt,
+	}
+}
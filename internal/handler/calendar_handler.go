@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// calendarFeedPath es la ruta pública de los feeds; el token y la extensión .ics se agregan al final
+const calendarFeedPath = "/api/calendar/"
+
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// NewCalendarHandler crea una nueva instancia de CalendarHandler
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// Create godoc
+// @Summary      Crear feed de calendario
+// @Description  Crea una URL secreta de suscripción iCalendar con las tareas que cumplen el filtro. El token solo se muestra en esta respuesta y al rotarlo.
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateCalendarFeed true "Datos del feed"
+// @Success      201 {object} utils.Response{data=domain.CalendarFeedURLResponse} "Feed creado"
+// @Failure      400 {object} utils.Response "Datos o filtro inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /calendar/feeds [post]
+func (h *CalendarHandler) Create(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.CreateCalendarFeed
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	feed, token, err := h.calendarService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Error al crear el feed: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Feed de calendario creado exitosamente", feedURLResponse(feed, token))
+}
+
+// GetAll godoc
+// @Summary      Listar feeds de calendario
+// @Description  Obtiene los feeds de calendario del usuario autenticado, sin sus tokens
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]domain.CalendarFeedResponse} "Lista de feeds"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /calendar/feeds [get]
+func (h *CalendarHandler) GetAll(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	feeds, err := h.calendarService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener los feeds: "+err.Error())
+		return
+	}
+
+	// Convertir a respuesta
+	feedsResponse := make([]domain.CalendarFeedResponse, 0, len(feeds))
+	for _, feed := range feeds {
+		feedsResponse = append(feedsResponse, feed.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Feeds de calendario obtenidos exitosamente", feedsResponse)
+}
+
+// GetByID godoc
+// @Summary      Obtener feed de calendario
+// @Description  Obtiene un feed de calendario por su ID, sin su token
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del feed"
+// @Success      200 {object} utils.Response{data=domain.CalendarFeedResponse} "Feed obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Feed no encontrado"
+// @Router       /calendar/feeds/{id} [get]
+func (h *CalendarHandler) GetByID(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del feed
+	feedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de feed inválido")
+		return
+	}
+
+	feed, err := h.calendarService.GetByID(c.Request.Context(), uint(feedID), userID)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener el feed: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Feed de calendario obtenido exitosamente", feed.ToResponse())
+}
+
+// Update godoc
+// @Summary      Actualizar feed de calendario
+// @Description  Actualiza el nombre o el filtro de un feed de calendario. La URL no cambia.
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del feed"
+// @Param        request body domain.UpdateCalendarFeed true "Datos a actualizar"
+// @Success      200 {object} utils.Response{data=domain.CalendarFeedResponse} "Feed actualizado"
+// @Failure      400 {object} utils.Response "Datos o filtro inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Feed no encontrado"
+// @Router       /calendar/feeds/{id} [put]
+func (h *CalendarHandler) Update(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del feed
+	feedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de feed inválido")
+		return
+	}
+
+	var req domain.UpdateCalendarFeed
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	feed, err := h.calendarService.Update(c.Request.Context(), uint(feedID), userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Error al actualizar el feed: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Feed de calendario actualizado exitosamente", feed.ToResponse())
+}
+
+// Delete godoc
+// @Summary      Eliminar feed de calendario
+// @Description  Elimina un feed de calendario; su URL deja de funcionar
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del feed"
+// @Success      200 {object} utils.Response "Feed eliminado"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Feed no encontrado"
+// @Router       /calendar/feeds/{id} [delete]
+func (h *CalendarHandler) Delete(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del feed
+	feedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de feed inválido")
+		return
+	}
+
+	if err := h.calendarService.Delete(c.Request.Context(), uint(feedID), userID); err != nil {
+		h.handleError(c, err, "Error al eliminar el feed: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Feed de calendario eliminado exitosamente", nil)
+}
+
+// Rotate godoc
+// @Summary      Rotar token del feed
+// @Description  Genera un nuevo token para el feed. La URL anterior deja de funcionar de inmediato.
+// @Tags         Calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del feed"
+// @Success      200 {object} utils.Response{data=domain.CalendarFeedURLResponse} "Token rotado"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Feed no encontrado"
+// @Router       /calendar/feeds/{id}/rotate [post]
+func (h *CalendarHandler) Rotate(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del feed
+	feedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de feed inválido")
+		return
+	}
+
+	feed, token, err := h.calendarService.Rotate(c.Request.Context(), uint(feedID), userID)
+	if err != nil {
+		h.handleError(c, err, "Error al rotar el token del feed: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token del feed rotado exitosamente", feedURLResponse(feed, token))
+}
+
+// Feed godoc
+// @Summary      Feed iCalendar
+// @Description  Devuelve el calendario del feed con una VTODO por tarea. No requiere autenticación: el token de la URL es el secreto. Admite GET condicional con If-None-Match.
+// @Tags         Calendar
+// @Produce      text/calendar
+// @Param        token path string true "Token del feed seguido de .ics"
+// @Param        If-None-Match header string false "ETag de la última respuesta"
+// @Success      200 {file} file "Calendario iCalendar"
+// @Success      304 "El calendario no cambió"
+// @Failure      404 {object} utils.Response "Feed no encontrado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /calendar/{token} [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("token"), ".ics")
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "Feed de calendario no encontrado")
+		return
+	}
+
+	ctx := c.Request.Context()
+	feed, err := h.calendarService.GetByToken(ctx, token)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener el feed: ")
+		return
+	}
+
+	etag, err := h.calendarService.FeedETag(ctx, feed)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener el feed: ")
+		return
+	}
+	// El token viaja en la URL: las cachés compartidas no deben guardar la respuesta
+	c.Header("Cache-Control", "private, no-cache")
+	if notModified(c, etag) {
+		return
+	}
+
+	c.Header("Content-Type", ical.MediaType)
+	buffer := bufio.NewWriterSize(c.Writer, 32<<10)
+	err = h.calendarService.WriteFeed(ctx, feed, buffer)
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			// La respuesta ya empezó: solo queda interrumpirla
+			log.Println("Error al escribir el feed de calendario:", err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("ETag")
+		h.handleError(c, err, "Error al escribir el feed: ")
+	}
+}
+
+// handleError traduce los errores del servicio de calendario a respuestas HTTP
+func (h *CalendarHandler) handleError(c *gin.Context, err error, prefix string) {
+	var syntaxErr *filter.SyntaxError
+	switch {
+	case errors.Is(err, service.ErrCalendarFeedNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Feed de calendario no encontrado")
+	case errors.Is(err, service.ErrCalendarFeedUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para acceder a este feed de calendario")
+	case errors.As(err, &syntaxErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, prefix+err.Error())
+	}
+}
+
+// feedURLResponse arma la respuesta con el token y la URL de suscripción del feed
+func feedURLResponse(feed *domain.CalendarFeed, token string) domain.CalendarFeedURLResponse {
+	return domain.CalendarFeedURLResponse{
+		CalendarFeedResponse: feed.ToResponse(),
+		Token:                token,
+		URL:                  calendarFeedPath + token + ".ics",
+	}
+}
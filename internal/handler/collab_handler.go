@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/collab"
+	"github.com/alexroel/gin-tasks-api/internal/events"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type CollabHandler struct {
+	hub         *collab.Hub
+	taskService service.TaskService
+	events      *events.Hub
+	upgrader    websocket.Upgrader
+}
+
+// NewCollabHandler crea una nueva instancia de CollabHandler.
+// allowedOrigins limita los orígenes de navegador aceptados; si está vacío solo se acepta el mismo origen.
+func NewCollabHandler(hub *collab.Hub, taskService service.TaskService, eventsHub *events.Hub, allowedOrigins []string) *CollabHandler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	if len(allowedOrigins) > 0 {
+		allowed := make(map[string]struct{}, len(allowedOrigins))
+		for _, origin := range allowedOrigins {
+			allowed[origin] = struct{}{}
+		}
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			_, ok := allowed[origin]
+			return ok
+		}
+	}
+	return &CollabHandler{hub: hub, taskService: taskService, events: eventsHub, upgrader: upgrader}
+}
+
+// Connect godoc
+// @Summary      Canal de colaboración
+// @Description  Abre un WebSocket para suscribirse a canales task:<id>, recibir presencia e indicadores de escritura y modificar tareas. Desde el navegador el token se envía en el parámetro access_token.
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        access_token query string false "Token JWT, si no se envía el encabezado Authorization"
+// @Success      101 "Conexión WebSocket establecida"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Router       /ws [get]
+func (h *CollabHandler) Connect(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// El upgrader responde el error al cliente si la petición no es un WebSocket válido
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	client := collab.NewClient(h.hub, conn, h.taskService, h.events, userID, c.GetString("userEmail"))
+	client.Run(c.Request.Context())
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag genera el ETag fuerte de un recurso a partir de su versión
+func versionETag(version uint) string {
+	return fmt.Sprintf(`"v%d"`, version)
+}
+
+// tasksETag genera un ETag débil para una lista de tareas a partir de sus IDs y versiones
+func tasksETag(tasks []domain.Task) string {
+	hash := sha256.New()
+	for _, task := range tasks {
+		fmt.Fprintf(hash, "%d:%d;", task.ID, task.Version)
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+// notModified establece el ETag de la respuesta y, si coincide con If-None-Match,
+// responde 304 Not Modified. Devuelve true si ya se respondió.
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	// If-None-Match usa comparación débil: se ignora el prefijo W/
+	current := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == current {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion obtiene la versión esperada del encabezado If-Match.
+// Devuelve 0 si no se envió o si es "*"; un valor que no corresponde a ninguna versión
+// (ETag débil o con otro formato) nunca coincide y devuelve ErrPreconditionFailed.
+func ifMatchVersion(c *gin.Context) (uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	// If-Match usa comparación fuerte: solo se aceptan ETags de la forma "vN"
+	if !strings.HasPrefix(header, `"v`) || !strings.HasSuffix(header, `"`) {
+		return 0, service.ErrPreconditionFailed
+	}
+	version, err := strconv.ParseUint(header[2:len(header)-1], 10, 32)
+	if err != nil || version == 0 {
+		return 0, service.ErrPreconditionFailed
+	}
+	return uint(version), nil
+}
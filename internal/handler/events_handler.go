@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/events"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+type EventsHandler struct {
+	hub       *events.Hub
+	heartbeat time.Duration
+}
+
+// NewEventsHandler crea una nueva instancia de EventsHandler.
+// heartbeat es la frecuencia de los comentarios que mantienen viva la conexión a través de proxies.
+func NewEventsHandler(hub *events.Hub, heartbeat time.Duration) *EventsHandler {
+	return &EventsHandler{hub: hub, heartbeat: heartbeat}
+}
+
+// Stream godoc
+// @Summary      Eventos en tiempo real
+// @Description  Abre un stream Server-Sent Events con los eventos task.created, task.updated y task.deleted de las tareas del usuario autenticado. Con Last-Event-ID se reenvían los eventos perdidos; si ya no están disponibles se envía un evento resync y el cliente debe volver a cargar sus tareas.
+// @Tags         Events
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        Last-Event-ID header string false "ID del último evento recibido"
+// @Success      200 {object} domain.TaskEvent "Stream de eventos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Router       /events [get]
+func (h *EventsHandler) Stream(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	sub, replay, ok := h.hub.Subscribe(userID, lastEventID)
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !ok {
+		c.Render(-1, sse.Event{Event: events.TypeResync, Data: "{}"})
+	}
+	for _, event := range replay {
+		renderEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, open := <-sub.Events():
+			if !open {
+				// El cliente no consumía los eventos a tiempo: reconectará con Last-Event-ID
+				return false
+			}
+			renderEvent(c, event)
+		case <-ticker.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+		}
+		return true
+	})
+}
+
+// renderEvent escribe un evento en el stream SSE
+func renderEvent(c *gin.Context, event events.Event) {
+	c.Render(-1, sse.Event{
+		Id:    event.ID,
+		Event: event.Type,
+		Data:  string(event.Data),
+	})
+}
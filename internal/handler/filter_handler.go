@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type FilterHandler struct {
+	filterService service.FilterService
+}
+
+// NewFilterHandler crea una nueva instancia de FilterHandler
+func NewFilterHandler(filterService service.FilterService) *FilterHandler {
+	return &FilterHandler{filterService: filterService}
+}
+
+// Create godoc
+// @Summary      Guardar filtro
+// @Description  Guarda una expresión de filtro con nombre como lista inteligente. Los campos admitidos son status, title, created y updated; due, tag y priority no existen todavía
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateFilter true "Datos del filtro"
+// @Param        Idempotency-Key header string false "Clave para reintentar la petición sin duplicarla"
+// @Success      201 {object} utils.Response{data=domain.FilterResponse} "Filtro guardado"
+// @Failure      400 {object} utils.Response "Datos o expresión inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /filters [post]
+func (h *FilterHandler) Create(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.CreateFilter
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	savedFilter, err := h.filterService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		var syntaxErr *filter.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al guardar el filtro: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Filtro guardado exitosamente", savedFilter.ToResponse())
+}
+
+// GetAll godoc
+// @Summary      Listar filtros
+// @Description  Obtiene los filtros guardados del usuario autenticado
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]domain.FilterResponse} "Lista de filtros"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /filters [get]
+func (h *FilterHandler) GetAll(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	savedFilters, err := h.filterService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener los filtros: "+err.Error())
+		return
+	}
+
+	// Convertir a respuesta
+	filtersResponse := make([]domain.FilterResponse, 0, len(savedFilters))
+	for _, savedFilter := range savedFilters {
+		filtersResponse = append(filtersResponse, savedFilter.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Filtros obtenidos exitosamente", filtersResponse)
+}
+
+// GetByID godoc
+// @Summary      Obtener filtro
+// @Description  Obtiene un filtro guardado por su ID
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del filtro"
+// @Success      200 {object} utils.Response{data=domain.FilterResponse} "Filtro obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Filtro no encontrado"
+// @Router       /filters/{id} [get]
+func (h *FilterHandler) GetByID(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del filtro
+	filterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de filtro inválido")
+		return
+	}
+
+	savedFilter, err := h.filterService.GetByID(c.Request.Context(), uint(filterID), userID)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener el filtro: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Filtro obtenido exitosamente", savedFilter.ToResponse())
+}
+
+// Update godoc
+// @Summary      Actualizar filtro
+// @Description  Actualiza el nombre o la expresión de un filtro guardado
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del filtro"
+// @Param        request body domain.UpdateFilter true "Datos a actualizar"
+// @Success      200 {object} utils.Response{data=domain.FilterResponse} "Filtro actualizado"
+// @Failure      400 {object} utils.Response "Datos o expresión inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Filtro no encontrado"
+// @Router       /filters/{id} [put]
+func (h *FilterHandler) Update(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del filtro
+	filterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de filtro inválido")
+		return
+	}
+
+	var req domain.UpdateFilter
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	savedFilter, err := h.filterService.Update(c.Request.Context(), uint(filterID), userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Error al actualizar el filtro: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Filtro actualizado exitosamente", savedFilter.ToResponse())
+}
+
+// Delete godoc
+// @Summary      Eliminar filtro
+// @Description  Elimina un filtro guardado
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del filtro"
+// @Success      200 {object} utils.Response "Filtro eliminado"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Filtro no encontrado"
+// @Router       /filters/{id} [delete]
+func (h *FilterHandler) Delete(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del filtro
+	filterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de filtro inválido")
+		return
+	}
+
+	if err := h.filterService.Delete(c.Request.Context(), uint(filterID), userID); err != nil {
+		h.handleError(c, err, "Error al eliminar el filtro: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Filtro eliminado exitosamente", nil)
+}
+
+// Tasks godoc
+// @Summary      Tareas de un filtro
+// @Description  Obtiene las tareas que cumplen un filtro guardado
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del filtro"
+// @Success      200 {object} utils.Response{data=[]domain.TaskResponse} "Tareas del filtro"
+// @Failure      400 {object} utils.Response "ID o expresión inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Filtro no encontrado"
+// @Router       /filters/{id}/tasks [get]
+func (h *FilterHandler) Tasks(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID del filtro
+	filterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de filtro inválido")
+		return
+	}
+
+	tasks, err := h.filterService.GetTasks(c.Request.Context(), uint(filterID), userID)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener las tareas: ")
+		return
+	}
+
+	// Convertir a respuesta
+	tasksResponse := make([]domain.TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		tasksResponse = append(tasksResponse, task.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tareas obtenidas exitosamente", tasksResponse)
+}
+
+// handleError traduce los errores del servicio de filtros a respuestas HTTP
+func (h *FilterHandler) handleError(c *gin.Context, err error, prefix string) {
+	var syntaxErr *filter.SyntaxError
+	switch {
+	case errors.Is(err, service.ErrFilterNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Filtro no encontrado")
+	case errors.Is(err, service.ErrFilterUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para acceder a este filtro")
+	case errors.As(err, &syntaxErr):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, prefix+err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/graph"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	server *graph.Server
+}
+
+// NewGraphQLHandler crea una nueva instancia de GraphQLHandler
+func NewGraphQLHandler(server *graph.Server) *GraphQLHandler {
+	return &GraphQLHandler{server: server}
+}
+
+// Query godoc
+// @Summary      Consulta GraphQL
+// @Description  Ejecuta una consulta o mutación GraphQL sobre las tareas, los filtros guardados y el historial del usuario autenticado.
+// @Description  La respuesta sigue el formato de GraphQL ({data, errors}) en lugar del formato de la API REST; los errores de los resolvers incluyen extensions.code.
+// @Description  Las consultas que superan la profundidad o la complejidad máximas se rechazan sin ejecutarse.
+// @Tags         GraphQL
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body graph.Request true "Consulta GraphQL"
+// @Success      200 {object} object "Resultado de la consulta"
+// @Failure      400 {object} utils.Response "Petición inválida"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Router       /graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req graph.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, h.server.Exec(c.Request.Context(), userID, &req))
+}
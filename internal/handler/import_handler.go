@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// importFormOverhead es el margen para los encabezados y campos del formulario multipart
+const importFormOverhead = 64 << 10
+
+type ImportHandler struct {
+	importService service.ImportService
+	maxBytes      int64
+}
+
+// NewImportHandler crea una nueva instancia de ImportHandler.
+// maxBytes es el tamaño máximo del archivo que se acepta.
+func NewImportHandler(importService service.ImportService, maxBytes int64) *ImportHandler {
+	return &ImportHandler{importService: importService, maxBytes: maxBytes}
+}
+
+// MaxBodyBytes es el tamaño máximo del cuerpo de una importación: el archivo más un margen
+// para los demás campos del formulario. Los middlewares que leen el cuerpo antes que el handler
+// (como el de idempotencia) deben usar este límite.
+func (h *ImportHandler) MaxBodyBytes() int64 {
+	return h.maxBytes + importFormOverhead
+}
+
+// Import godoc
+// @Summary      Importar tareas
+// @Description  Importa tareas desde un archivo CSV, JSON (exportación de esta API), Todoist (plantilla CSV) o todo.txt. El formato se detecta automáticamente si no se indica. Con dry_run=true solo informa qué tareas se crearían y los errores por línea. Las líneas con errores se omiten y el resto se crea en una sola transacción. Los archivos grandes se importan en segundo plano y se responde 202 con el trabajo.
+// @Tags         Import
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file formData file true "Archivo a importar"
+// @Param        format formData string false "Formato del archivo" Enums(auto, csv, json, todoist, todotxt) default(auto)
+// @Param        mapping formData string false "Mapeo de columnas CSV, por ejemplo title=Nombre,completed=Hecho"
+// @Param        dry_run formData bool false "Solo validar, sin crear tareas" default(false)
+// @Success      200 {object} utils.Response{data=domain.ImportResult} "Simulación de la importación"
+// @Success      201 {object} utils.Response{data=domain.ImportResult} "Tareas importadas"
+// @Success      202 {object} utils.Response{data=domain.ImportJobResponse} "Importación en segundo plano"
+// @Failure      400 {object} utils.Response "Archivo u opciones inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      413 {object} utils.Response "Archivo demasiado grande"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /import [post]
+func (h *ImportHandler) Import(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Limitar el cuerpo completo; se deja un margen para los demás campos del formulario
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxBodyBytes())
+
+	var opts domain.ImportOptions
+	if err := c.ShouldBind(&opts); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("El archivo excede el tamaño máximo de %d bytes", h.maxBytes))
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Se requiere el archivo en el campo file")
+		return
+	}
+	if fileHeader.Size > h.maxBytes {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("El archivo excede el tamaño máximo de %d bytes", h.maxBytes))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "No se pudo leer el archivo")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxBytes+1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "No se pudo leer el archivo")
+		return
+	}
+	if int64(len(data)) > h.maxBytes {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("El archivo excede el tamaño máximo de %d bytes", h.maxBytes))
+		return
+	}
+
+	result, job, err := h.importService.Import(c.Request.Context(), userID, fileHeader.Filename, data, &opts)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImportFile) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al importar las tareas: "+err.Error())
+		return
+	}
+
+	switch {
+	case job != nil:
+		c.Header("Location", fmt.Sprintf("/api/import/jobs/%d", job.ID))
+		utils.SuccessResponse(c, http.StatusAccepted, "Importación iniciada en segundo plano", job.ToResponse())
+	case opts.DryRun:
+		utils.SuccessResponse(c, http.StatusOK, "Simulación de la importación completada", result)
+	default:
+		utils.SuccessResponse(c, http.StatusCreated, "Tareas importadas exitosamente", result)
+	}
+}
+
+// Job godoc
+// @Summary      Estado de una importación
+// @Description  Obtiene el estado y el progreso de una importación en segundo plano
+// @Tags         Import
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del trabajo de importación"
+// @Success      200 {object} utils.Response{data=domain.ImportJobResponse} "Estado obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Trabajo no encontrado"
+// @Router       /import/jobs/{id} [get]
+func (h *ImportHandler) Job(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de trabajo inválido")
+		return
+	}
+
+	job, err := h.importService.GetJob(c.Request.Context(), uint(jobID), userID)
+	if err != nil {
+		switch err {
+		case service.ErrImportJobNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		case service.ErrImportJobDenied:
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener la importación: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Estado de la importación obtenido exitosamente", job.ToResponse())
+}
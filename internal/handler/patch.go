@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/patch"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// handlePatchError responde los errores comunes a las peticiones PATCH.
+// Devuelve false si el error no corresponde a ninguno de ellos.
+func handlePatchError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, patch.ErrUnsupportedMediaType):
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, patch.ErrInvalidPatch):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, patch.ErrTestFailed):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, patch.ErrCannotApply), errors.Is(err, service.ErrInvalidDocument):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, service.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, service.ErrConcurrentModification):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	default:
+		return false
+	}
+	return true
+}
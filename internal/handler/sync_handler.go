@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SyncHandler struct {
+	taskService service.TaskService
+}
+
+// NewSyncHandler crea una nueva instancia de SyncHandler
+func NewSyncHandler(taskService service.TaskService) *SyncHandler {
+	return &SyncHandler{taskService: taskService}
+}
+
+// Pull godoc
+// @Summary      Obtener cambios para sincronizar
+// @Description  Devuelve las tareas creadas, modificadas o eliminadas (tombstones) desde el token indicado, junto con el token para la siguiente sincronización. Sin token, o si el token es más antiguo que la retención de la papelera, devuelve todas las tareas con reset=true. Si has_more es true se debe pedir la siguiente página con el nuevo token.
+// @Tags         Sync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        since query string false "Token devuelto por la sincronización anterior"
+// @Param        limit query int false "Cambios por página (máx. 1000)" default(500)
+// @Success      200 {object} utils.Response{data=domain.SyncChanges} "Cambios obtenidos"
+// @Failure      400 {object} utils.Response "Token inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /sync [get]
+func (h *SyncHandler) Pull(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(domain.DefaultSyncLimit)))
+	if err != nil || limit < 1 {
+		limit = domain.DefaultSyncLimit
+	}
+	if limit > domain.MaxSyncLimit {
+		limit = domain.MaxSyncLimit
+	}
+
+	changes, err := h.taskService.Changes(c.Request.Context(), userID, c.Query("since"), limit)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidSyncToken:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener los cambios: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Cambios obtenidos exitosamente", changes)
+}
+
+// Push godoc
+// @Summary      Enviar cambios sin conexión
+// @Description  Aplica en orden las mutaciones hechas por el cliente sin conexión (create con client_id generado por el cliente, update y delete) y reporta el resultado de cada una: applied, merged o rejected. Los conflictos se resuelven campo por campo: si base_version coincide se aplican todos los campos; si no, se aplican los campos que el servidor no modificó desde base y, en los que cambiaron en ambos lados, gana el updated_at más reciente (el servidor gana los empates).
+// @Tags         Sync
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.SyncRequest true "Mutaciones del cliente (máx. 500)"
+// @Success      200 {object} utils.Response{data=domain.SyncResult} "Mutaciones procesadas"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /sync [post]
+func (h *SyncHandler) Push(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	result, err := h.taskService.Sync(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case service.ErrSyncTooManyMutations:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al sincronizar: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sincronización procesada exitosamente", result)
+}
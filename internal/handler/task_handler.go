@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/exporter"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
 	"github.com/alexroel/gin-tasks-api/internal/middleware"
 	"github.com/alexroel/gin-tasks-api/internal/service"
 	"github.com/alexroel/gin-tasks-api/pkg/utils"
@@ -28,6 +35,7 @@ func NewTaskHandler(taskService service.TaskService) *TaskHandler {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        request body domain.CreateTask true "Datos de la tarea"
+// @Param        Idempotency-Key header string false "Clave para reintentar la petición sin duplicarla"
 // @Success      201 {object} utils.Response{data=domain.TaskResponse} "Tarea creada"
 // @Failure      400 {object} utils.Response "Datos inválidos"
 // @Failure      401 {object} utils.Response "No autenticado"
@@ -58,12 +66,16 @@ func (h *TaskHandler) Create(c *gin.Context) {
 
 // GetAll godoc
 // @Summary      Listar tareas
-// @Description  Obtiene todas las tareas del usuario autenticado
+// @Description  Obtiene todas las tareas del usuario autenticado, opcionalmente filtradas con una expresión
 // @Tags         Tasks
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        filter query string false "Expresión de filtro con los campos status, title, created y updated (no hay due, tag ni priority), p. ej. status:open created<7d -deploy"
+// @Param        If-None-Match header string false "ETag de la lista conocida por el cliente"
 // @Success      200 {object} utils.Response{data=[]domain.TaskResponse} "Lista de tareas"
+// @Success      304 "La lista no cambió"
+// @Failure      400 {object} utils.Response "Expresión de filtro inválida"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      500 {object} utils.Response "Error interno"
 // @Router       /tasks [get]
@@ -75,12 +87,27 @@ func (h *TaskHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	tasks, err := h.taskService.GetByUserID(c.Request.Context(), userID)
+	var tasks []domain.Task
+	var err error
+	if expression := c.Query("filter"); expression != "" {
+		tasks, err = h.taskService.Filter(c.Request.Context(), userID, expression)
+	} else {
+		tasks, err = h.taskService.GetByUserID(c.Request.Context(), userID)
+	}
 	if err != nil {
+		var syntaxErr *filter.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener las tareas: "+err.Error())
 		return
 	}
 
+	if notModified(c, tasksETag(tasks)) {
+		return
+	}
+
 	// Convertir a respuesta
 	var tasksResponse []domain.TaskResponse
 	for _, task := range tasks {
@@ -98,7 +125,9 @@ func (h *TaskHandler) GetAll(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path int true "ID de la tarea"
+// @Param        If-None-Match header string false "ETag de la tarea conocida por el cliente"
 // @Success      200 {object} utils.Response{data=domain.TaskResponse} "Tarea obtenida"
+// @Success      304 "La tarea no cambió"
 // @Failure      400 {object} utils.Response "ID inválido"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      403 {object} utils.Response "Sin permiso"
@@ -135,6 +164,10 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, versionETag(task.Version)) {
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Tarea obtenida exitosamente", task.ToResponse())
 }
 
@@ -147,11 +180,15 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 // @Security     BearerAuth
 // @Param        id path int true "ID de la tarea"
 // @Param        request body domain.UpdateTask true "Datos a actualizar"
+// @Param        If-Match header string false "ETag de la versión que se modifica"
 // @Success      200 {object} utils.Response{data=domain.TaskResponse} "Tarea actualizada"
 // @Failure      400 {object} utils.Response "Datos inválidos"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      403 {object} utils.Response "Sin permiso"
 // @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Failure      409 {object} utils.Response "Modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      428 {object} utils.Response "If-Match requerido"
 // @Router       /tasks/{id} [put]
 func (h *TaskHandler) Update(c *gin.Context) {
 	// Obtener ID del usuario autenticado
@@ -174,8 +211,86 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.Update(c.Request.Context(), uint(taskID), userID, &req)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Update(c.Request.Context(), uint(taskID), userID, &req, version)
+	if err != nil {
+		switch err {
+		case service.ErrTaskNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
+		case service.ErrTaskUnauthorized:
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para modificar esta tarea")
+		case service.ErrPreconditionFailed:
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		case service.ErrConcurrentModification:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al actualizar la tarea: "+err.Error())
+		}
+		return
+	}
+
+	c.Header("ETag", versionETag(task.Version))
+	utils.SuccessResponse(c, http.StatusOK, "Tarea actualizada exitosamente", task.ToResponse())
+}
+
+// Patch godoc
+// @Summary      Modificar tarea parcialmente
+// @Description  Aplica un JSON Merge Patch (RFC 7396) o un JSON Patch (RFC 6902) a una tarea. El documento resultante se valida con las mismas reglas que al crear una tarea.
+// @Tags         Tasks
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la tarea"
+// @Param        request body domain.TaskDocument true "Merge patch o lista de operaciones JSON Patch"
+// @Param        If-Match header string false "ETag de la versión que se modifica"
+// @Success      200 {object} utils.Response{data=domain.TaskResponse} "Tarea actualizada"
+// @Failure      400 {object} utils.Response "Patch inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Failure      409 {object} utils.Response "Operación test fallida o modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      415 {object} utils.Response "Tipo de contenido no soportado"
+// @Failure      422 {object} utils.Response "El documento resultante no es válido"
+// @Failure      428 {object} utils.Response "If-Match requerido"
+// @Router       /tasks/{id} [patch]
+func (h *TaskHandler) Patch(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la tarea
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de tarea inválido")
+		return
+	}
+
+	body, err := c.GetRawData()
 	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "No se pudo leer el cuerpo de la petición")
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Patch(c.Request.Context(), uint(taskID), userID, c.ContentType(), body, version)
+	if err != nil {
+		if handlePatchError(c, err) {
+			return
+		}
 		switch err {
 		case service.ErrTaskNotFound:
 			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
@@ -187,6 +302,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(task.Version))
 	utils.SuccessResponse(c, http.StatusOK, "Tarea actualizada exitosamente", task.ToResponse())
 }
 
@@ -198,11 +314,15 @@ func (h *TaskHandler) Update(c *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path int true "ID de la tarea"
+// @Param        If-Match header string false "ETag de la versión que se elimina"
 // @Success      200 {object} utils.Response "Tarea eliminada"
 // @Failure      400 {object} utils.Response "ID inválido"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      403 {object} utils.Response "Sin permiso"
 // @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Failure      409 {object} utils.Response "Modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      428 {object} utils.Response "If-Match requerido"
 // @Router       /tasks/{id} [delete]
 func (h *TaskHandler) Delete(c *gin.Context) {
 	// Obtener ID del usuario autenticado
@@ -219,13 +339,23 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.taskService.Delete(c.Request.Context(), uint(taskID), userID)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	err = h.taskService.Delete(c.Request.Context(), uint(taskID), userID, version)
 	if err != nil {
 		switch err {
 		case service.ErrTaskNotFound:
 			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
 		case service.ErrTaskUnauthorized:
 			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para eliminar esta tarea")
+		case service.ErrPreconditionFailed:
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		case service.ErrConcurrentModification:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 		default:
 			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al eliminar la tarea: "+err.Error())
 		}
@@ -244,11 +374,15 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 // @Security     BearerAuth
 // @Param        id path int true "ID de la tarea"
 // @Param        request body object{completed=bool} true "Nuevo estado"
+// @Param        If-Match header string false "ETag de la versión que se modifica"
 // @Success      200 {object} utils.Response{data=domain.TaskResponse} "Estado actualizado"
 // @Failure      400 {object} utils.Response "Datos inválidos"
 // @Failure      401 {object} utils.Response "No autenticado"
 // @Failure      403 {object} utils.Response "Sin permiso"
 // @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Failure      409 {object} utils.Response "Modificación concurrente"
+// @Failure      412 {object} utils.Response "La versión no coincide con If-Match"
+// @Failure      428 {object} utils.Response "If-Match requerido"
 // @Router       /tasks/{id}/status [patch]
 func (h *TaskHandler) ToggleStatus(c *gin.Context) {
 	// Obtener ID del usuario autenticado
@@ -274,18 +408,351 @@ func (h *TaskHandler) ToggleStatus(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.UpdateStatus(c.Request.Context(), uint(taskID), userID, req.Completed)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	task, err := h.taskService.UpdateStatus(c.Request.Context(), uint(taskID), userID, req.Completed, version)
 	if err != nil {
 		switch err {
 		case service.ErrTaskNotFound:
 			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
 		case service.ErrTaskUnauthorized:
 			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para modificar esta tarea")
+		case service.ErrPreconditionFailed:
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		case service.ErrConcurrentModification:
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 		default:
 			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al actualizar el estado: "+err.Error())
 		}
 		return
 	}
 
+	c.Header("ETag", versionETag(task.Version))
 	utils.SuccessResponse(c, http.StatusOK, "Estado de tarea actualizado exitosamente", task.ToResponse())
 }
+
+// Trash godoc
+// @Summary      Listar papelera
+// @Description  Obtiene las tareas eliminadas del usuario autenticado que aún no se han purgado
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]domain.TrashTaskResponse} "Tareas en la papelera"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /tasks/trash [get]
+func (h *TaskHandler) Trash(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	tasks, err := h.taskService.GetTrash(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener la papelera: "+err.Error())
+		return
+	}
+
+	// Convertir a respuesta
+	tasksResponse := make([]domain.TrashTaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		tasksResponse = append(tasksResponse, task.ToTrashResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Papelera obtenida exitosamente", tasksResponse)
+}
+
+// Restore godoc
+// @Summary      Restaurar tarea
+// @Description  Recupera una tarea de la papelera
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la tarea"
+// @Param        Idempotency-Key header string false "Clave para reintentar la petición sin duplicarla"
+// @Success      200 {object} utils.Response{data=domain.TaskResponse} "Tarea restaurada"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Tarea no encontrada en la papelera"
+// @Router       /tasks/{id}/restore [post]
+func (h *TaskHandler) Restore(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la tarea
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de tarea inválido")
+		return
+	}
+
+	task, err := h.taskService.Restore(c.Request.Context(), uint(taskID), userID)
+	if err != nil {
+		switch err {
+		case service.ErrTaskNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada en la papelera")
+		case service.ErrTaskUnauthorized:
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para restaurar esta tarea")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al restaurar la tarea: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tarea restaurada exitosamente", task.ToResponse())
+}
+
+// Purge godoc
+// @Summary      Eliminar tarea definitivamente
+// @Description  Elimina de forma permanente una tarea que está en la papelera
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la tarea"
+// @Success      200 {object} utils.Response "Tarea eliminada definitivamente"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Tarea no encontrada en la papelera"
+// @Router       /tasks/trash/{id} [delete]
+func (h *TaskHandler) Purge(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la tarea
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de tarea inválido")
+		return
+	}
+
+	err = h.taskService.Purge(c.Request.Context(), uint(taskID), userID)
+	if err != nil {
+		switch err {
+		case service.ErrTaskNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada en la papelera")
+		case service.ErrTaskUnauthorized:
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para eliminar esta tarea")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al eliminar la tarea: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tarea eliminada definitivamente", nil)
+}
+
+// Export godoc
+// @Summary      Exportar tareas
+// @Description  Descarga las tareas del usuario en CSV, JSON, Markdown o iCalendar (VTODO). Acepta el mismo filtro que el listado. La respuesta se envía a medida que se leen las tareas.
+// @Tags         Tasks
+// @Produce      text/csv,application/json,text/markdown,text/calendar
+// @Security     BearerAuth
+// @Param        format query string false "Formato de exportación" Enums(csv, json, md, ics) default(json)
+// @Param        filter query string false "Expresión de filtro con los campos status, title, created y updated (no hay due, tag ni priority), p. ej. status:open created<7d -deploy"
+// @Success      200 {file} file "Archivo exportado"
+// @Failure      400 {object} utils.Response "Formato o expresión de filtro inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /tasks/export [get]
+func (h *TaskHandler) Export(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	format := c.DefaultQuery("format", exporter.FormatJSON)
+	buffer := bufio.NewWriterSize(c.Writer, 32<<10)
+	writer, err := exporter.New(format, buffer)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", exporter.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tareas-%s.%s"`, time.Now().Format("20060102"), format))
+
+	err = h.taskService.Export(c.Request.Context(), userID, c.Query("filter"), writer)
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			// La respuesta ya empezó: solo queda interrumpirla
+			log.Println("Error al exportar las tareas:", err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		var syntaxErr *filter.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al exportar las tareas: "+err.Error())
+	}
+}
+
+// Search godoc
+// @Summary      Buscar tareas
+// @Description  Busca tareas del usuario autenticado por texto, con coincidencia por prefijo, ordenadas por relevancia
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q query string true "Texto a buscar"
+// @Param        lang query string false "Idioma de la búsqueda (es, en). Por defecto ambos"
+// @Param        limit query int false "Máximo de resultados (máx. 100)" default(20)
+// @Success      200 {object} utils.Response{data=[]domain.TaskSearchResponse} "Resultados de la búsqueda"
+// @Failure      400 {object} utils.Response "Búsqueda inválida"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /tasks/search [get]
+func (h *TaskHandler) Search(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	_, limit := getPagination(c)
+	results, err := h.taskService.Search(c.Request.Context(), userID, c.Query("q"), c.Query("lang"), limit)
+	if err != nil {
+		switch err {
+		case service.ErrEmptySearchQuery, service.ErrUnsupportedLang:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al buscar tareas: "+err.Error())
+		}
+		return
+	}
+
+	// Convertir a respuesta
+	resultsResponse := make([]domain.TaskSearchResponse, 0, len(results))
+	for _, result := range results {
+		resultsResponse = append(resultsResponse, result.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Búsqueda realizada exitosamente", resultsResponse)
+}
+
+// Bulk godoc
+// @Summary      Operación masiva de tareas
+// @Description  Completa, reabre o elimina varias tareas por ID, o crea varias tareas, en una sola transacción. En modo atomic (por defecto) se aplican todos los elementos o ninguno; en modo partial se aplican los válidos y se reporta el resultado por elemento.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.BulkTaskRequest true "Operación a realizar"
+// @Param        Idempotency-Key header string false "Clave para reintentar la petición sin duplicarla"
+// @Success      200 {object} utils.Response{data=domain.BulkTaskResponse} "Operación realizada"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      422 {object} utils.Response{data=domain.BulkTaskResponse} "Operación cancelada (modo atomic)"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /tasks/bulk [post]
+func (h *TaskHandler) Bulk(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	result, err := h.taskService.Bulk(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case service.ErrBulkAborted:
+			utils.ErrorResponseWithData(c, http.StatusUnprocessableEntity, err.Error(), result)
+		case service.ErrBulkEmpty, service.ErrBulkTooManyItems, service.ErrBulkUnsupportedAction:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error en la operación masiva: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Operación masiva realizada", result)
+}
+
+// Move godoc
+// @Summary      Reordenar tarea
+// @Description  Mueve una tarea dentro de la lista del usuario. after_id es la tarea que quedará inmediatamente antes y before_id la que quedará inmediatamente después; basta con indicar una de ellas.
+// @Tags         Tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la tarea"
+// @Param        request body domain.MoveTask true "Tareas de referencia"
+// @Param        Idempotency-Key header string false "Clave para reintentar la petición sin duplicarla"
+// @Success      200 {object} utils.Response{data=domain.TaskResponse} "Tarea reordenada"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Tarea no encontrada"
+// @Router       /tasks/{id}/move [post]
+func (h *TaskHandler) Move(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	// Obtener ID de la tarea
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de tarea inválido")
+		return
+	}
+
+	var req domain.MoveTask
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	task, err := h.taskService.Move(c.Request.Context(), uint(taskID), userID, &req)
+	if err != nil {
+		switch err {
+		case service.ErrTaskNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Tarea no encontrada")
+		case service.ErrTaskUnauthorized:
+			utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para modificar esta tarea")
+		case service.ErrInvalidMove, service.ErrMoveAnchorNotFound:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Error al reordenar la tarea: "+err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tarea reordenada exitosamente", task.ToResponse())
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/middleware"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler crea una nueva instancia de WebhookHandler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// Create godoc
+// @Summary      Registrar webhook
+// @Description  Suscribe una URL a eventos de las tareas del usuario. El secreto para verificar las firmas solo se devuelve en esta respuesta.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateWebhook true "URL y eventos (task.created, task.updated, task.completed, task.reopened, task.moved, task.deleted, task.restored, task.purged o *)"
+// @Success      201 {object} utils.Response{data=domain.WebhookSecretResponse} "Webhook registrado"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var req domain.CreateWebhook
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	hook, err := h.webhookService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Error al registrar el webhook: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Webhook registrado exitosamente", domain.WebhookSecretResponse{
+		WebhookResponse: hook.ToResponse(),
+		Secret:          hook.Secret,
+	})
+}
+
+// GetAll godoc
+// @Summary      Listar webhooks
+// @Description  Obtiene los webhooks del usuario autenticado
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]domain.WebhookResponse} "Lista de webhooks"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      500 {object} utils.Response "Error interno"
+// @Router       /webhooks [get]
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	// Obtener ID del usuario autenticado
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	hooks, err := h.webhookService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Error al obtener los webhooks: "+err.Error())
+		return
+	}
+
+	hooksResponse := make([]domain.WebhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		hooksResponse = append(hooksResponse, hook.ToResponse())
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhooks obtenidos exitosamente", hooksResponse)
+}
+
+// GetByID godoc
+// @Summary      Obtener webhook
+// @Description  Obtiene un webhook del usuario autenticado
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del webhook"
+// @Success      200 {object} utils.Response{data=domain.WebhookResponse} "Webhook obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Webhook no encontrado"
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	userID, webhookID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.GetByID(c.Request.Context(), webhookID, userID)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener el webhook: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook obtenido exitosamente", hook.ToResponse())
+}
+
+// Update godoc
+// @Summary      Actualizar webhook
+// @Description  Actualiza la URL, los eventos o el estado de un webhook. Reactivarlo reinicia su contador de fallos.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del webhook"
+// @Param        request body domain.UpdateWebhook true "Datos a actualizar"
+// @Success      200 {object} utils.Response{data=domain.WebhookResponse} "Webhook actualizado"
+// @Failure      400 {object} utils.Response "Datos inválidos"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Webhook no encontrado"
+// @Router       /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	userID, webhookID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateWebhook
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Datos inválidos: "+err.Error())
+		return
+	}
+
+	hook, err := h.webhookService.Update(c.Request.Context(), webhookID, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Error al actualizar el webhook: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook actualizado exitosamente", hook.ToResponse())
+}
+
+// Delete godoc
+// @Summary      Eliminar webhook
+// @Description  Elimina un webhook del usuario autenticado
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del webhook"
+// @Success      200 {object} utils.Response "Webhook eliminado"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Webhook no encontrado"
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID, webhookID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), webhookID, userID); err != nil {
+		h.handleError(c, err, "Error al eliminar el webhook: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook eliminado exitosamente", nil)
+}
+
+// Deliveries godoc
+// @Summary      Historial de entregas
+// @Description  Obtiene las entregas de un webhook con su estado, intentos y código de respuesta
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del webhook"
+// @Param        page query int false "Número de página" default(1)
+// @Param        limit query int false "Registros por página (máx. 100)" default(20)
+// @Success      200 {object} utils.Response{data=domain.DeliveryPage} "Historial obtenido"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Webhook no encontrado"
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	userID, webhookID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	page, limit := getPagination(c)
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), webhookID, userID, page, limit)
+	if err != nil {
+		h.handleError(c, err, "Error al obtener las entregas: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Entregas obtenidas exitosamente", deliveries)
+}
+
+// Redeliver godoc
+// @Summary      Reenviar entrega
+// @Description  Encola una nueva entrega con el mismo contenido que una entrega anterior
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID del webhook"
+// @Param        deliveryId path int true "ID de la entrega"
+// @Success      202 {object} utils.Response{data=domain.DeliveryResponse} "Entrega encolada"
+// @Failure      400 {object} utils.Response "ID inválido"
+// @Failure      401 {object} utils.Response "No autenticado"
+// @Failure      403 {object} utils.Response "Sin permiso"
+// @Failure      404 {object} utils.Response "Webhook o entrega no encontrados"
+// @Failure      409 {object} utils.Response "Webhook desactivado"
+// @Router       /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, webhookID, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de entrega inválido")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), webhookID, uint(deliveryID), userID)
+	if err != nil {
+		h.handleError(c, err, "Error al reenviar la entrega: ")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Entrega encolada exitosamente", delivery.ToResponse())
+}
+
+// params obtiene el usuario autenticado y el ID del webhook; responde el error si falta alguno
+func (h *WebhookHandler) params(c *gin.Context) (uint, uint, bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+		return 0, 0, false
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID de webhook inválido")
+		return 0, 0, false
+	}
+	return userID, uint(webhookID), true
+}
+
+// handleError traduce los errores del servicio de webhooks a respuestas HTTP
+func (h *WebhookHandler) handleError(c *gin.Context, err error, prefix string) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Webhook no encontrado")
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Entrega no encontrada")
+	case errors.Is(err, service.ErrWebhookUnauthorized):
+		utils.ErrorResponse(c, http.StatusForbidden, "No tienes permiso para acceder a este webhook")
+	case errors.Is(err, service.ErrWebhookInactive):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrWebhookAddress), errors.Is(err, service.ErrInvalidWebhookEvent):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, prefix+err.Error())
+	}
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Columnas que se usan cuando el mapeo no indica otra
+var defaultColumns = map[string][]string{
+	"title":     {"title", "titulo", "título", "name", "nombre", "task", "tarea", "content"},
+	"completed": {"completed", "completada", "done", "hecho", "status", "estado"},
+}
+
+// parseCSV interpreta un CSV con encabezado según el mapeo de columnas
+func parseCSV(data []byte, mapping map[string]string, result *Result) error {
+	reader, header, err := readCSVHeader(data)
+	if err != nil {
+		return err
+	}
+
+	titleCol, err := findColumn(header, "title", mapping)
+	if err != nil {
+		return err
+	}
+	if titleCol < 0 {
+		return errors.New("no se encontró la columna del título: indícala en el mapeo con title=<columna>")
+	}
+	completedCol, err := findColumn(header, "completed", mapping)
+	if err != nil {
+		return err
+	}
+
+	return eachCSVRecord(reader, result, func(line int, record []string) {
+		item := Item{Line: line, Title: field(record, titleCol)}
+		if completedCol >= 0 {
+			completed, ok := parseBool(field(record, completedCol))
+			if !ok {
+				result.addError(line, fmt.Sprintf("valor de completado no reconocido: %q", field(record, completedCol)))
+				return
+			}
+			item.Completed = completed
+		}
+		result.addItem(item)
+	})
+}
+
+// parseTodoist interpreta la plantilla CSV de Todoist. Solo se importan las filas de tipo task;
+// las secciones y los comentarios se omiten.
+func parseTodoist(data []byte, result *Result) error {
+	reader, header, err := readCSVHeader(data)
+	if err != nil {
+		return err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	typeCol, okType := columns["TYPE"]
+	contentCol, okContent := columns["CONTENT"]
+	if !okType || !okContent {
+		return errors.New("el archivo de Todoist debe tener las columnas TYPE y CONTENT")
+	}
+	priorityCol, okPriority := columns["PRIORITY"]
+	dateCol, okDate := columns["DATE"]
+
+	return eachCSVRecord(reader, result, func(line int, record []string) {
+		if !strings.EqualFold(field(record, typeCol), "task") {
+			return
+		}
+		item := Item{Line: line, Title: field(record, contentCol)}
+		if okPriority {
+			// Todoist exporta la prioridad 4 como la más baja (sin prioridad)
+			if priority := field(record, priorityCol); priority != "" && priority != "4" {
+				item.Priority = priority
+			}
+		}
+		if okDate {
+			item.Due = field(record, dateCol)
+		}
+		result.addItem(item)
+	})
+}
+
+// readCSVHeader crea el lector del CSV y lee la fila de encabezado
+func readCSVHeader(data []byte) (*csv.Reader, []string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("encabezado CSV inválido: %w", err)
+	}
+	return reader, header, nil
+}
+
+// eachCSVRecord recorre las filas del CSV con su número de línea. Las filas vacías se omiten
+// y las filas mal formadas se reportan como errores.
+func eachCSVRecord(reader *csv.Reader, result *Result, fn func(line int, record []string)) error {
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.addError(parseErr.StartLine, parseErr.Err.Error())
+				continue
+			}
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		fn(line, record)
+	}
+}
+
+// findColumn busca la columna de un campo: la indicada en el mapeo o un nombre conocido.
+// Devuelve -1 si el campo no tiene columna.
+func findColumn(header []string, name string, mapping map[string]string) (int, error) {
+	if column, ok := mapping[name]; ok {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), column) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("la columna %q del mapeo no existe en el archivo", column)
+	}
+
+	for _, candidate := range defaultColumns[name] {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), candidate) {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// field obtiene el valor de una columna, o vacío si la fila es más corta
+func field(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
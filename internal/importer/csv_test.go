@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		mapping    map[string]string
+		wantItems  []Item
+		wantErrors []LineError
+	}{
+		{"columnas por defecto", "title,completed\nComprar pan,false\nLlamar,true\n", nil,
+			[]Item{{Line: 2, Title: "Comprar pan"}, {Line: 3, Title: "Llamar", Completed: true}}, nil},
+		{"nombres en español sin distinguir mayúsculas", "Título,Hecho\nInforme,sí\n", nil,
+			[]Item{{Line: 2, Title: "Informe", Completed: true}}, nil},
+		{"mapeo de columnas", "Nombre,Estado,Notas\nInforme,done,x\nRevisar,pendiente,y\n",
+			map[string]string{"title": "Nombre", "completed": "Estado"},
+			[]Item{{Line: 2, Title: "Informe", Completed: true}, {Line: 3, Title: "Revisar"}}, nil},
+		{"el mapeo prevalece sobre los nombres conocidos", "title,Resumen\nlargo,corto\n",
+			map[string]string{"title": "resumen"},
+			[]Item{{Line: 2, Title: "corto"}}, nil},
+		{"sin columna de completado", "name\nInforme\n", nil,
+			[]Item{{Line: 2, Title: "Informe"}}, nil},
+		{"espacios alrededor de los valores", "title, completed\n  Informe  , yes \n", nil,
+			[]Item{{Line: 2, Title: "Informe", Completed: true}}, nil},
+		{"campos entre comillas con comas y saltos de línea", "title,completed\n\"Comprar pan, leche\",0\n\"Dos\nlíneas\",1\nÚltima,0\n", nil,
+			[]Item{{Line: 2, Title: "Comprar pan, leche"}, {Line: 3, Title: "Dos\nlíneas", Completed: true}, {Line: 5, Title: "Última"}}, nil},
+		{"filas vacías omitidas", "title\nUna\n\nDos\n", nil,
+			[]Item{{Line: 2, Title: "Una"}, {Line: 4, Title: "Dos"}}, nil},
+		{"fila más corta que el encabezado", "title,completed\nSolo título\n", nil,
+			[]Item{{Line: 2, Title: "Solo título"}}, nil},
+		{"completado no reconocido", "title,completed\nInforme,quizás\nOtra,no\n", nil,
+			[]Item{{Line: 3, Title: "Otra"}},
+			[]LineError{{Line: 2, Message: `valor de completado no reconocido: "quizás"`}}},
+		{"título vacío", "title,completed\n,true\nOtra,false\n", nil,
+			[]Item{{Line: 3, Title: "Otra"}},
+			[]LineError{{Line: 2, Message: "el título es requerido"}}},
+		{"comillas mal cerradas", "title\n\"Abierta\nOtra\n", nil,
+			nil,
+			[]LineError{{Line: 2, Message: `extraneous or missing " in quoted-field`}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatCSV, "tareas.csv", []byte(tt.data), Options{Mapping: tt.mapping})
+			require.NoError(t, err)
+			assert.Equal(t, FormatCSV, result.Format)
+			assert.Equal(t, tt.wantItems, result.Items)
+			assert.Equal(t, tt.wantErrors, result.Errors)
+		})
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping map[string]string
+		want    string
+	}{
+		{"sin columna de título", "descripcion,completed\nInforme,true\n", nil, "no se encontró la columna del título"},
+		{"columna del mapeo inexistente", "title,completed\nInforme,true\n", map[string]string{"completed": "Hecho"}, `la columna "Hecho" del mapeo no existe`},
+		{"encabezado mal formado", "\"title\n", nil, "encabezado CSV inválido"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatCSV, "tareas.csv", []byte(tt.data), Options{Mapping: tt.mapping})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestParseTodoist(t *testing.T) {
+	header := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n"
+	tests := []struct {
+		name        string
+		data        string
+		wantItems   []Item
+		wantErrors  []LineError
+		wantIgnored []string
+	}{
+		{"tareas con prioridad y fecha", header +
+			"task,Informe,,1,1,Ana,,every monday,es,Europe/Madrid\n" +
+			"task,Revisar,,4,1,Ana,,,es,Europe/Madrid\n",
+			[]Item{{Line: 2, Title: "Informe", Priority: "1", Due: "every monday"}, {Line: 3, Title: "Revisar"}},
+			nil, []string{AttrPriority, AttrDue}},
+		{"secciones, comentarios y filas vacías omitidos", header +
+			"section,Trabajo,,,,,,,,\n" +
+			"task,Informe,,4,1,Ana,,,,\n" +
+			"note,Un comentario,,,,,,,,\n" +
+			",,,,,,,,,\n" +
+			"\n" +
+			"TASK,Llamar,,4,1,Ana,,,,\n",
+			[]Item{{Line: 3, Title: "Informe"}, {Line: 7, Title: "Llamar"}}, nil, nil},
+		{"solo las columnas obligatorias", "type,content\ntask,Informe\n",
+			[]Item{{Line: 2, Title: "Informe"}}, nil, nil},
+		{"tarea sin contenido", header + "task,,,4,1,Ana,,,,\n",
+			nil, []LineError{{Line: 2, Message: "el título es requerido"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatTodoist, "todoist.csv", []byte(tt.data), Options{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantItems, result.Items)
+			assert.Equal(t, tt.wantErrors, result.Errors)
+			assert.Equal(t, tt.wantIgnored, result.Ignored)
+		})
+	}
+}
+
+func TestParseTodoistErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"sin columna CONTENT", "TYPE,DESCRIPTION\ntask,Informe\n", "debe tener las columnas TYPE y CONTENT"},
+		{"solo secciones", "TYPE,CONTENT\nsection,Trabajo\n", ErrEmptyFile.Error()},
+		{"archivo vacío", "", ErrEmptyFile.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatTodoist, "todoist.csv", []byte(tt.data), Options{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Nil(t, result)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Package importer interpreta archivos de tareas exportados desde otras herramientas.
+//
+// Formatos soportados:
+//
+//	csv      CSV con encabezado; las columnas se eligen con un mapeo (title=Nombre,completed=Hecho)
+//	json     exportación JSON de esta API: un arreglo de tareas o un objeto con "data" o "tasks"
+//	todoist  plantilla CSV exportada por Todoist (columnas TYPE, CONTENT, PRIORITY, DATE...)
+//	todotxt  formato todo.txt: x (A) 2024-01-02 Título +proyecto @contexto due:2024-01-05
+//
+// El resultado conserva el número de línea de cada tarea para poder reportar errores.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formatos de archivo soportados
+const (
+	FormatAuto    = "auto"
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatTodoist = "todoist"
+	FormatTodoTxt = "todotxt"
+)
+
+// Atributos que se leen de los archivos pero que las tareas todavía no soportan
+const (
+	AttrPriority = "priority"
+	AttrProject  = "project"
+	AttrContext  = "context"
+	AttrDue      = "due"
+)
+
+// maxTitleLength replica la validación del título de CreateTask
+const maxTitleLength = 200
+
+var (
+	ErrUnknownFormat = errors.New("formato de importación no soportado: debe ser csv, json, todoist o todotxt")
+	ErrEmptyFile     = errors.New("el archivo no contiene tareas")
+	ErrInvalidMap    = errors.New("el mapeo de columnas no es válido: se espera campo=columna separados por comas")
+)
+
+// Item es una tarea leída del archivo
+type Item struct {
+	Line      int
+	Title     string
+	Completed bool
+	Priority  string
+	Projects  []string
+	Contexts  []string
+	Due       string
+}
+
+// LineError describe un problema en una línea del archivo
+type LineError struct {
+	Line    int
+	Message string
+}
+
+// Result contiene las tareas válidas y los errores encontrados en el archivo
+type Result struct {
+	Format  string
+	Items   []Item
+	Errors  []LineError
+	Ignored []string
+}
+
+// Options configura la interpretación del archivo
+type Options struct {
+	// Mapping relaciona los campos de la tarea (title, completed) con las columnas del CSV
+	Mapping map[string]string
+}
+
+// Detect determina el formato a partir del nombre y el contenido del archivo
+func Detect(filename string, data []byte) string {
+	head := bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	firstLine := strings.ToUpper(string(head))
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	switch {
+	case strings.HasPrefix(firstLine, "TYPE,CONTENT"):
+		return FormatTodoist
+	case len(head) > 0 && (head[0] == '[' || head[0] == '{'):
+		return FormatJSON
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	case ".txt", ".todo":
+		return FormatTodoTxt
+	}
+
+	if strings.Contains(firstLine, ",") {
+		return FormatCSV
+	}
+	return FormatTodoTxt
+}
+
+// Parse interpreta el archivo en el formato indicado; FormatAuto o "" lo detecta
+func Parse(format, filename string, data []byte, opts Options) (*Result, error) {
+	if format == "" || format == FormatAuto {
+		format = Detect(filename, data)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	result := &Result{Format: format}
+	var err error
+	switch format {
+	case FormatCSV:
+		err = parseCSV(data, opts.Mapping, result)
+	case FormatTodoist:
+		err = parseTodoist(data, result)
+	case FormatJSON:
+		err = parseJSON(data, result)
+	case FormatTodoTxt:
+		parseTodoTxt(data, result)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 && len(result.Errors) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	result.Ignored = ignoredAttributes(result.Items)
+	return result, nil
+}
+
+// ParseMapping interpreta un mapeo de columnas con el formato title=Nombre,completed=Hecho
+func ParseMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == "" || (field != "title" && field != "completed") {
+			return nil, ErrInvalidMap
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// addItem valida el título y agrega la tarea o el error correspondiente
+func (r *Result) addItem(item Item) {
+	item.Title = strings.TrimSpace(item.Title)
+	length := utf8.RuneCountInString(item.Title)
+	switch {
+	case length == 0:
+		r.addError(item.Line, "el título es requerido")
+	case length > maxTitleLength:
+		r.addError(item.Line, fmt.Sprintf("el título excede los %d caracteres", maxTitleLength))
+	default:
+		r.Items = append(r.Items, item)
+	}
+}
+
+// addError agrega un error asociado a una línea
+func (r *Result) addError(line int, message string) {
+	r.Errors = append(r.Errors, LineError{Line: line, Message: message})
+}
+
+// ignoredAttributes lista los atributos presentes en el archivo que no se importan
+func ignoredAttributes(items []Item) []string {
+	found := make(map[string]bool)
+	for _, item := range items {
+		found[AttrPriority] = found[AttrPriority] || item.Priority != ""
+		found[AttrProject] = found[AttrProject] || len(item.Projects) > 0
+		found[AttrContext] = found[AttrContext] || len(item.Contexts) > 0
+		found[AttrDue] = found[AttrDue] || item.Due != ""
+	}
+
+	var ignored []string
+	for _, attr := range []string{AttrPriority, AttrProject, AttrContext, AttrDue} {
+		if found[attr] {
+			ignored = append(ignored, attr)
+		}
+	}
+	return ignored
+}
+
+// parseBool interpreta los valores habituales de una columna de completado
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "no", "n", "open", "pending", "pendiente", "abierta":
+		return false, true
+	case "1", "true", "yes", "y", "si", "sí", "x", "done", "completed", "completada", "hecho":
+		return true, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		want     string
+	}{
+		{"plantilla de Todoist", "export.csv", "TYPE,CONTENT,PRIORITY\ntask,Informe,1\n", FormatTodoist},
+		{"Todoist con BOM", "export", "\xEF\xBB\xBFTYPE,CONTENT\n", FormatTodoist},
+		{"JSON por el contenido", "tareas.txt", "  [{\"title\":\"Informe\"}]", FormatJSON},
+		{"JSON por la extensión", "tareas.json", "", FormatJSON},
+		{"CSV por la extensión", "tareas.CSV", "Informe\n", FormatCSV},
+		{"todo.txt por la extensión", "todo.txt", "title,completed\n", FormatTodoTxt},
+		{"extensión .todo", "lista.todo", "(A) Informe\n", FormatTodoTxt},
+		{"CSV por las comas", "tareas", "title,completed\nInforme,true\n", FormatCSV},
+		{"texto sin comas", "tareas", "Informe\n", FormatTodoTxt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.filename, []byte(tt.data)))
+		})
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"vacío", "  ", map[string]string{}, false},
+		{"ambos campos", "title=Nombre,completed=Hecho", map[string]string{"title": "Nombre", "completed": "Hecho"}, false},
+		{"espacios y mayúsculas en el campo", " Title = Nombre de la tarea ", map[string]string{"title": "Nombre de la tarea"}, false},
+		{"campo desconocido", "due=Fecha", nil, true},
+		{"sin signo igual", "title", nil, true},
+		{"columna vacía", "title=", nil, true},
+		{"par vacío", "title=Nombre,", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := ParseMapping(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMap)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mapping)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		filename    string
+		data        string
+		wantFormat  string
+		wantItems   int
+		wantIgnored []string
+		wantErr     error
+	}{
+		{"detección automática", FormatAuto, "todo.txt", "(A) Informe @oficina\n", FormatTodoTxt, 1, []string{AttrPriority, AttrContext}, nil},
+		{"formato vacío se detecta", "", "tareas.csv", "\xEF\xBB\xBFtitle\nInforme\n", FormatCSV, 1, nil, nil},
+		{"atributos ignorados en orden fijo", FormatTodoTxt, "todo.txt", "Informe due:2024-01-05 +trabajo\n(B) Revisar\n", FormatTodoTxt, 2, []string{AttrPriority, AttrProject, AttrDue}, nil},
+		{"formato desconocido", "xlsx", "tareas.xlsx", "Informe", "", 0, nil, ErrUnknownFormat},
+		{"archivo vacío", FormatTodoTxt, "todo.txt", "\n\n", "", 0, nil, ErrEmptyFile},
+		{"CSV solo con encabezado", FormatCSV, "tareas.csv", "title,completed\n", "", 0, nil, ErrEmptyFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.format, tt.filename, []byte(tt.data), Options{})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, result.Format)
+			assert.Len(t, result.Items, tt.wantItems)
+			assert.Equal(t, tt.wantIgnored, result.Ignored)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// jsonTask representa una tarea de la exportación JSON de esta API
+type jsonTask struct {
+	Title     string          `json:"title"`
+	Completed json.RawMessage `json:"completed"`
+}
+
+// parseJSON interpreta un arreglo de tareas o un objeto que lo contiene en "data" o "tasks",
+// como la respuesta de GET /api/tasks o la exportación en JSON
+func parseJSON(data []byte, result *Result) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := seekTaskArray(decoder); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("JSON inválido en la línea %d: %w", lineAt(data, decoder.InputOffset()), err)
+		}
+		// La línea del elemento es la de su primer carácter
+		line := lineAt(data, decoder.InputOffset()-int64(len(raw)))
+
+		var task jsonTask
+		if err := json.Unmarshal(raw, &task); err != nil {
+			result.addError(line, "la tarea debe ser un objeto con title y completed")
+			continue
+		}
+
+		item := Item{Line: line, Title: task.Title}
+		if len(task.Completed) > 0 && string(task.Completed) != "null" {
+			if err := json.Unmarshal(task.Completed, &item.Completed); err != nil {
+				result.addError(line, "completed debe ser true o false")
+				continue
+			}
+		}
+		result.addItem(item)
+	}
+	return nil
+}
+
+// seekTaskArray avanza el decoder hasta el inicio del arreglo de tareas
+func seekTaskArray(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("JSON inválido: %w", err)
+	}
+	if token == json.Delim('[') {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return errors.New("el JSON debe ser un arreglo de tareas o un objeto con data o tasks")
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("JSON inválido: %w", err)
+		}
+		if key == "data" || key == "tasks" {
+			token, err := decoder.Token()
+			if err != nil {
+				return fmt.Errorf("JSON inválido: %w", err)
+			}
+			if token != json.Delim('[') {
+				return fmt.Errorf("%s debe ser un arreglo de tareas", key)
+			}
+			return nil
+		}
+		// Omitir el valor de cualquier otra clave
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return fmt.Errorf("JSON inválido: %w", err)
+		}
+	}
+	return errors.New("el JSON debe ser un arreglo de tareas o un objeto con data o tasks")
+}
+
+// lineAt calcula el número de línea (desde 1) de una posición del archivo
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return 1 + bytes.Count(data[:offset], []byte{'\n'})
+}
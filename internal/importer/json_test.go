@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantItems  []Item
+		wantErrors []LineError
+	}{
+		{"arreglo de tareas", `[{"title":"Informe","completed":true},{"title":"Revisar"}]`,
+			[]Item{{Line: 1, Title: "Informe", Completed: true}, {Line: 1, Title: "Revisar"}}, nil},
+		{"respuesta de la API con data", "{\n  \"success\": true,\n  \"data\": [\n    {\"id\": 1, \"title\": \"Informe\", \"completed\": false},\n    {\"id\": 2, \"title\": \"Revisar\", \"completed\": null}\n  ]\n}",
+			[]Item{{Line: 4, Title: "Informe"}, {Line: 5, Title: "Revisar"}}, nil},
+		{"objeto con tasks", `{"tasks":[{"title":"Informe"}]}`,
+			[]Item{{Line: 1, Title: "Informe"}}, nil},
+		{"elemento que no es un objeto", "[\n\"Informe\",\n{\"title\":\"Revisar\"}\n]",
+			[]Item{{Line: 3, Title: "Revisar"}},
+			[]LineError{{Line: 2, Message: "la tarea debe ser un objeto con title y completed"}}},
+		{"completed que no es booleano", "[\n{\"title\":\"Informe\",\"completed\":\"sí\"}\n]",
+			nil,
+			[]LineError{{Line: 2, Message: "completed debe ser true o false"}}},
+		{"título vacío", "[\n{\"title\":\"  \"}\n]",
+			nil,
+			[]LineError{{Line: 2, Message: "el título es requerido"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatJSON, "tareas.json", []byte(tt.data), Options{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantItems, result.Items)
+			assert.Equal(t, tt.wantErrors, result.Errors)
+		})
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"vacío", "", "JSON inválido"},
+		{"valor suelto", `"Informe"`, "el JSON debe ser un arreglo de tareas"},
+		{"objeto sin tareas", `{"success":true}`, "el JSON debe ser un arreglo de tareas"},
+		{"data que no es un arreglo", `{"data":{"title":"Informe"}}`, "data debe ser un arreglo de tareas"},
+		{"arreglo sin cerrar", "[\n{\"title\":\"Informe\"},\n{\"title\":", "JSON inválido en la línea"},
+		{"arreglo vacío", `[]`, ErrEmptyFile.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatJSON, "tareas.json", []byte(tt.data), Options{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Nil(t, result)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\([A-Z]\)$`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// parseTodoTxt interpreta un archivo todo.txt. Cada línea no vacía es una tarea:
+//
+//	x 2024-01-03 2024-01-01 (A) Título +proyecto @contexto due:2024-01-05
+//
+// La "x" inicial marca la tarea como completada; las fechas de creación y completado se descartan.
+// Los proyectos, contextos y pares clave:valor se quitan del título.
+func parseTodoTxt(data []byte, result *Result) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		tokens := strings.Fields(scanner.Text())
+		if len(tokens) == 0 {
+			continue
+		}
+
+		item := Item{Line: line}
+		if tokens[0] == "x" {
+			item.Completed = true
+			tokens = tokens[1:]
+		}
+		if len(tokens) > 0 && !item.Completed && todoTxtPriority.MatchString(tokens[0]) {
+			item.Priority = tokens[0][1:2]
+			tokens = tokens[1:]
+		}
+		// Fecha de completado y de creación
+		for i := 0; i < 2 && len(tokens) > 0 && todoTxtDate.MatchString(tokens[0]); i++ {
+			tokens = tokens[1:]
+		}
+
+		var words []string
+		for _, token := range tokens {
+			switch {
+			case len(token) > 1 && token[0] == '+':
+				item.Projects = append(item.Projects, token[1:])
+			case len(token) > 1 && token[0] == '@':
+				item.Contexts = append(item.Contexts, token[1:])
+			case isTodoTxtTag(token):
+				key, value, _ := strings.Cut(token, ":")
+				switch key {
+				case "due":
+					item.Due = value
+				case "pri":
+					item.Priority = value
+				}
+			default:
+				words = append(words, token)
+			}
+		}
+
+		item.Title = strings.Join(words, " ")
+		result.addItem(item)
+	}
+}
+
+// isTodoTxtTag indica si el token es un par clave:valor (las URL no cuentan como etiquetas)
+func isTodoTxtTag(token string) bool {
+	key, value, ok := strings.Cut(token, ":")
+	return ok && key != "" && value != "" && !strings.HasPrefix(value, "//")
+}
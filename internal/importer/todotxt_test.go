@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Item
+	}{
+		{"tarea simple", "Comprar pan\n",
+			[]Item{{Line: 1, Title: "Comprar pan"}}},
+		{"prioridad", "(A) Llamar a Ana\n",
+			[]Item{{Line: 1, Title: "Llamar a Ana", Priority: "A"}}},
+		{"prioridad y fecha de creación", "(B) 2024-01-02 Informe\n",
+			[]Item{{Line: 1, Title: "Informe", Priority: "B"}}},
+		{"completada con fechas de completado y creación", "x 2024-01-03 2024-01-01 Informe\n",
+			[]Item{{Line: 1, Title: "Informe", Completed: true}}},
+		{"completada con la prioridad en pri:", "x 2024-01-03 Informe pri:A\n",
+			[]Item{{Line: 1, Title: "Informe", Completed: true, Priority: "A"}}},
+		{"la x solo marca completada al inicio", "Revisar x y z\n",
+			[]Item{{Line: 1, Title: "Revisar x y z"}}},
+		{"la X mayúscula no marca completada", "X Revisar\n",
+			[]Item{{Line: 1, Title: "X Revisar"}}},
+		{"prioridad fuera del inicio", "Informe (A)\n",
+			[]Item{{Line: 1, Title: "Informe (A)"}}},
+		{"prioridad en minúscula", "(a) Informe\n",
+			[]Item{{Line: 1, Title: "(a) Informe"}}},
+		{"proyectos, contextos y vencimiento", "Informe +trabajo @oficina due:2024-01-05 +q1\n",
+			[]Item{{Line: 1, Title: "Informe", Projects: []string{"trabajo", "q1"}, Contexts: []string{"oficina"}, Due: "2024-01-05"}}},
+		{"las URL se conservan en el título", "Leer https://example.com/doc\n",
+			[]Item{{Line: 1, Title: "Leer https://example.com/doc"}}},
+		{"signos sueltos en el título", "Sumar + y @ sin nombre\n",
+			[]Item{{Line: 1, Title: "Sumar + y @ sin nombre"}}},
+		{"líneas vacías y finales de línea de Windows", "Una\r\n\r\n  \r\nDos\r\n",
+			[]Item{{Line: 1, Title: "Una"}, {Line: 4, Title: "Dos"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatTodoTxt, "todo.txt", []byte(tt.data), Options{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Items)
+			assert.Empty(t, result.Errors)
+		})
+	}
+}
+
+func TestParseTodoTxtErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantItems  int
+		wantErrors []LineError
+	}{
+		{"solo etiquetas", "Informe\n+trabajo @oficina due:2024-01-05\n", 1,
+			[]LineError{{Line: 2, Message: "el título es requerido"}}},
+		{"solo la marca de completada", "x\n", 0,
+			[]LineError{{Line: 1, Message: "el título es requerido"}}},
+		{"título demasiado largo", strings.Repeat("a", maxTitleLength+1) + "\nCorta\n", 1,
+			[]LineError{{Line: 1, Message: "el título excede los 200 caracteres"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(FormatTodoTxt, "todo.txt", []byte(tt.data), Options{})
+			require.NoError(t, err)
+			assert.Len(t, result.Items, tt.wantItems)
+			assert.Equal(t, tt.wantErrors, result.Errors)
+		})
+	}
+}
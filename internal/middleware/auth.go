@@ -53,3 +53,15 @@ func GetUserID(c *gin.Context) (uint, bool) {
 	}
 	return userID.(uint), true
 }
+
+// TokenFromQuery copia el token del parámetro access_token al encabezado Authorization.
+// Los navegadores no permiten encabezados propios al abrir un WebSocket; se registra antes de AuthMiddleware.
+// Logger omite el valor del parámetro en los logs de las peticiones.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// BasicAuth autentica con HTTP Basic usando el email del usuario y una contraseña de aplicación.
+// Los clientes que no pueden obtener un JWT, como los de CalDAV, usan este middleware en lugar de AuthMiddleware.
+func BasicAuth(realm string, appPasswords service.AppPasswordService) gin.HandlerFunc {
+	challenge := `Basic realm="` + realm + `", charset="UTF-8"`
+	return func(c *gin.Context) {
+		email, password, ok := c.Request.BasicAuth()
+		if !ok {
+			c.Header("WWW-Authenticate", challenge)
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Credenciales no proporcionadas")
+			c.Abort()
+			return
+		}
+
+		user, err := appPasswords.Authenticate(c.Request.Context(), email, password)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAppPassword) {
+				c.Header("WWW-Authenticate", challenge)
+				utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			} else {
+				utils.ErrorResponse(c, http.StatusInternalServerError, "Error al autenticar: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		// Los mismos datos que AuthMiddleware, para que los handlers funcionen con ambos
+		c.Set("userID", user.ID)
+		c.Set("userEmail", user.Email)
+
+		c.Next()
+	}
+}
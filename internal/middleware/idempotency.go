@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength es la longitud máxima del encabezado Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders son los encabezados de la respuesta original que se repiten en los reintentos
+var replayedHeaders = []string{"Content-Type", "ETag", "Location", "Last-Modified", "Content-Disposition"}
+
+// responseRecorder copia el cuerpo de la respuesta mientras se escribe al cliente
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency atiende el encabezado Idempotency-Key en las peticiones POST.
+// La primera petición con una clave se ejecuta y su respuesta se guarda; los reintentos con la
+// misma clave y el mismo cuerpo reciben la respuesta guardada sin volver a ejecutarse.
+// Debe registrarse después de AuthMiddleware, ya que las claves son propias de cada usuario.
+// El cuerpo se lee completo para calcular su hash, así que se limita a maxBody bytes.
+func Idempotency(idempotency service.IdempotencyService, maxBody int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.ErrorResponse(c, http.StatusBadRequest, "El encabezado Idempotency-Key es demasiado largo")
+			c.Abort()
+			return
+		}
+
+		userID, exists := GetUserID(c)
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Usuario no autenticado")
+			c.Abort()
+			return
+		}
+
+		// Leer el cuerpo para calcular su hash y restaurarlo para el handler
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("El cuerpo de la petición excede el tamaño máximo de %d bytes", maxBody))
+			} else {
+				utils.ErrorResponse(c, http.StatusBadRequest, "No se pudo leer el cuerpo de la petición")
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		stored, err := idempotency.Begin(ctx, userID, key, requestHash(c.Request.Method, c.Request.URL.RequestURI(), body))
+		if err != nil {
+			switch err {
+			case service.ErrIdempotencyKeyMismatch:
+				utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			case service.ErrIdempotencyKeyInProgress:
+				utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			default:
+				utils.ErrorResponse(c, http.StatusInternalServerError, "Error al procesar la clave de idempotencia: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		// Reintento: repetir la respuesta guardada con sus encabezados
+		if stored != nil {
+			var header http.Header
+			if stored.ResponseHeaders != "" {
+				if err := json.Unmarshal([]byte(stored.ResponseHeaders), &header); err != nil {
+					log.Println("Error al leer los encabezados de la respuesta idempotente:", err)
+				}
+			}
+			for name, values := range header {
+				for _, value := range values {
+					c.Writer.Header().Add(name, value)
+				}
+			}
+			contentType := header.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/json; charset=utf-8"
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, contentType, []byte(stored.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		// El resultado se guarda aunque el cliente se desconecte antes de recibirlo
+		saveCtx := context.WithoutCancel(ctx)
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Si la respuesta no llega a guardarse (pánico, error del servidor o fallo al guardarla)
+		// la clave se libera para que un reintento vuelva a ejecutar la petición
+		saved := false
+		defer func() {
+			if saved {
+				return
+			}
+			if err := idempotency.Release(saveCtx, userID, key); err != nil {
+				log.Println("Error al liberar la clave de idempotencia:", err)
+			}
+		}()
+
+		c.Next()
+
+		// Los errores del servidor no se guardan para que el cliente pueda reintentar
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		header := http.Header{}
+		for _, name := range replayedHeaders {
+			if values := recorder.Header().Values(name); len(values) > 0 {
+				header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+		if err := idempotency.Complete(saveCtx, userID, key, status, header, recorder.body.Bytes()); err != nil {
+			log.Println("Error al guardar la respuesta idempotente:", err)
+			return
+		}
+		saved = true
+	}
+}
+
+// requestHash identifica una petición por su método, ruta y cuerpo
+func requestHash(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIdempotency es un IdempotencyService en memoria para las pruebas del middleware
+type fakeIdempotency struct {
+	keys        map[string]*domain.IdempotencyKey
+	begun       int
+	completeErr error
+}
+
+func newFakeIdempotency() *fakeIdempotency {
+	return &fakeIdempotency{keys: map[string]*domain.IdempotencyKey{}}
+}
+
+func (f *fakeIdempotency) Begin(ctx context.Context, userID uint, key, requestHash string) (*domain.IdempotencyKey, error) {
+	f.begun++
+	if existing, ok := f.keys[key]; ok {
+		return existing, nil
+	}
+	f.keys[key] = &domain.IdempotencyKey{UserID: userID, Key: key, RequestHash: requestHash}
+	return nil, nil
+}
+
+func (f *fakeIdempotency) Complete(ctx context.Context, userID uint, key string, statusCode int, header http.Header, body []byte) error {
+	if f.completeErr != nil {
+		return f.completeErr
+	}
+	headers, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	f.keys[key].StatusCode = statusCode
+	f.keys[key].ResponseHeaders = string(headers)
+	f.keys[key].ResponseBody = string(body)
+	return nil
+}
+
+func (f *fakeIdempotency) Release(ctx context.Context, userID uint, key string) error {
+	delete(f.keys, key)
+	return nil
+}
+
+func (f *fakeIdempotency) PurgeExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// newIdempotencyRouter crea un router con el middleware y un handler que devuelve el cuerpo recibido
+func newIdempotencyRouter(idempotency *fakeIdempotency, maxBody int64) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	router := gin.New()
+	router.POST("/items", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	}, Idempotency(idempotency, maxBody), func(c *gin.Context) {
+		calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusCreated, "application/json; charset=utf-8", body)
+	})
+	return router, &calls
+}
+
+func TestIdempotencyBodyLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		key      string
+		wantCode int
+		wantCall bool
+	}{
+		{"dentro del límite", `{"title":"ok"}`, "k1", http.StatusCreated, true},
+		{"en el límite", strings.Repeat("a", 32), "k2", http.StatusCreated, true},
+		{"excede el límite", strings.Repeat("a", 33), "k3", http.StatusRequestEntityTooLarge, false},
+		{"sin clave no se limita aquí", strings.Repeat("a", 64), "", http.StatusCreated, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idempotency := newFakeIdempotency()
+			router, calls := newIdempotencyRouter(idempotency, 32)
+
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantCall, *calls == 1)
+			if tt.wantCode == http.StatusRequestEntityTooLarge {
+				assert.Zero(t, idempotency.begun, "no debe reservarse la clave")
+			}
+		})
+	}
+}
+
+func TestIdempotencyReplayHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+	}{
+		{"JSON con ETag y Location", map[string]string{
+			"Content-Type": "application/json; charset=utf-8",
+			"ETag":         `"3"`,
+			"Location":     "/api/tasks/7",
+		}},
+		{"cuerpo que no es JSON", map[string]string{
+			"Content-Type":        "text/csv; charset=utf-8",
+			"Content-Disposition": `attachment; filename="tareas.csv"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			calls := 0
+			router := gin.New()
+			router.POST("/items", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+			}, Idempotency(newFakeIdempotency(), 1024), func(c *gin.Context) {
+				calls++
+				for name, value := range tt.header {
+					c.Header(name, value)
+				}
+				c.Header("X-Request-Only", "no se repite")
+				c.String(http.StatusCreated, "respuesta %d", calls)
+			})
+
+			send := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{}`))
+				req.Header.Set("Idempotency-Key", "clave")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+			original, replay := send(), send()
+
+			assert.Equal(t, 1, calls)
+			assert.Equal(t, http.StatusCreated, replay.Code)
+			assert.Equal(t, original.Body.String(), replay.Body.String())
+			assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+			for name, value := range tt.header {
+				assert.Equal(t, value, replay.Header().Get(name), name)
+			}
+			assert.Empty(t, replay.Header().Get("X-Request-Only"))
+		})
+	}
+}
+
+// Si la respuesta no se guarda la clave se libera: un reintento vuelve a ejecutar la petición
+// en lugar de recibir 409 hasta que la reserva venza
+func TestIdempotencyReleasesUnsavedKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     gin.HandlerFunc
+		completeErr error
+		wantCode    int
+		wantStored  bool
+	}{
+		{"respuesta guardada", func(c *gin.Context) { c.Status(http.StatusCreated) }, nil, http.StatusCreated, true},
+		{"error del cliente guardado", func(c *gin.Context) { c.Status(http.StatusBadRequest) }, nil, http.StatusBadRequest, true},
+		{"error del servidor", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) }, nil, http.StatusServiceUnavailable, false},
+		{"pánico en el handler", func(c *gin.Context) { panic("fallo") }, nil, http.StatusInternalServerError, false},
+		{"fallo al guardar la respuesta", func(c *gin.Context) { c.Status(http.StatusCreated) }, errors.New("sin conexión"), http.StatusCreated, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			idempotency := newFakeIdempotency()
+			idempotency.completeErr = tt.completeErr
+			router := gin.New()
+			router.Use(gin.Recovery())
+			router.POST("/items", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+			}, Idempotency(idempotency, 1024), tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{}`))
+			req.Header.Set("Idempotency-Key", "clave")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			_, stored := idempotency.keys["clave"]
+			assert.Equal(t, tt.wantStored, stored)
+		})
+	}
+}
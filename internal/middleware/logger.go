@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sensitiveQuery encuentra los parámetros de la URL que llevan credenciales (TokenFromQuery)
+var sensitiveQuery = regexp.MustCompile(`([?&]access_token=)[^&]*`)
+
+// Logger registra cada petición con el mismo formato que el logger de gin, pero sin el valor
+// de access_token: la URL de los WebSocket lleva el token y no debe quedar en los logs
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{Formatter: logFormatter})
+}
+
+// logFormatter reproduce el formato por defecto de gin con la ruta sin credenciales
+func logFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactPath(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactPath reemplaza el valor de los parámetros con credenciales de una ruta con su consulta
+func redactPath(path string) string {
+	return sensitiveQuery.ReplaceAllString(path, "${1}REDACTED")
+}
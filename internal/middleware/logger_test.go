@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"sin consulta", "/api/ws", "/api/ws"},
+		{"solo el token", "/api/ws?access_token=abc.def.ghi", "/api/ws?access_token=REDACTED"},
+		{"token entre otros parámetros", "/api/ws?room=1&access_token=abc&x=2", "/api/ws?room=1&access_token=REDACTED&x=2"},
+		{"token vacío", "/api/ws?access_token=", "/api/ws?access_token=REDACTED"},
+		{"parámetro con un nombre parecido", "/api/ws?my_access_token=abc", "/api/ws?my_access_token=abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redactPath(tt.path))
+		})
+	}
+}
+
+func TestLoggerRedactsAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &out
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+
+	router := gin.New()
+	router.Use(Logger())
+	router.GET("/api/ws", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ws?access_token=secreto.jwt", nil))
+
+	assert.Contains(t, out.String(), "/api/ws?access_token=REDACTED")
+	assert.NotContains(t, out.String(), "secreto.jwt")
+}
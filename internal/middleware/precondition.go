@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch exige el encabezado If-Match en las peticiones que modifican recursos.
+// Si required es false el encabezado es opcional y solo se valida cuando se envía.
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader("If-Match") == "" {
+			utils.ErrorResponse(c, http.StatusPreconditionRequired, "El encabezado If-Match es requerido")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,506 @@
+// Package migrations aplica las migraciones versionadas del esquema de la base de datos.
+//
+// Cada migración es un par de archivos SQL embebidos en el binario, NNNN_nombre.up.sql y
+// NNNN_nombre.down.sql, en el directorio de cada motor (postgres, mysql y sqlite). Los tres
+// directorios tienen las mismas versiones. Las versiones aplicadas se registran en la tabla
+// schema_migrations y un bloqueo evita que varias instancias migren a la vez.
+//
+// Los archivos pueden tener varias sentencias; cada una termina con ";" al final de una línea.
+// Un comentario "-- +si-no-existe columna|indice <tabla> <nombre>" antes de una sentencia la
+// omite si ese objeto ya existe.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+const (
+	// lockKey identifica el bloqueo consultivo de las migraciones en PostgreSQL
+	lockKey int64 = 4_719_622_318_004_211
+	// lockName identifica el bloqueo de las migraciones en MySQL
+	lockName = "gin_tasks_api_migrations"
+)
+
+// fileName es el formato de los archivos de migración: 0001_nombre.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	// ErrNewerSchema indica que la base de datos tiene migraciones que este binario no conoce,
+	// es decir, que la migró una versión más nueva de la aplicación
+	ErrNewerSchema = errors.New("el esquema de la base de datos es más nuevo que esta versión de la aplicación")
+	// ErrPending indica que hay migraciones sin aplicar
+	ErrPending = errors.New("hay migraciones pendientes")
+
+	// errAlreadyApplied indica que otra instancia aplicó la migración antes
+	errAlreadyApplied = errors.New("migración ya aplicada")
+)
+
+// Migration es una migración versionada
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String devuelve el nombre de la migración como en sus archivos, por ejemplo 0001_baseline
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status es el estado de una migración en la base de datos
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt int64 // 0 si está pendiente
+	Unknown   bool  // aplicada en la base de datos pero no incluida en este binario
+}
+
+// record es una fila de schema_migrations
+type record struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(255);not null"`
+	AppliedAt int64  `gorm:"not null"`
+}
+
+// TableName especifica el nombre de la tabla de versiones
+func (record) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator aplica y revierte las migraciones de un motor de base de datos
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New crea el migrador con las migraciones embebidas para el motor de db
+func New(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	dir, err := fs.Sub(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("migraciones de %s: %w", dialect, err)
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no hay migraciones para el motor %s", dialect)
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load lee las migraciones de un directorio y las ordena por versión
+func load(dir fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(dir, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nombre de archivo inválido %q", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("versión inválida en %q", entry.Name())
+		}
+		content, err := fs.ReadFile(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := splitStatements(string(content)); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("la versión %d tiene dos nombres: %s y %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("la migración %s necesita los archivos .up.sql y .down.sql", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up aplica las migraciones pendientes en orden y devuelve las aplicadas.
+// Cada migración se ejecuta en su propia transacción junto con su registro en schema_migrations.
+// En MySQL las sentencias DDL confirman la transacción implícitamente: si una migración falla
+// a mitad, sus primeras sentencias quedan aplicadas sin registrar.
+// Devuelve ErrNewerSchema sin aplicar nada si la base de datos tiene versiones desconocidas.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		if unknown := m.unknown(done); len(unknown) > 0 {
+			return newerSchemaError(unknown)
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				// Sin bloqueo con nombre (SQLite) otra instancia pudo aplicarla mientras esperábamos
+				var count int64
+				if err := tx.Model(&record{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return errAlreadyApplied
+				}
+				if err := execStatements(tx, migration.Up); err != nil {
+					return err
+				}
+				return tx.Create(&record{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().Unix()}).Error
+			})
+			if errors.Is(err, errAlreadyApplied) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error al aplicar la migración %s: %w", migration, err)
+			}
+			log.Printf("Migración %s aplicada", migration)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down revierte las últimas steps migraciones aplicadas, de la más nueva a la más antigua,
+// y devuelve las revertidas
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		var records []record
+		if err := conn.Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+			return err
+		}
+
+		for _, rec := range records {
+			migration, ok := m.find(rec.Version)
+			if !ok {
+				return newerSchemaError([]int64{rec.Version})
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := execStatements(tx, migration.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&record{}, rec.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error al revertir la migración %s: %w", migration, err)
+			}
+			log.Printf("Migración %s revertida", migration)
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status devuelve el estado de todas las migraciones conocidas, seguidas de las versiones
+// aplicadas que este binario no conoce
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	done, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if rec, ok := done[migration.Version]; ok {
+			status.AppliedAt = rec.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for _, version := range m.unknown(done) {
+		rec := done[version]
+		statuses = append(statuses, Status{Version: version, Name: rec.Name, AppliedAt: rec.AppliedAt, Unknown: true})
+	}
+	return statuses, nil
+}
+
+// Check comprueba que el esquema coincide con las migraciones de este binario.
+// Devuelve ErrNewerSchema si hay versiones desconocidas y ErrPending si falta aplicar alguna.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var unknown []int64
+	pending := 0
+	for _, status := range statuses {
+		switch {
+		case status.Unknown:
+			unknown = append(unknown, status.Version)
+		case status.AppliedAt == 0:
+			pending++
+		}
+	}
+	if len(unknown) > 0 {
+		return newerSchemaError(unknown)
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w (%d)", ErrPending, pending)
+	}
+	return nil
+}
+
+// applied devuelve las migraciones registradas en schema_migrations, por versión.
+// Si la tabla todavía no existe no hay ninguna aplicada.
+func (m *Migrator) applied(db *gorm.DB) (map[int64]record, error) {
+	done := make(map[int64]record)
+	if !db.Migrator().HasTable(&record{}) {
+		return done, nil
+	}
+	var records []record
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("error al leer schema_migrations: %w", err)
+	}
+	for _, rec := range records {
+		done[rec.Version] = rec
+	}
+	return done, nil
+}
+
+// unknown devuelve, ordenadas, las versiones aplicadas que no están entre las migraciones conocidas
+func (m *Migrator) unknown(done map[int64]record) []int64 {
+	var versions []int64
+	for version := range done {
+		if _, ok := m.find(version); !ok {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+// find busca una migración conocida por versión
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// withLock ejecuta fn en una única conexión mientras mantiene el bloqueo de migraciones.
+// Si otra instancia está migrando, espera a que termine; después fn ve sus cambios.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		unlock, err := lock(conn)
+		if err != nil {
+			return fmt.Errorf("error al obtener el bloqueo de migraciones: %w", err)
+		}
+		// El bloqueo pertenece a la sesión: se libera aunque ctx se haya cancelado
+		// para no devolver al pool una conexión que lo mantiene
+		defer func() {
+			if err := unlock(conn.WithContext(context.Background())); err != nil {
+				log.Println("Error al liberar el bloqueo de migraciones:", err)
+			}
+		}()
+
+		err = conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at BIGINT NOT NULL
+		)`).Error
+		if err != nil {
+			return fmt.Errorf("error al crear schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+// lock toma el bloqueo de migraciones en la conexión y devuelve la función que lo libera.
+// PostgreSQL y MySQL usan bloqueos con nombre que esperan sin límite; SQLite no los necesita
+// porque las transacciones de escritura ya se serializan sobre el archivo.
+func lock(conn *gorm.DB) (func(conn *gorm.DB) error, error) {
+	switch conn.Dialector.Name() {
+	case "postgres":
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return nil, err
+		}
+		return func(conn *gorm.DB) error {
+			return conn.Exec("SELECT pg_advisory_unlock(?)", lockKey).Error
+		}, nil
+
+	case "mysql":
+		var acquired int
+		if err := conn.Raw("SELECT GET_LOCK(?, -1)", lockName).Scan(&acquired).Error; err != nil {
+			return nil, err
+		}
+		if acquired != 1 {
+			return nil, errors.New("GET_LOCK no obtuvo el bloqueo")
+		}
+		return func(conn *gorm.DB) error {
+			return conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error
+		}, nil
+
+	default:
+		return func(*gorm.DB) error { return nil }, nil
+	}
+}
+
+// guardPrefix marca un comentario que condiciona la sentencia siguiente a que no exista una
+// columna o un índice, por ejemplo "-- +si-no-existe columna tasks position". Permite que las
+// bases de datos creadas por AutoMigrate adopten el esquema en los motores sin
+// ADD COLUMN IF NOT EXISTS.
+const guardPrefix = "-- +si-no-existe "
+
+// statement es una sentencia de un archivo de migración
+type statement struct {
+	sql   string
+	guard *guard
+}
+
+// guard es la condición de una sentencia: solo se ejecuta si no existe el objeto
+type guard struct {
+	kind  string // columna o indice
+	table string
+	name  string
+}
+
+// parseGuard interpreta el texto que sigue a guardPrefix
+func parseGuard(text string) (*guard, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 3 || (fields[0] != "columna" && fields[0] != "indice") {
+		return nil, fmt.Errorf("condición inválida %q: use columna|indice <tabla> <nombre>", text)
+	}
+	return &guard{kind: fields[0], table: fields[1], name: fields[2]}, nil
+}
+
+// exists consulta el catálogo del motor para saber si la columna o el índice ya existen
+func (g *guard) exists(tx *gorm.DB) (bool, error) {
+	var query string
+	switch tx.Dialector.Name() + " " + g.kind {
+	case "postgres columna":
+		query = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?"
+	case "postgres indice":
+		query = "SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ? AND indexname = ?"
+	case "mysql columna":
+		query = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+	case "mysql indice":
+		query = "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?"
+	case "sqlite columna":
+		query = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	case "sqlite indice":
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?"
+	default:
+		return false, fmt.Errorf("condición %s no disponible para el motor %s", g.kind, tx.Dialector.Name())
+	}
+	var count int64
+	if err := tx.Raw(query, g.table, g.name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// execStatements ejecuta las sentencias de un archivo de migración una por una,
+// ya que no todos los drivers aceptan varias sentencias en una sola llamada
+func execStatements(tx *gorm.DB, sql string) error {
+	statements, err := splitStatements(sql)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if statement.guard != nil {
+			exists, err := statement.guard.exists(tx)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+		}
+		if err := tx.Exec(statement.sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements separa un archivo SQL en sentencias: cada una termina con ";" al final
+// de una línea. Las líneas de comentario (--) se descartan, salvo las condiciones
+// (guardPrefix), que se aplican a la sentencia siguiente.
+func splitStatements(sql string) ([]statement, error) {
+	var statements []statement
+	var current strings.Builder
+	var pending *guard
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, guardPrefix) {
+			if pending != nil || current.Len() > 0 {
+				return nil, fmt.Errorf("condición %q fuera de lugar", trimmed)
+			}
+			g, err := parseGuard(strings.TrimPrefix(trimmed, guardPrefix))
+			if err != nil {
+				return nil, err
+			}
+			pending = g
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, statement{sql: strings.TrimSpace(current.String()), guard: pending})
+			current.Reset()
+			pending = nil
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, statement{sql: rest, guard: pending})
+	} else if pending != nil {
+		return nil, errors.New("condición sin sentencia al final del archivo")
+	}
+	return statements, nil
+}
+
+// newerSchemaError describe las versiones desconocidas de la base de datos
+func newerSchemaError(versions []int64) error {
+	return fmt.Errorf("%w (versiones desconocidas: %v)", ErrNewerSchema, versions)
+}
@@ -0,0 +1,154 @@
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// legacyUser y legacyTask reproducen las tablas que creaba AutoMigrate en la primera versión
+// de la aplicación, antes de la posición, las versiones, la sincronización y los administradores
+type legacyUser struct {
+	ID        uint           `gorm:"primaryKey"`
+	FullName  string         `gorm:"type:varchar(100);not null"`
+	Email     string         `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Password  string         `gorm:"type:varchar(255);not null"`
+	Tasks     []legacyTask   `gorm:"foreignKey:UserID"`
+	CreatedAt int64          `gorm:"autoCreateTime"`
+	UpdatedAt int64          `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (legacyUser) TableName() string { return "users" }
+
+type legacyTask struct {
+	ID        uint           `gorm:"primaryKey"`
+	Title     string         `gorm:"type:varchar(200);not null"`
+	Completed bool           `gorm:"default:false"`
+	UserID    uint           `gorm:"not null;index"`
+	CreatedAt int64          `gorm:"autoCreateTime"`
+	UpdatedAt int64          `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (legacyTask) TableName() string { return "tasks" }
+
+// newTestDB crea una base SQLite temporal vacía. No usa testutil.NewDB porque las pruebas
+// aplican las migraciones ellas mismas, y testutil importa este paquete.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := config.ConnectDB(&config.Config{
+		GinMode:     "test",
+		DBDriver:    config.DriverSQLite,
+		URLDatabase: filepath.Join(t.TempDir(), "tasks.db"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { config.CloseDB(db) })
+	return db
+}
+
+// Una base de datos creada por AutoMigrate adopta el esquema inicial y recibe las columnas
+// que las tablas existentes no tenían, con sus valores por defecto en las filas antiguas
+func TestUpAdoptsLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&legacyUser{}, &legacyTask{}))
+	require.NoError(t, db.Create(&legacyUser{FullName: "Ana", Email: "ana@example.com", Password: "x",
+		Tasks: []legacyTask{{Title: "Informe"}}}).Error)
+
+	migrator, err := New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	for table, columns := range map[string][]string{
+		"users": {"is_admin", "version"},
+		"tasks": {"position", "client_id", "version"},
+	} {
+		for _, column := range columns {
+			assert.True(t, db.Migrator().HasColumn(table, column), "%s.%s", table, column)
+		}
+	}
+	for _, index := range []string{"idx_tasks_position", "idx_tasks_user_updated", "idx_tasks_user_client"} {
+		assert.True(t, db.Migrator().HasIndex("tasks", index), index)
+	}
+
+	var task struct {
+		Title    string
+		Position string
+		Version  int64
+	}
+	require.NoError(t, db.Table("tasks").Select("title, position, version").Take(&task).Error)
+	assert.Equal(t, "Informe", task.Title)
+	assert.Equal(t, "", task.Position)
+	assert.Equal(t, int64(1), task.Version)
+
+	var user struct {
+		IsAdmin bool
+		Version int64
+	}
+	require.NoError(t, db.Table("users").Select("is_admin, version").Take(&user).Error)
+	assert.False(t, user.IsAdmin)
+	assert.Equal(t, int64(1), user.Version)
+}
+
+// En una base de datos nueva las condiciones omiten los ALTER TABLE porque CREATE TABLE ya
+// crea las columnas, y las migraciones se pueden revertir y volver a aplicar
+func TestUpDownFreshSchema(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	migrator, err := New(db)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+
+	reverted, err := migrator.Down(ctx, len(migrator.migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(migrator.migrations))
+	assert.False(t, db.Migrator().HasTable("tasks"))
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		want    []statement
+		wantErr bool
+	}{
+		{"varias sentencias", "-- comentario\nCREATE TABLE a (\n  id INTEGER\n);\n\nDROP TABLE b;\n",
+			[]statement{{sql: "CREATE TABLE a (\n  id INTEGER\n);"}, {sql: "DROP TABLE b;"}}, false},
+		{"sin punto y coma final", "DROP TABLE a", []statement{{sql: "DROP TABLE a"}}, false},
+		{"condición de columna", "-- +si-no-existe columna tasks position\nALTER TABLE tasks ADD COLUMN position TEXT;\nDROP TABLE b;",
+			[]statement{{sql: "ALTER TABLE tasks ADD COLUMN position TEXT;", guard: &guard{kind: "columna", table: "tasks", name: "position"}}, {sql: "DROP TABLE b;"}}, false},
+		{"condición de índice", "-- +si-no-existe indice tasks idx_tasks_position\nCREATE INDEX idx_tasks_position ON tasks (position);",
+			[]statement{{sql: "CREATE INDEX idx_tasks_position ON tasks (position);", guard: &guard{kind: "indice", table: "tasks", name: "idx_tasks_position"}}}, false},
+		{"tipo de condición desconocido", "-- +si-no-existe tabla tasks\nCREATE TABLE tasks (id INTEGER);", nil, true},
+		{"condición incompleta", "-- +si-no-existe columna tasks\nALTER TABLE tasks ADD COLUMN x TEXT;", nil, true},
+		{"dos condiciones seguidas", "-- +si-no-existe columna tasks a\n-- +si-no-existe columna tasks b\nALTER TABLE tasks ADD COLUMN b TEXT;", nil, true},
+		{"condición dentro de una sentencia", "ALTER TABLE tasks\n-- +si-no-existe columna tasks a\nADD COLUMN a TEXT;", nil, true},
+		{"condición al final del archivo", "DROP TABLE a;\n-- +si-no-existe columna tasks a\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := splitStatements(tt.sql)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, statements)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+-- Esquema inicial, equivalente al de PostgreSQL.
+-- MySQL no tiene búsqueda con tsvector: las búsquedas usan coincidencia parcial.
+-- Las claves de idempotencia y los client_id de sincronización distinguen mayúsculas,
+-- como en PostgreSQL, por eso usan la colación binaria.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    full_name  VARCHAR(100) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    is_admin   BOOLEAN NOT NULL DEFAULT false,
+    version    BIGINT UNSIGNED NOT NULL DEFAULT 1,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_users_email (email),
+    INDEX idx_users_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- Bases de datos creadas por AutoMigrate antes de que existieran estas columnas
+-- +si-no-existe columna users is_admin
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+-- +si-no-existe columna users version
+ALTER TABLE users ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    title      VARCHAR(200) NOT NULL,
+    completed  BOOLEAN DEFAULT false,
+    position   VARCHAR(255) NOT NULL DEFAULT '',
+    user_id    BIGINT UNSIGNED NOT NULL,
+    client_id  VARCHAR(64) COLLATE utf8mb4_bin,
+    version    BIGINT UNSIGNED NOT NULL DEFAULT 1,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_tasks_user_id (user_id),
+    INDEX idx_tasks_position (position),
+    INDEX idx_tasks_user_updated (user_id, updated_at),
+    UNIQUE INDEX idx_tasks_user_client (user_id, client_id),
+    INDEX idx_tasks_deleted_at (deleted_at),
+    CONSTRAINT fk_users_tasks FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +si-no-existe columna tasks position
+ALTER TABLE tasks ADD COLUMN position VARCHAR(255) NOT NULL DEFAULT '';
+-- +si-no-existe columna tasks client_id
+ALTER TABLE tasks ADD COLUMN client_id VARCHAR(64) COLLATE utf8mb4_bin;
+-- +si-no-existe columna tasks version
+ALTER TABLE tasks ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1;
+-- +si-no-existe indice tasks idx_tasks_position
+CREATE INDEX idx_tasks_position ON tasks (position);
+-- +si-no-existe indice tasks idx_tasks_user_updated
+CREATE INDEX idx_tasks_user_updated ON tasks (user_id, updated_at);
+-- +si-no-existe indice tasks idx_tasks_user_client
+CREATE UNIQUE INDEX idx_tasks_user_client ON tasks (user_id, client_id);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    actor_id    BIGINT UNSIGNED,
+    action      VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id   BIGINT UNSIGNED,
+    changes     TEXT,
+    created_at  BIGINT,
+    PRIMARY KEY (id),
+    INDEX idx_activities_actor_id (actor_id),
+    INDEX idx_activities_action (action),
+    INDEX idx_activity_entity (entity_type, entity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(100) NOT NULL,
+    query      VARCHAR(500) NOT NULL,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_saved_filters_user_id (user_id),
+    INDEX idx_saved_filters_deleted_at (deleted_at),
+    CONSTRAINT fk_saved_filters_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id         BIGINT UNSIGNED NOT NULL,
+    idempotency_key VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    request_hash    VARCHAR(64) NOT NULL,
+    status_code     BIGINT NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    created_at      BIGINT,
+    expires_at      BIGINT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_idempotency_user_key (user_id, idempotency_key),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    url           VARCHAR(2048) NOT NULL,
+    secret        VARCHAR(64) NOT NULL,
+    events        VARCHAR(500) NOT NULL,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    disabled_at   BIGINT NOT NULL DEFAULT 0,
+    user_id       BIGINT UNSIGNED NOT NULL,
+    created_at    BIGINT,
+    updated_at    BIGINT,
+    deleted_at    DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhooks_user_id (user_id),
+    INDEX idx_webhooks_deleted_at (deleted_at),
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    webhook_id      BIGINT UNSIGNED NOT NULL,
+    event           VARCHAR(50) NOT NULL,
+    payload         TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    attempts        BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT NOT NULL,
+    response_code   BIGINT NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    error           TEXT,
+    delivered_at    BIGINT NOT NULL DEFAULT 0,
+    created_at      BIGINT,
+    updated_at      BIGINT,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_deliveries_webhook_id (webhook_id),
+    INDEX idx_delivery_queue (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id     BIGINT UNSIGNED NOT NULL,
+    format      VARCHAR(20) NOT NULL,
+    filename    VARCHAR(255),
+    status      VARCHAR(20) NOT NULL,
+    total       BIGINT NOT NULL DEFAULT 0,
+    processed   BIGINT NOT NULL DEFAULT 0,
+    created     BIGINT NOT NULL DEFAULT 0,
+    errors      TEXT,
+    ignored     VARCHAR(255),
+    error       TEXT,
+    created_at  BIGINT,
+    updated_at  BIGINT,
+    finished_at BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    INDEX idx_import_jobs_user_id (user_id),
+    INDEX idx_import_jobs_status (status),
+    CONSTRAINT fk_import_jobs_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(100) NOT NULL,
+    filter     VARCHAR(500) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_calendar_feeds_token_hash (token_hash),
+    INDEX idx_calendar_feeds_user_id (user_id),
+    INDEX idx_calendar_feeds_deleted_at (deleted_at),
+    CONSTRAINT fk_calendar_feeds_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS app_passwords (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name          VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(64) NOT NULL,
+    user_id       BIGINT UNSIGNED NOT NULL,
+    last_used_at  BIGINT NOT NULL DEFAULT 0,
+    created_at    BIGINT,
+    deleted_at    DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_app_passwords_password_hash (password_hash),
+    INDEX idx_app_passwords_user_id (user_id),
+    INDEX idx_app_passwords_deleted_at (deleted_at),
+    CONSTRAINT fk_app_passwords_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
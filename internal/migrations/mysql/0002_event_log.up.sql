@@ -0,0 +1,9 @@
+-- Eventos en tiempo real para EVENTS_BACKEND=database: cada réplica consulta los nuevos
+-- periódicamente. Las filas se eliminan a los pocos minutos.
+CREATE TABLE IF NOT EXISTS event_log (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    payload    TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_event_log_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+-- El historial de entregas ya no guarda el cuerpo de la respuesta del receptor, solo su código:
+-- un webhook apuntado a un servicio interno permitiría leer sus respuestas.
+ALTER TABLE webhook_deliveries DROP COLUMN response_body;
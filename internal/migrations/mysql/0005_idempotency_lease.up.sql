@@ -0,0 +1,3 @@
+-- Fin de la reserva de una petición en curso: al vencer, otro intento con la misma clave
+-- puede tomarla. Las reservas anteriores quedan con 0, es decir, ya vencidas.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,173 @@
+-- Esquema inicial: las tablas que creaba AutoMigrate hasta la introducción de las migraciones
+-- versionadas. Todo usa IF NOT EXISTS para que las bases de datos existentes adopten esta
+-- versión sin cambios.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    full_name  VARCHAR(100) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    is_admin   BOOLEAN NOT NULL DEFAULT false,
+    version    BIGINT NOT NULL DEFAULT 1,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at TIMESTAMPTZ
+);
+-- Bases de datos creadas por AutoMigrate antes de que existieran estas columnas
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id         BIGSERIAL PRIMARY KEY,
+    title      VARCHAR(200) NOT NULL,
+    completed  BOOLEAN DEFAULT false,
+    position   VARCHAR(255) NOT NULL DEFAULT '',
+    user_id    BIGINT NOT NULL,
+    client_id  VARCHAR(64),
+    version    BIGINT NOT NULL DEFAULT 1,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_tasks FOREIGN KEY (user_id) REFERENCES users (id)
+);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS client_id VARCHAR(64);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks (user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks (position);
+CREATE INDEX IF NOT EXISTS idx_tasks_user_updated ON tasks (user_id, updated_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_client ON tasks (user_id, client_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+
+-- Búsqueda de texto completo: el vector combina el análisis en español y en inglés
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('spanish'::regconfig, coalesce(title, '')) ||
+        to_tsvector('english'::regconfig, coalesce(title, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    BIGINT,
+    action      VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id   BIGINT,
+    changes     TEXT,
+    created_at  BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_activities_actor_id ON activities (actor_id);
+CREATE INDEX IF NOT EXISTS idx_activities_action ON activities (action);
+CREATE INDEX IF NOT EXISTS idx_activity_entity ON activities (entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    query      VARCHAR(500) NOT NULL,
+    user_id    BIGINT NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_saved_filters_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_saved_filters_user_id ON saved_filters (user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_filters_deleted_at ON saved_filters (deleted_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    VARCHAR(64) NOT NULL,
+    status_code     BIGINT NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    created_at      BIGINT,
+    expires_at      BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_user_key ON idempotency_keys (user_id, idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id            BIGSERIAL PRIMARY KEY,
+    url           VARCHAR(2048) NOT NULL,
+    secret        VARCHAR(64) NOT NULL,
+    events        VARCHAR(500) NOT NULL,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    disabled_at   BIGINT NOT NULL DEFAULT 0,
+    user_id       BIGINT NOT NULL,
+    created_at    BIGINT,
+    updated_at    BIGINT,
+    deleted_at    TIMESTAMPTZ,
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_deleted_at ON webhooks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    webhook_id      BIGINT NOT NULL,
+    event           VARCHAR(50) NOT NULL,
+    payload         TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    attempts        BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT NOT NULL,
+    response_code   BIGINT NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    error           TEXT,
+    delivered_at    BIGINT NOT NULL DEFAULT 0,
+    created_at      BIGINT,
+    updated_at      BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_delivery_queue ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL,
+    format      VARCHAR(20) NOT NULL,
+    filename    VARCHAR(255),
+    status      VARCHAR(20) NOT NULL,
+    total       BIGINT NOT NULL DEFAULT 0,
+    processed   BIGINT NOT NULL DEFAULT 0,
+    created     BIGINT NOT NULL DEFAULT 0,
+    errors      TEXT,
+    ignored     VARCHAR(255),
+    error       TEXT,
+    created_at  BIGINT,
+    updated_at  BIGINT,
+    finished_at BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_import_jobs_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user_id ON import_jobs (user_id);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs (status);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    filter     VARCHAR(500) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    user_id    BIGINT NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_calendar_feeds_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feeds_token_hash ON calendar_feeds (token_hash);
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user_id ON calendar_feeds (user_id);
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_deleted_at ON calendar_feeds (deleted_at);
+
+CREATE TABLE IF NOT EXISTS app_passwords (
+    id            BIGSERIAL PRIMARY KEY,
+    name          VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(64) NOT NULL,
+    user_id       BIGINT NOT NULL,
+    last_used_at  BIGINT NOT NULL DEFAULT 0,
+    created_at    BIGINT,
+    deleted_at    TIMESTAMPTZ,
+    CONSTRAINT fk_app_passwords_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_app_passwords_password_hash ON app_passwords (password_hash);
+CREATE INDEX IF NOT EXISTS idx_app_passwords_user_id ON app_passwords (user_id);
+CREATE INDEX IF NOT EXISTS idx_app_passwords_deleted_at ON app_passwords (deleted_at);
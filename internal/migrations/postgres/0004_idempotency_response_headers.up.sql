@@ -0,0 +1,3 @@
+-- Encabezados de la respuesta original (Content-Type, ETag, Location...) para repetirlos
+-- en los reintentos, en JSON
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers TEXT;
@@ -0,0 +1,11 @@
+-- Elimina el esquema inicial completo, con todos sus datos
+DROP TABLE IF EXISTS app_passwords;
+DROP TABLE IF EXISTS calendar_feeds;
+DROP TABLE IF EXISTS import_jobs;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS saved_filters;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,169 @@
+-- Esquema inicial, equivalente al de PostgreSQL.
+-- SQLite no tiene búsqueda con tsvector: las búsquedas usan coincidencia parcial.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    full_name  VARCHAR(100) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    is_admin   NUMERIC NOT NULL DEFAULT false,
+    version    INTEGER NOT NULL DEFAULT 1,
+    created_at INTEGER,
+    updated_at INTEGER,
+    deleted_at DATETIME
+);
+-- Bases de datos creadas por AutoMigrate antes de que existieran estas columnas
+-- +si-no-existe columna users is_admin
+ALTER TABLE users ADD COLUMN is_admin NUMERIC NOT NULL DEFAULT false;
+-- +si-no-existe columna users version
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    title      VARCHAR(200) NOT NULL,
+    completed  NUMERIC DEFAULT false,
+    position   VARCHAR(255) NOT NULL DEFAULT '',
+    user_id    INTEGER NOT NULL,
+    client_id  VARCHAR(64),
+    version    INTEGER NOT NULL DEFAULT 1,
+    created_at INTEGER,
+    updated_at INTEGER,
+    deleted_at DATETIME,
+    CONSTRAINT fk_users_tasks FOREIGN KEY (user_id) REFERENCES users (id)
+);
+-- +si-no-existe columna tasks position
+ALTER TABLE tasks ADD COLUMN position VARCHAR(255) NOT NULL DEFAULT '';
+-- +si-no-existe columna tasks client_id
+ALTER TABLE tasks ADD COLUMN client_id VARCHAR(64);
+-- +si-no-existe columna tasks version
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks (user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks (position);
+CREATE INDEX IF NOT EXISTS idx_tasks_user_updated ON tasks (user_id, updated_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_client ON tasks (user_id, client_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id    INTEGER,
+    action      VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id   INTEGER,
+    changes     TEXT,
+    created_at  INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_activities_actor_id ON activities (actor_id);
+CREATE INDEX IF NOT EXISTS idx_activities_action ON activities (action);
+CREATE INDEX IF NOT EXISTS idx_activity_entity ON activities (entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(100) NOT NULL,
+    query      VARCHAR(500) NOT NULL,
+    user_id    INTEGER NOT NULL,
+    created_at INTEGER,
+    updated_at INTEGER,
+    deleted_at DATETIME,
+    CONSTRAINT fk_saved_filters_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_saved_filters_user_id ON saved_filters (user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_filters_deleted_at ON saved_filters (deleted_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    VARCHAR(64) NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    created_at      INTEGER,
+    expires_at      INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_user_key ON idempotency_keys (user_id, idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    url           VARCHAR(2048) NOT NULL,
+    secret        VARCHAR(64) NOT NULL,
+    events        VARCHAR(500) NOT NULL,
+    active        NUMERIC NOT NULL DEFAULT true,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    disabled_at   INTEGER NOT NULL DEFAULT 0,
+    user_id       INTEGER NOT NULL,
+    created_at    INTEGER,
+    updated_at    INTEGER,
+    deleted_at    DATETIME,
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_deleted_at ON webhooks (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id      INTEGER NOT NULL,
+    event           VARCHAR(50) NOT NULL,
+    payload         TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    response_code   INTEGER NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    error           TEXT,
+    delivered_at    INTEGER NOT NULL DEFAULT 0,
+    created_at      INTEGER,
+    updated_at      INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_delivery_queue ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER NOT NULL,
+    format      VARCHAR(20) NOT NULL,
+    filename    VARCHAR(255),
+    status      VARCHAR(20) NOT NULL,
+    total       INTEGER NOT NULL DEFAULT 0,
+    processed   INTEGER NOT NULL DEFAULT 0,
+    created     INTEGER NOT NULL DEFAULT 0,
+    errors      TEXT,
+    ignored     VARCHAR(255),
+    error       TEXT,
+    created_at  INTEGER,
+    updated_at  INTEGER,
+    finished_at INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_import_jobs_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_user_id ON import_jobs (user_id);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs (status);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(100) NOT NULL,
+    filter     VARCHAR(500) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    user_id    INTEGER NOT NULL,
+    created_at INTEGER,
+    updated_at INTEGER,
+    deleted_at DATETIME,
+    CONSTRAINT fk_calendar_feeds_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feeds_token_hash ON calendar_feeds (token_hash);
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user_id ON calendar_feeds (user_id);
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_deleted_at ON calendar_feeds (deleted_at);
+
+CREATE TABLE IF NOT EXISTS app_passwords (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    name          VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(64) NOT NULL,
+    user_id       INTEGER NOT NULL,
+    last_used_at  INTEGER NOT NULL DEFAULT 0,
+    created_at    INTEGER,
+    deleted_at    DATETIME,
+    CONSTRAINT fk_app_passwords_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_app_passwords_password_hash ON app_passwords (password_hash);
+CREATE INDEX IF NOT EXISTS idx_app_passwords_user_id ON app_passwords (user_id);
+CREATE INDEX IF NOT EXISTS idx_app_passwords_deleted_at ON app_passwords (deleted_at);
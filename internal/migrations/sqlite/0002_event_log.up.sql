@@ -0,0 +1,8 @@
+-- Eventos en tiempo real para EVENTS_BACKEND=database: cada réplica consulta los nuevos
+-- periódicamente. Las filas se eliminan a los pocos minutos.
+CREATE TABLE IF NOT EXISTS event_log (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    payload    TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_event_log_created_at ON event_log (created_at);
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ActivityRepository define las operaciones de base de datos para el historial de actividad
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *domain.Activity) error
+	GetByEntity(ctx context.Context, entityType string, entityID uint, offset, limit int) ([]domain.Activity, int64, error)
+	GetByActorID(ctx context.Context, actorID uint, offset, limit int) ([]domain.Activity, int64, error)
+	GetEntityIDsSince(ctx context.Context, actorID uint, action string, since int64) ([]uint, error)
+}
+
+// activityRepository implementa ActivityRepository
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository crea una nueva instancia de ActivityRepository
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// Create agrega un nuevo registro al historial de actividad
+func (r *activityRepository) Create(ctx context.Context, activity *domain.Activity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+// GetByEntity obtiene el historial de una entidad, del más reciente al más antiguo
+func (r *activityRepository) GetByEntity(ctx context.Context, entityType string, entityID uint, offset, limit int) ([]domain.Activity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Activity{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID)
+	return r.paginate(query, offset, limit)
+}
+
+// GetByActorID obtiene el historial de acciones realizadas por un usuario
+func (r *activityRepository) GetByActorID(ctx context.Context, actorID uint, offset, limit int) ([]domain.Activity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Activity{}).
+		Where("actor_id = ?", actorID)
+	return r.paginate(query, offset, limit)
+}
+
+// GetEntityIDsSince obtiene los IDs de las entidades sobre las que un usuario realizó una acción desde la fecha dada
+func (r *activityRepository) GetEntityIDsSince(ctx context.Context, actorID uint, action string, since int64) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&domain.Activity{}).
+		Where("actor_id = ? AND action = ? AND created_at >= ?", actorID, action, since).
+		Distinct().
+		Pluck("entity_id", &ids).Error
+	return ids, err
+}
+
+// paginate cuenta el total de registros y obtiene la página solicitada
+func (r *activityRepository) paginate(query *gorm.DB, offset, limit int) ([]domain.Activity, int64, error) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var activities []domain.Activity
+	err := query.Session(&gorm.Session{}).Order("id DESC").Offset(offset).Limit(limit).Find(&activities).Error
+	return activities, total, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AppPasswordRepository define las operaciones de base de datos para las contraseñas de aplicación
+type AppPasswordRepository interface {
+	Create(ctx context.Context, password *domain.AppPassword) error
+	GetByID(ctx context.Context, id uint) (*domain.AppPassword, error)
+	GetByHash(ctx context.Context, hash string) (*domain.AppPassword, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.AppPassword, error)
+	TouchLastUsed(ctx context.Context, id uint, at int64) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// appPasswordRepository implementa AppPasswordRepository
+type appPasswordRepository struct {
+	db *gorm.DB
+}
+
+// NewAppPasswordRepository crea una nueva instancia de AppPasswordRepository
+func NewAppPasswordRepository(db *gorm.DB) AppPasswordRepository {
+	return &appPasswordRepository{db: db}
+}
+
+// Create guarda una nueva contraseña de aplicación
+func (r *appPasswordRepository) Create(ctx context.Context, password *domain.AppPassword) error {
+	return r.db.WithContext(ctx).Create(password).Error
+}
+
+// GetByID obtiene una contraseña de aplicación por su ID
+func (r *appPasswordRepository) GetByID(ctx context.Context, id uint) (*domain.AppPassword, error) {
+	var password domain.AppPassword
+	err := r.db.WithContext(ctx).First(&password, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &password, err
+}
+
+// GetByHash obtiene una contraseña de aplicación por el hash de su secreto
+func (r *appPasswordRepository) GetByHash(ctx context.Context, hash string) (*domain.AppPassword, error) {
+	var password domain.AppPassword
+	err := r.db.WithContext(ctx).Where("password_hash = ?", hash).First(&password).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &password, err
+}
+
+// GetByUserID obtiene las contraseñas de aplicación de un usuario
+func (r *appPasswordRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.AppPassword, error) {
+	var passwords []domain.AppPassword
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&passwords).Error
+	return passwords, err
+}
+
+// TouchLastUsed registra el último uso de una contraseña de aplicación
+func (r *appPasswordRepository) TouchLastUsed(ctx context.Context, id uint, at int64) error {
+	return r.db.WithContext(ctx).Model(&domain.AppPassword{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// Delete revoca una contraseña de aplicación (soft delete)
+func (r *appPasswordRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.AppPassword{}, id).Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository define las operaciones de base de datos para los feeds de calendario
+type CalendarFeedRepository interface {
+	Create(ctx context.Context, feed *domain.CalendarFeed) error
+	GetByID(ctx context.Context, id uint) (*domain.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.CalendarFeed, error)
+	Update(ctx context.Context, feed *domain.CalendarFeed) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// calendarFeedRepository implementa CalendarFeedRepository
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository crea una nueva instancia de CalendarFeedRepository
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// Create guarda un nuevo feed en la base de datos
+func (r *calendarFeedRepository) Create(ctx context.Context, feed *domain.CalendarFeed) error {
+	return r.db.WithContext(ctx).Create(feed).Error
+}
+
+// GetByID obtiene un feed por su ID
+func (r *calendarFeedRepository) GetByID(ctx context.Context, id uint) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.WithContext(ctx).First(&feed, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &feed, err
+}
+
+// GetByTokenHash obtiene un feed por el hash de su token
+func (r *calendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &feed, err
+}
+
+// GetByUserID obtiene todos los feeds de un usuario
+func (r *calendarFeedRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.CalendarFeed, error) {
+	var feeds []domain.CalendarFeed
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&feeds).Error
+	return feeds, err
+}
+
+// Update actualiza un feed existente
+func (r *calendarFeedRepository) Update(ctx context.Context, feed *domain.CalendarFeed) error {
+	return r.db.WithContext(ctx).Save(feed).Error
+}
+
+// Delete elimina un feed por su ID (soft delete)
+func (r *calendarFeedRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.CalendarFeed{}, id).Error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/testutil"
+	"gorm.io/gorm"
+)
+
+// newTestDB crea una base SQLite temporal con el esquema de las migraciones
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return testutil.NewDB(t, nil)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// FilterRepository define las operaciones de base de datos para filtros guardados
+type FilterRepository interface {
+	Create(ctx context.Context, savedFilter *domain.SavedFilter) error
+	GetByID(ctx context.Context, id uint) (*domain.SavedFilter, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.SavedFilter, error)
+	Update(ctx context.Context, savedFilter *domain.SavedFilter) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// filterRepository implementa FilterRepository
+type filterRepository struct {
+	db *gorm.DB
+}
+
+// NewFilterRepository crea una nueva instancia de FilterRepository
+func NewFilterRepository(db *gorm.DB) FilterRepository {
+	return &filterRepository{db: db}
+}
+
+// Create guarda un nuevo filtro en la base de datos
+func (r *filterRepository) Create(ctx context.Context, savedFilter *domain.SavedFilter) error {
+	return r.db.WithContext(ctx).Create(savedFilter).Error
+}
+
+// GetByID obtiene un filtro guardado por su ID
+func (r *filterRepository) GetByID(ctx context.Context, id uint) (*domain.SavedFilter, error) {
+	var savedFilter domain.SavedFilter
+	err := r.db.WithContext(ctx).First(&savedFilter, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &savedFilter, err
+}
+
+// GetByUserID obtiene todos los filtros guardados de un usuario
+func (r *filterRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.SavedFilter, error) {
+	var savedFilters []domain.SavedFilter
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&savedFilters).Error
+	return savedFilters, err
+}
+
+// Update actualiza un filtro guardado existente
+func (r *filterRepository) Update(ctx context.Context, savedFilter *domain.SavedFilter) error {
+	return r.db.WithContext(ctx).Save(savedFilter).Error
+}
+
+// Delete elimina un filtro guardado por su ID (soft delete)
+func (r *filterRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.SavedFilter{}, id).Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository define las operaciones de base de datos para las claves de idempotencia
+type IdempotencyRepository interface {
+	Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error)
+	Get(ctx context.Context, userID uint, key string) (*domain.IdempotencyKey, error)
+	Renew(ctx context.Context, key *domain.IdempotencyKey, now int64) (bool, error)
+	Complete(ctx context.Context, userID uint, key string, statusCode int, headers, body string) error
+	Delete(ctx context.Context, userID uint, key string) error
+	DeleteExpired(ctx context.Context, now int64) (int64, error)
+}
+
+// idempotencyRepository implementa IdempotencyRepository
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository crea una nueva instancia de IdempotencyRepository
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Create reserva una clave de idempotencia. Devuelve false si el usuario ya la había usado;
+// el índice único garantiza que solo una de varias peticiones concurrentes la obtenga.
+func (r *idempotencyRepository) Create(ctx context.Context, key *domain.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Get obtiene una clave de idempotencia de un usuario
+func (r *idempotencyRepository) Get(ctx context.Context, userID uint, key string) (*domain.IdempotencyKey, error) {
+	var record domain.IdempotencyKey
+	err := r.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &record, err
+}
+
+// Renew reserva de nuevo una clave que ya expiró pero aún no se purgó, descartando su respuesta,
+// o una cuya petición original sigue en curso después de vencer su reserva (locked_until).
+// La condición hace que, entre varias peticiones concurrentes, solo una la obtenga: las demás
+// encuentran la reserva nueva, que ya no está vencida.
+func (r *idempotencyRepository) Renew(ctx context.Context, key *domain.IdempotencyKey, now int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).
+		Where("user_id = ? AND idempotency_key = ? AND (expires_at <= ? OR (status_code = 0 AND locked_until <= ?))",
+			key.UserID, key.Key, now, now).
+		Updates(map[string]interface{}{
+			"request_hash":     key.RequestHash,
+			"status_code":      0,
+			"response_headers": "",
+			"response_body":    "",
+			"created_at":       now,
+			"expires_at":       key.ExpiresAt,
+			"locked_until":     key.LockedUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Complete guarda la respuesta de la petición original
+func (r *idempotencyRepository) Complete(ctx context.Context, userID uint, key string, statusCode int, headers, body string) error {
+	return r.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Updates(map[string]interface{}{
+			"status_code":      statusCode,
+			"response_headers": headers,
+			"response_body":    body,
+		}).Error
+}
+
+// Delete libera una clave de idempotencia
+func (r *idempotencyRepository) Delete(ctx context.Context, userID uint, key string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userID, key).Delete(&domain.IdempotencyKey{}).Error
+}
+
+// DeleteExpired elimina las claves cuyo tiempo de vida terminó y devuelve cuántas se eliminaron
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now int64) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&domain.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRenew(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name        string
+		expiresAt   int64
+		lockedUntil int64
+		inProgress  bool
+		wantRenewed bool
+	}{
+		{"clave expirada", now - 10, now - 10, false, true},
+		{"clave que expira justo ahora", now, now - 10, false, true},
+		{"clave vigente", now + 60, now - 10, false, false},
+		{"petición en curso con la reserva vencida", now + 60, now - 10, true, true},
+		{"petición en curso con la reserva vigente", now + 60, now + 10, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewIdempotencyRepository(newTestDB(t))
+			created, err := repo.Create(ctx, &domain.IdempotencyKey{UserID: 1, Key: "k", RequestHash: "viejo", ExpiresAt: tt.expiresAt, LockedUntil: tt.lockedUntil})
+			require.NoError(t, err)
+			require.True(t, created)
+			if !tt.inProgress {
+				require.NoError(t, repo.Complete(ctx, 1, "k", 201, `{"Etag":["\"1\""]}`, `{"id":1}`))
+			}
+
+			renewed, err := repo.Renew(ctx, &domain.IdempotencyKey{UserID: 1, Key: "k", RequestHash: "nuevo", ExpiresAt: now + 3600, LockedUntil: now + 60}, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRenewed, renewed)
+
+			stored, err := repo.Get(ctx, 1, "k")
+			require.NoError(t, err)
+			if tt.wantRenewed {
+				assert.Equal(t, "nuevo", stored.RequestHash)
+				assert.False(t, stored.Completed())
+				assert.Empty(t, stored.ResponseBody)
+				assert.Empty(t, stored.ResponseHeaders)
+			} else {
+				assert.Equal(t, "viejo", stored.RequestHash)
+				assert.Equal(t, !tt.inProgress, stored.Completed())
+			}
+		})
+	}
+}
+
+// Varias peticiones que encuentran la misma clave expirada: solo una puede reservarla
+func TestIdempotencyRenewConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(newTestDB(t))
+	now := time.Now().Unix()
+	_, err := repo.Create(ctx, &domain.IdempotencyKey{UserID: 1, Key: "k", RequestHash: "h", ExpiresAt: now - 1})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var winners atomic.Int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			renewed, err := repo.Renew(ctx, &domain.IdempotencyKey{UserID: 1, Key: "k", RequestHash: "h", ExpiresAt: now + 3600, LockedUntil: now + 60}, now)
+			assert.NoError(t, err)
+			if renewed {
+				winners.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), winners.Load())
+}
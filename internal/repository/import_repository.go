@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ImportJobRepository define las operaciones de base de datos para los trabajos de importación
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *domain.ImportJob) error
+	GetByID(ctx context.Context, id uint) (*domain.ImportJob, error)
+	Update(ctx context.Context, job *domain.ImportJob) error
+	UpdateProgress(ctx context.Context, id uint, processed int) error
+}
+
+// importJobRepository implementa ImportJobRepository
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepository crea una nueva instancia de ImportJobRepository
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+// Create registra un nuevo trabajo de importación
+func (r *importJobRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID obtiene un trabajo de importación por su ID
+func (r *importJobRepository) GetByID(ctx context.Context, id uint) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	err := r.db.WithContext(ctx).First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &job, err
+}
+
+// Update guarda el estado de un trabajo de importación
+func (r *importJobRepository) Update(ctx context.Context, job *domain.ImportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// UpdateProgress actualiza la cantidad de tareas procesadas; también renueva updated_at,
+// que sirve para detectar trabajos interrumpidos
+func (r *importJobRepository) UpdateProgress(ctx context.Context, id uint, processed int) error {
+	return r.db.WithContext(ctx).Model(&domain.ImportJob{}).Where("id = ?", id).
+		Update("processed", processed).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"gorm.io/gorm"
+)
+
+// filterColumns asocia los campos de fecha del lenguaje de filtros con sus columnas
+var filterColumns = map[filter.Field]string{
+	filter.FieldCreated: "created_at",
+	filter.FieldUpdated: "updated_at",
+}
+
+// likeEscaper escapa los comodines de LIKE en el texto introducido por el usuario.
+// Se usa '!' como carácter de escape porque se interpreta igual en todos los motores.
+var likeEscaper = strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+
+// GetByFilter obtiene las tareas de un usuario que cumplen la expresión de filtro
+func (r *taskRepository) GetByFilter(ctx context.Context, userID uint, query *filter.Query) ([]domain.Task, error) {
+	var tasks []domain.Task
+	db := applyFilter(r.db.WithContext(ctx).Where("user_id = ?", userID), query)
+	err := db.Order("position, id").Find(&tasks).Error
+	return tasks, err
+}
+
+// StreamByFilter recorre las tareas de un usuario que cumplen la expresión, en el orden de la lista,
+// leyéndolas de la base de datos una a una. Si query es nulo recorre todas. Un error de fn detiene el recorrido.
+func (r *taskRepository) StreamByFilter(ctx context.Context, userID uint, query *filter.Query, fn func(task *domain.Task) error) error {
+	db := applyFilter(r.db.WithContext(ctx).Model(&domain.Task{}).Where("user_id = ?", userID), query)
+	rows, err := db.Order("position, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task domain.Task
+		if err := r.db.ScanRows(rows, &task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetFilterStats resume las tareas de un usuario que cumplen la expresión: cantidad, última
+// modificación y suma de versiones. Sirve para calcular un ETag sin leer las tareas.
+func (r *taskRepository) GetFilterStats(ctx context.Context, userID uint, query *filter.Query) (domain.TaskStats, error) {
+	var stats domain.TaskStats
+	db := applyFilter(r.db.WithContext(ctx).Model(&domain.Task{}).Where("user_id = ?", userID), query)
+	err := db.Select("COUNT(*) AS count, COALESCE(MAX(updated_at), 0) AS last_updated, COALESCE(SUM(version), 0) AS version_sum").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// applyFilter compila los términos de la expresión en condiciones parametrizadas de GORM
+func applyFilter(db *gorm.DB, query *filter.Query) *gorm.DB {
+	if query == nil {
+		return db
+	}
+
+	for _, term := range query.Terms {
+		sql, args := compileTerm(term)
+		if term.Negated {
+			sql = "NOT (" + sql + ")"
+		}
+		db = db.Where(sql, args...)
+	}
+	return db
+}
+
+// compileTerm convierte un término en una condición SQL con sus parámetros
+func compileTerm(term filter.Term) (string, []interface{}) {
+	switch term.Field {
+	case filter.FieldStatus:
+		return "completed = ?", []interface{}{term.Bool}
+
+	case filter.FieldCreated, filter.FieldUpdated:
+		column := filterColumns[term.Field]
+		if !term.Day {
+			return column + " " + string(term.Op) + " ?", []interface{}{term.Time.Unix()}
+		}
+		// Una fecha absoluta abarca el día completo, de medianoche a la medianoche siguiente
+		start, next := term.Time.Unix(), term.Time.AddDate(0, 0, 1).Unix()
+		switch term.Op {
+		case filter.OpLT:
+			return column + " < ?", []interface{}{start}
+		case filter.OpLTE:
+			return column + " < ?", []interface{}{next}
+		case filter.OpGT:
+			return column + " >= ?", []interface{}{next}
+		case filter.OpGTE:
+			return column + " >= ?", []interface{}{start}
+		default:
+			return column + " >= ? AND " + column + " < ?", []interface{}{start, next}
+		}
+
+	default:
+		// Texto libre, frases y title: coincidencia parcial en el título
+		return `LOWER(title) LIKE ? ESCAPE '!'`, []interface{}{"%" + likeEscaper.Replace(term.Text) + "%"}
+	}
+}
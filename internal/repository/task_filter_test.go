@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileTerm(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	start, next := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	instant := time.Date(2026, 10, 11, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		term     filter.Term
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{"status", filter.Term{Field: filter.FieldStatus, Op: filter.OpEq, Bool: true},
+			"completed = ?", []interface{}{true}},
+		{"texto con comodines", filter.Term{Field: filter.FieldText, Op: filter.OpEq, Text: "50%_!"},
+			`LOWER(title) LIKE ? ESCAPE '!'`, []interface{}{"%50!%!_!!%"}},
+		{"día con :", filter.Term{Field: filter.FieldCreated, Op: filter.OpEq, Time: day, Day: true},
+			"created_at >= ? AND created_at < ?", []interface{}{start, next}},
+		{"día con <", filter.Term{Field: filter.FieldCreated, Op: filter.OpLT, Time: day, Day: true},
+			"created_at < ?", []interface{}{start}},
+		{"día con <= incluye el día", filter.Term{Field: filter.FieldCreated, Op: filter.OpLTE, Time: day, Day: true},
+			"created_at < ?", []interface{}{next}},
+		{"día con > empieza al día siguiente", filter.Term{Field: filter.FieldUpdated, Op: filter.OpGT, Time: day, Day: true},
+			"updated_at >= ?", []interface{}{next}},
+		{"día con >=", filter.Term{Field: filter.FieldUpdated, Op: filter.OpGTE, Time: day, Day: true},
+			"updated_at >= ?", []interface{}{start}},
+		{"instante con >", filter.Term{Field: filter.FieldCreated, Op: filter.OpGT, Time: instant},
+			"created_at > ?", []interface{}{instant.Unix()}},
+		{"instante con <=", filter.Term{Field: filter.FieldUpdated, Op: filter.OpLTE, Time: instant},
+			"updated_at <= ?", []interface{}{instant.Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := compileTerm(tt.term)
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestGetByFilterDayBoundaries(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	for _, task := range []domain.Task{
+		{Title: "antes", CreatedAt: day.Add(-time.Second).Unix()},
+		{Title: "medianoche", CreatedAt: day.Unix()},
+		{Title: "tarde", CreatedAt: day.Add(23*time.Hour + 59*time.Minute).Unix()},
+		{Title: "después", CreatedAt: day.AddDate(0, 0, 1).Unix()},
+	} {
+		task.UserID = 1
+		require.NoError(t, db.Create(&task).Error)
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"created:2026-10-18", []string{"medianoche", "tarde"}},
+		{"created<2026-10-18", []string{"antes"}},
+		{"created<=2026-10-18", []string{"antes", "medianoche", "tarde"}},
+		{"created>2026-10-18", []string{"después"}},
+		{"created>=2026-10-18", []string{"medianoche", "tarde", "después"}},
+		{"-created:2026-10-18", []string{"antes", "después"}},
+	}
+	repo := NewTaskRepository(db)
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			query, err := filter.Parse(tt.expr, now)
+			require.NoError(t, err)
+			tasks, err := repo.GetByFilter(ctx, 1, query)
+			require.NoError(t, err)
+
+			titles := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			assert.ElementsMatch(t, tt.want, titles)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockList bloquea la lista de tareas del usuario hasta el final de la transacción actual.
+// Serializa los movimientos concurrentes bloqueando la fila del usuario (SELECT ... FOR UPDATE).
+func (r *taskRepository) LockList(ctx context.Context, userID uint) error {
+	var ids []uint
+	return r.db.WithContext(ctx).Model(&domain.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userID).
+		Pluck("id", &ids).Error
+}
+
+// GetLastPosition obtiene la mayor clave de posición de las tareas del usuario
+func (r *taskRepository) GetLastPosition(ctx context.Context, userID uint) (string, error) {
+	var positions []string
+	err := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("user_id = ?", userID).
+		Order("position DESC").
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+// GetAdjacentPosition obtiene la clave de la tarea inmediatamente posterior (next) o anterior
+// a la posición dada, sin considerar la tarea excludeID
+func (r *taskRepository) GetAdjacentPosition(ctx context.Context, userID uint, position string, excludeID uint, next bool) (string, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("user_id = ? AND id <> ?", userID, excludeID)
+	if next {
+		query = query.Where("position > ?", position).Order("position ASC")
+	} else {
+		query = query.Where("position < ? AND position <> ''", position).Order("position DESC")
+	}
+
+	var positions []string
+	if err := query.Limit(1).Pluck("position", &positions).Error; err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+// UpdatePosition actualiza la clave de posición de una tarea
+func (r *taskRepository) UpdatePosition(ctx context.Context, id uint, position string) error {
+	return r.db.WithContext(ctx).Model(&domain.Task{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"position": position, "version": gorm.Expr("version + 1")}).Error
+}
+
+// GetUsersToRebalance obtiene los usuarios con tareas sin posición o con claves demasiado largas
+func (r *taskRepository) GetUsersToRebalance(ctx context.Context, maxLength int) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("position = '' OR LENGTH(position) > ?", maxLength).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
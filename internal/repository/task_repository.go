@@ -3,12 +3,17 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
-	"github.com/alexroel/gin-tasks-api/internal/config"
 	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict indica que el registro fue modificado por otra operación
+// después de leerse (control de concurrencia optimista)
+var ErrVersionConflict = errors.New("el registro fue modificado por otra operación")
+
 // TaskRepository define las operaciones de base de datos para tareas
 type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
@@ -16,18 +21,43 @@ type TaskRepository interface {
 	GetByID(ctx context.Context, id uint) (*domain.Task, error)
 	GetByUserID(ctx context.Context, userID uint) ([]domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
-	Delete(ctx context.Context, id uint) error
-	UpdateStatus(ctx context.Context, id uint, completed bool) error
+	Delete(ctx context.Context, id, version uint) error
+	UpdateStatus(ctx context.Context, id uint, completed bool, version uint) error
+	GetDeletedByUserID(ctx context.Context, userID uint) ([]domain.Task, error)
+	GetDeletedByID(ctx context.Context, id uint) (*domain.Task, error)
+	Restore(ctx context.Context, id uint) error
+	HardDelete(ctx context.Context, id uint) error
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	Search(ctx context.Context, userID uint, terms []string, lang string, limit int) ([]domain.TaskSearchResult, error)
+	GetByFilter(ctx context.Context, userID uint, query *filter.Query) ([]domain.Task, error)
+	GetFilterStats(ctx context.Context, userID uint, query *filter.Query) (domain.TaskStats, error)
+	StreamByFilter(ctx context.Context, userID uint, query *filter.Query, fn func(task *domain.Task) error) error
+	GetOwnedIDs(ctx context.Context, userID uint, ids []uint) ([]uint, error)
+	GetByIDs(ctx context.Context, userID uint, ids []uint) ([]domain.Task, error)
+	CreateBatch(ctx context.Context, tasks []domain.Task) error
+	UpdateStatusBatch(ctx context.Context, ids []uint, completed bool) error
+	DeleteBatch(ctx context.Context, ids []uint) error
+	Transaction(ctx context.Context, fn func(repo TaskRepository) error) error
+	Activities() ActivityRepository
+	LockList(ctx context.Context, userID uint) error
+	GetLastPosition(ctx context.Context, userID uint) (string, error)
+	GetAdjacentPosition(ctx context.Context, userID uint, position string, excludeID uint, next bool) (string, error)
+	UpdatePosition(ctx context.Context, id uint, position string) error
+	GetUsersToRebalance(ctx context.Context, maxLength int) ([]uint, error)
+	GetChanges(ctx context.Context, userID uint, updatedAt int64, afterID uint, limit int) ([]domain.Task, error)
+	GetByClientID(ctx context.Context, userID uint, clientID string) (*domain.Task, error)
+	GetLastChange(ctx context.Context, userID uint) (*domain.Task, error)
 }
 
 // taskRepository implementa TaskRepository
 type taskRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	fullTextSearch bool
 }
 
 // NewTaskRepository crea una nueva instancia de TaskRepository
-func NewTaskRepository() TaskRepository {
-	return &taskRepository{db: config.DB}
+func NewTaskRepository(db *gorm.DB) TaskRepository {
+	return &taskRepository{db: db, fullTextSearch: supportsFullTextSearch(db)}
 }
 
 // Create crea una nueva tarea en la base de datos
@@ -52,24 +82,189 @@ func (r *taskRepository) GetByID(ctx context.Context, id uint) (*domain.Task, er
 	return &task, err
 }
 
-// GetByUserID obtiene todas las tareas de un usuario
+// GetByUserID obtiene todas las tareas de un usuario en su orden manual
 func (r *taskRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.Task, error) {
 	var tasks []domain.Task
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&tasks).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("position, id").Find(&tasks).Error
 	return tasks, err
 }
 
-// Update actualiza una tarea existente
+// Update actualiza una tarea existente si su versión no cambió desde que se leyó.
+// Incrementa la versión; si otra operación la modificó antes devuelve ErrVersionConflict.
 func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
-	return r.db.WithContext(ctx).Save(task).Error
+	expected := task.Version
+	task.Version++
+
+	result := r.db.WithContext(ctx).Model(task).
+		Where("version = ?", expected).
+		Select("*").Omit("id", "created_at", "User").
+		Updates(task)
+	if result.Error != nil {
+		task.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		task.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Delete elimina una tarea por su ID (soft delete) si su versión no cambió desde que se leyó.
+// También actualiza updated_at y la versión para que la eliminación aparezca en la sincronización.
+func (r *taskRepository) Delete(ctx context.Context, id, version uint) error {
+	result := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(softDeleteColumns())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// UpdateStatus actualiza el estado de completado de una tarea si su versión no cambió desde que se leyó
+func (r *taskRepository) UpdateStatus(ctx context.Context, id uint, completed bool, version uint) error {
+	result := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]interface{}{
+			"completed": completed,
+			"version":   gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// GetDeletedByUserID obtiene las tareas eliminadas (en papelera) de un usuario
+func (r *taskRepository) GetDeletedByUserID(ctx context.Context, userID uint) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// GetDeletedByID obtiene una tarea eliminada por su ID
+func (r *taskRepository) GetDeletedByID(ctx context.Context, id uint) (*domain.Task, error) {
+	var task domain.Task
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&task, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &task, err
+}
+
+// Restore recupera una tarea eliminada
+func (r *taskRepository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&domain.Task{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error
+}
+
+// HardDelete elimina definitivamente una tarea de la base de datos
+func (r *taskRepository) HardDelete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&domain.Task{}, id).Error
+}
+
+// PurgeDeletedBefore elimina definitivamente las tareas que están en la papelera desde antes de la fecha dada
+func (r *taskRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&domain.Task{})
+	return result.RowsAffected, result.Error
+}
+
+// GetOwnedIDs devuelve, de la lista dada, los IDs de tareas que pertenecen al usuario
+func (r *taskRepository) GetOwnedIDs(ctx context.Context, userID uint, ids []uint) ([]uint, error) {
+	var owned []uint
+	err := r.db.WithContext(ctx).Model(&domain.Task{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Pluck("id", &owned).Error
+	return owned, err
+}
+
+// GetByIDs obtiene en una sola consulta las tareas del usuario con los IDs dados.
+// Los IDs que no existen o pertenecen a otro usuario se omiten.
+func (r *taskRepository) GetByIDs(ctx context.Context, userID uint, ids []uint) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := r.db.WithContext(ctx).Where("user_id = ? AND id IN ?", userID, ids).Find(&tasks).Error
+	return tasks, err
+}
+
+// CreateBatch crea varias tareas en una sola sentencia
+func (r *taskRepository) CreateBatch(ctx context.Context, tasks []domain.Task) error {
+	return r.db.WithContext(ctx).Create(&tasks).Error
+}
+
+// UpdateStatusBatch actualiza el estado de completado de varias tareas
+func (r *taskRepository) UpdateStatusBatch(ctx context.Context, ids []uint, completed bool) error {
+	return r.db.WithContext(ctx).Model(&domain.Task{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"completed": completed, "version": gorm.Expr("version + 1")}).Error
+}
+
+// DeleteBatch elimina varias tareas (soft delete)
+func (r *taskRepository) DeleteBatch(ctx context.Context, ids []uint) error {
+	return r.db.WithContext(ctx).Model(&domain.Task{}).Where("id IN ?", ids).
+		Updates(softDeleteColumns()).Error
+}
+
+// softDeleteColumns devuelve las columnas que marca un soft delete; updated_at lo agrega GORM
+func softDeleteColumns() map[string]interface{} {
+	return map[string]interface{}{"deleted_at": time.Now(), "version": gorm.Expr("version + 1")}
+}
+
+// GetChanges obtiene las tareas de un usuario, incluidas las eliminadas, modificadas después
+// de la posición (updatedAt, afterID), ordenadas por updated_at e ID
+func (r *taskRepository) GetChanges(ctx context.Context, userID uint, updatedAt int64, afterID uint, limit int) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND (updated_at > ? OR (updated_at = ? AND id > ?))", userID, updatedAt, updatedAt, afterID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// GetByClientID obtiene una tarea, incluso eliminada, por el ID que le asignó el cliente al crearla
+func (r *taskRepository) GetByClientID(ctx context.Context, userID uint, clientID string) (*domain.Task, error) {
+	var task domain.Task
+	err := r.db.WithContext(ctx).Unscoped().Where("user_id = ? AND client_id = ?", userID, clientID).First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &task, err
+}
+
+// GetLastChange obtiene la última tarea del usuario creada, modificada o eliminada
+func (r *taskRepository) GetLastChange(ctx context.Context, userID uint) (*domain.Task, error) {
+	var task domain.Task
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").
+		First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &task, err
 }
 
-// Delete elimina una tarea por su ID (soft delete)
-func (r *taskRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.Task{}, id).Error
+// Transaction ejecuta fn dentro de una transacción con un repositorio ligado a ella.
+// Si fn devuelve un error se revierten todos los cambios. Las transacciones anidadas usan savepoints.
+func (r *taskRepository) Transaction(ctx context.Context, fn func(repo TaskRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&taskRepository{db: tx, fullTextSearch: r.fullTextSearch})
+	})
 }
 
-// UpdateStatus actualiza el estado de completado de una tarea
-func (r *taskRepository) UpdateStatus(ctx context.Context, id uint, completed bool) error {
-	return r.db.WithContext(ctx).Model(&domain.Task{}).Where("id = ?", id).Update("completed", completed).Error
+// Activities devuelve el repositorio del historial sobre la misma conexión. Dentro de Transaction
+// permite guardar la actividad en la misma transacción que el cambio de la tarea.
+func (r *taskRepository) Activities() ActivityRepository {
+	return &activityRepository{db: r.db}
 }
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchConfigs asocia cada idioma soportado con su configuración de texto de PostgreSQL
+var searchConfigs = map[string][]string{
+	"":                       {"spanish", "english"},
+	domain.SearchLangSpanish: {"spanish"},
+	domain.SearchLangEnglish: {"english"},
+}
+
+// Delimitadores que ts_headline inserta alrededor de cada coincidencia. Son caracteres de control
+// que se eliminan del título antes de resaltarlo, para poder escapar el HTML del título y después
+// sustituirlos por las etiquetas <mark>.
+const (
+	searchMarkStart = "\x02"
+	searchMarkStop  = "\x03"
+)
+
+// searchMarks sustituye los delimitadores de ts_headline por las etiquetas <mark>
+var searchMarks = strings.NewReplacer(searchMarkStart, "<mark>", searchMarkStop, "</mark>")
+
+// searchRow representa una fila del resultado de la búsqueda de texto completo
+type searchRow struct {
+	ID      uint
+	Rank    float64
+	Snippet string
+}
+
+// supportsFullTextSearch indica si la base de datos tiene la columna tsvector de búsqueda
+func supportsFullTextSearch(db *gorm.DB) bool {
+	if db == nil || db.Dialector.Name() != "postgres" {
+		return false
+	}
+	return db.Migrator().HasColumn(&domain.Task{}, "search_vector")
+}
+
+// Search busca tareas del usuario que coincidan con todos los términos (por prefijo).
+// Usa tsvector en PostgreSQL y, si no está disponible, una búsqueda por coincidencia parcial.
+func (r *taskRepository) Search(ctx context.Context, userID uint, terms []string, lang string, limit int) ([]domain.TaskSearchResult, error) {
+	if !r.fullTextSearch {
+		return r.searchFallback(ctx, userID, terms, limit)
+	}
+
+	configs, ok := searchConfigs[lang]
+	if !ok {
+		configs = searchConfigs[""]
+	}
+
+	// Cada término se busca por prefijo: "tarea:* & urgente:*"
+	prefixed := make([]string, len(terms))
+	for i, term := range terms {
+		prefixed[i] = term + ":*"
+	}
+
+	// Las configuraciones provienen de searchConfigs, nunca de la entrada del usuario
+	queries := make([]string, len(configs))
+	for i, cfg := range configs {
+		queries[i] = "to_tsquery('" + cfg + "', @query)"
+	}
+
+	sql := `SELECT id,
+			ts_rank(search_vector, q.tsq) AS rank,
+			ts_headline('` + configs[0] + `', translate(title, chr(2) || chr(3), ''), q.tsq,
+				'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', HighlightAll=true') AS snippet
+		FROM tasks, (SELECT ` + strings.Join(queries, " || ") + ` AS tsq) AS q
+		WHERE user_id = @user AND deleted_at IS NULL AND search_vector @@ q.tsq
+		ORDER BY rank DESC, id DESC
+		LIMIT @limit`
+
+	var rows []searchRow
+	err := r.db.WithContext(ctx).Raw(sql, map[string]interface{}{
+		"query": strings.Join(prefixed, " & "),
+		"user":  userID,
+		"limit": limit,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []domain.TaskSearchResult{}, nil
+	}
+
+	// Obtener las tareas completas conservando el orden por relevancia
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var tasks []domain.Task
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]domain.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	results := make([]domain.TaskSearchResult, 0, len(rows))
+	for _, row := range rows {
+		task, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		snippet := searchMarks.Replace(html.EscapeString(row.Snippet))
+		results = append(results, domain.TaskSearchResult{Task: task, Rank: row.Rank, Snippet: snippet})
+	}
+	return results, nil
+}
+
+// searchFallback busca tareas por coincidencia parcial para bases de datos sin tsvector.
+// La relevancia es la proporción de palabras del título que coinciden con algún término.
+// Como no se puede calcular en SQL, la base de datos aplica el límite con una aproximación
+// (títulos con una palabra que empieza por el primer término y, después, los más cortos)
+// y la relevancia solo reordena esas tareas.
+//
+// Los términos llegan en minúsculas y se comparan con LOWER(title). En SQLite, LOWER solo
+// convierte las letras ASCII: un término como "ñandú" no encuentra el título "Ñandú".
+// MySQL convierte todas las letras según la intercalación de la columna.
+func (r *taskRepository) searchFallback(ctx context.Context, userID uint, terms []string, limit int) ([]domain.TaskSearchResult, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	for _, term := range terms {
+		query = query.Where(`LOWER(title) LIKE ? ESCAPE '!'`, "%"+likeEscaper.Replace(term)+"%")
+	}
+
+	first := likeEscaper.Replace(terms[0])
+	var tasks []domain.Task
+	err := query.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:  `CASE WHEN LOWER(title) LIKE ? ESCAPE '!' OR LOWER(title) LIKE ? ESCAPE '!' THEN 0 ELSE 1 END, LENGTH(title), id DESC`,
+		Vars: []interface{}{first + "%", "% " + first + "%"},
+	}}).Limit(limit).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	matcher := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+
+	results := make([]domain.TaskSearchResult, 0, len(tasks))
+	for _, task := range tasks {
+		words := strings.Fields(task.Title)
+		matched := 0
+		for _, word := range words {
+			if matcher.MatchString(word) {
+				matched++
+			}
+		}
+		var rank float64
+		if len(words) > 0 {
+			rank = float64(matched) / float64(len(words))
+		}
+		results = append(results, domain.TaskSearchResult{
+			Task:    task,
+			Rank:    rank,
+			Snippet: highlight(task.Title, matcher),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Task.ID > results[j].Task.ID
+	})
+	return results, nil
+}
+
+// highlight escapa el HTML del título y envuelve en <mark> las coincidencias del matcher
+func highlight(title string, matcher *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range matcher.FindAllStringIndex(title, -1) {
+		b.WriteString(html.EscapeString(title[last:match[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(title[match[0]:match[1]]))
+		b.WriteString("</mark>")
+		last = match[1]
+	}
+	b.WriteString(html.EscapeString(title[last:]))
+	return b.String()
+}
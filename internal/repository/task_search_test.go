@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestHighlight(t *testing.T) {
+	matcher := regexp.MustCompile(`(?i)informe|amp`)
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Informe mensual", "<mark>Informe</mark> mensual"},
+		{"Sin coincidencias", "Sin coincidencias"},
+		{`<script>alert("informe")</script>`, `&lt;script&gt;alert(&#34;<mark>informe</mark>&#34;)&lt;/script&gt;`},
+		// Las coincidencias se buscan en el título original, no en las entidades escapadas
+		{"R&D informe", "R&amp;D <mark>informe</mark>"},
+		{"<img src=x onerror=alert(1)> informe", "&lt;img src=x onerror=alert(1)&gt; <mark>informe</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlight(tt.title, matcher))
+		})
+	}
+}
+
+func TestSearchMarks(t *testing.T) {
+	// Fragmento tal como lo devuelve ts_headline con los delimitadores de control
+	snippet := "<b>" + searchMarkStart + "informe" + searchMarkStop + "</b> & más"
+	assert.Equal(t, "&lt;b&gt;<mark>informe</mark>&lt;/b&gt; &amp; más", searchMarks.Replace(html.EscapeString(snippet)))
+}
+
+func TestSearchFallbackEscapesSnippet(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+	require.NoError(t, db.Create(&domain.Task{Title: `<img src=x onerror=alert(1)> informe`, UserID: 1}).Error)
+
+	results, err := NewTaskRepository(db).Search(ctx, 1, []string{"informe"}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <mark>informe</mark>", results[0].Snippet)
+}
+
+func TestSearchFallbackLimit(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+	for _, title := range []string{"revisar el informe anual de ventas", "preinforme", "informe mensual", "informe", "otra tarea"} {
+		require.NoError(t, db.Create(&domain.Task{Title: title, UserID: 1}).Error)
+	}
+
+	// El límite se aplica en la consulta: no se leen todas las coincidencias
+	var queries []string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	}))
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{1, []string{"informe"}},
+		{2, []string{"informe", "informe mensual"}},
+		{3, []string{"informe", "informe mensual", "revisar el informe anual de ventas"}},
+		{10, []string{"informe", "preinforme", "informe mensual", "revisar el informe anual de ventas"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.limit), func(t *testing.T) {
+			queries = nil
+			results, err := NewTaskRepository(db).Search(ctx, 1, []string{"informe"}, "", tt.limit)
+			require.NoError(t, err)
+
+			titles := make([]string, len(results))
+			for i, result := range results {
+				titles[i] = result.Task.Title
+			}
+			assert.Equal(t, tt.want, titles)
+			require.Len(t, queries, 1)
+			assert.Contains(t, queries[0], "LIMIT")
+		})
+	}
+}
+
+// Los comodines de LIKE en los términos se buscan como texto, también al ordenar
+func TestSearchFallbackEscapesWildcards(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+	for _, title := range []string{"100% listo", "1000 listo", "a_b", "axb", "xb_b", "_b más largo", "¡oferta!", "oferta"} {
+		require.NoError(t, db.Create(&domain.Task{Title: title, UserID: 1}).Error)
+	}
+
+	tests := []struct {
+		terms []string
+		limit int
+		want  []string
+	}{
+		{[]string{"100%"}, 10, []string{"100% listo"}},
+		{[]string{"a_b"}, 10, []string{"a_b"}},
+		{[]string{"%"}, 10, []string{"100% listo"}},
+		{[]string{"oferta!"}, 10, []string{"¡oferta!"}},
+		// "_b%" sin escapar también coincidiría al principio de "xb_b", el título más corto
+		{[]string{"_b"}, 1, []string{"_b más largo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.terms[0], func(t *testing.T) {
+			results, err := NewTaskRepository(db).Search(ctx, 1, tt.terms, "", tt.limit)
+			require.NoError(t, err)
+
+			titles := make([]string, len(results))
+			for i, result := range results {
+				titles[i] = result.Task.Title
+			}
+			assert.Equal(t, tt.want, titles)
+		})
+	}
+}
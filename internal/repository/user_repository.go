@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/alexroel/gin-tasks-api/internal/config"
 	"github.com/alexroel/gin-tasks-api/internal/domain"
 	"gorm.io/gorm"
 )
@@ -16,7 +15,7 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uint) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
-	Delete(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id, version uint) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 }
 
@@ -26,8 +25,8 @@ type userRepository struct {
 }
 
 // NewUserRepository crea una nueva instancia de UserRepository
-func NewUserRepository() UserRepository {
-	return &userRepository{db: config.DB}
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
 }
 
 // Create crea un nuevo usuario en la base de datos
@@ -62,14 +61,37 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return &user, err
 }
 
-// Update actualiza un usuario existente
+// Update actualiza un usuario existente si su versión no cambió desde que se leyó.
+// Incrementa la versión; si otra operación lo modificó antes devuelve ErrVersionConflict.
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	expected := user.Version
+	user.Version++
+
+	result := r.db.WithContext(ctx).Model(user).
+		Where("version = ?", expected).
+		Select("*").Omit("id", "created_at", "Tasks").
+		Updates(user)
+	if result.Error != nil {
+		user.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		user.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
 }
 
-// Delete elimina un usuario por su ID (soft delete)
-func (r *userRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
+// Delete elimina un usuario por su ID (soft delete) si su versión no cambió desde que se leyó
+func (r *userRepository) Delete(ctx context.Context, id, version uint) error {
+	result := r.db.WithContext(ctx).Where("version = ?", version).Delete(&domain.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 // ExistsByEmail verifica si existe un usuario con el email dado
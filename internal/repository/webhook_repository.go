@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository define las operaciones de base de datos para webhooks y sus entregas
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetByID(ctx context.Context, id uint) (*domain.Webhook, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error)
+	GetActiveByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error)
+	Update(ctx context.Context, webhook *domain.Webhook, activeChanged bool) error
+	Delete(ctx context.Context, id uint) error
+	RecordSuccess(ctx context.Context, id uint) error
+	RecordFailure(ctx context.Context, id uint, disableAfter int, now int64) error
+
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]domain.WebhookDelivery, int64, error)
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil int64, limit int) ([]domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// webhookRepository implementa WebhookRepository
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository crea una nueva instancia de WebhookRepository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create guarda un nuevo webhook en la base de datos
+func (r *webhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+// GetByID obtiene un webhook por su ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uint) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.db.WithContext(ctx).First(&webhook, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &webhook, err
+}
+
+// GetByUserID obtiene todos los webhooks de un usuario
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetActiveByUserID obtiene los webhooks activos de un usuario
+func (r *webhookRepository) GetActiveByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := r.db.WithContext(ctx).Where("user_id = ? AND active = ?", userID, true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// Update guarda los campos que el usuario puede editar. El estado solo se escribe si activeChanged,
+// para no pisar una desactivación automática simultánea; al reactivar se reinicia el contador de fallos.
+// El resto de columnas las mantienen RecordSuccess y RecordFailure.
+func (r *webhookRepository) Update(ctx context.Context, webhook *domain.Webhook, activeChanged bool) error {
+	columns := []string{"url", "events", "updated_at"}
+	if activeChanged {
+		columns = append(columns, "active")
+		if webhook.Active {
+			webhook.FailureCount = 0
+			webhook.DisabledAt = 0
+			columns = append(columns, "failure_count", "disabled_at")
+		}
+	}
+	return r.db.WithContext(ctx).Model(webhook).Select(columns).Updates(webhook).Error
+}
+
+// Delete elimina un webhook por su ID (soft delete)
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Webhook{}, id).Error
+}
+
+// RecordSuccess reinicia el contador de fallos consecutivos de un webhook
+func (r *webhookRepository) RecordSuccess(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&domain.Webhook{}).
+		Where("id = ? AND failure_count <> 0", id).
+		Update("failure_count", 0).Error
+}
+
+// RecordFailure incrementa el contador de fallos consecutivos y desactiva el webhook
+// al alcanzar disableAfter. Se hace en una sola sentencia para que sea seguro entre réplicas.
+func (r *webhookRepository) RecordFailure(ctx context.Context, id uint, disableAfter int, now int64) error {
+	return r.db.WithContext(ctx).Model(&domain.Webhook{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"failure_count": gorm.Expr("failure_count + 1"),
+			"active":        gorm.Expr("CASE WHEN failure_count + 1 >= ? THEN ? ELSE active END", disableAfter, false),
+			"disabled_at":   gorm.Expr("CASE WHEN failure_count + 1 >= ? AND disabled_at = 0 THEN ? ELSE disabled_at END", disableAfter, now),
+		}).Error
+}
+
+// CreateDeliveries encola nuevas entregas
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+// GetDeliveryByID obtiene una entrega por su ID
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := r.db.WithContext(ctx).First(&delivery, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &delivery, err
+}
+
+// GetDeliveries obtiene el historial de entregas de un webhook, de la más reciente a la más antigua
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]domain.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err := query.Session(&gorm.Session{}).Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// ClaimDueDeliveries reserva las entregas pendientes cuyo próximo intento ya venció,
+// posponiendo su siguiente intento hasta leaseUntil. Con SKIP LOCKED varias réplicas
+// pueden procesar la cola a la vez sin entregar dos veces el mismo evento.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil int64, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.DeliveryStatusPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&domain.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	return deliveries, err
+}
+
+// UpdateDelivery guarda el resultado de un intento de entrega
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
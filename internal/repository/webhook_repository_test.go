@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Update parte de una copia leída antes de que el repartidor registre fallos, como ocurre
+// cuando una petición del usuario coincide con una entrega fallida
+func TestWebhookUpdateKeepsDeliveryState(t *testing.T) {
+	ctx := context.Background()
+	const disableAfter = 3
+
+	tests := []struct {
+		name          string
+		failures      int
+		active        bool
+		activeChanged bool
+		wantActive    bool
+		wantFailures  int
+		wantDisabled  bool
+	}{
+		{"editar la URL conserva los fallos", 2, true, false, true, 2, false},
+		{"editar la URL no reactiva un webhook desactivado", 3, true, false, false, 3, true},
+		{"desactivar conserva los fallos", 2, false, true, false, 2, false},
+		{"reactivar reinicia los fallos", 3, true, true, true, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+			repo := NewWebhookRepository(db)
+			require.NoError(t, repo.Create(ctx, &domain.Webhook{URL: "https://a.example.com", Secret: "s", Events: "task.created", Active: true, UserID: 1}))
+
+			stale, err := repo.GetByID(ctx, 1)
+			require.NoError(t, err)
+			for i := 0; i < tt.failures; i++ {
+				require.NoError(t, repo.RecordFailure(ctx, 1, disableAfter, 1000))
+			}
+
+			stale.URL = "https://b.example.com"
+			stale.Active = tt.active
+			require.NoError(t, repo.Update(ctx, stale, tt.activeChanged))
+
+			stored, err := repo.GetByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "https://b.example.com", stored.URL)
+			assert.Equal(t, tt.wantActive, stored.Active)
+			assert.Equal(t, tt.wantFailures, stored.FailureCount)
+			assert.Equal(t, tt.wantDisabled, stored.DisabledAt != 0)
+			assert.Equal(t, "s", stored.Secret)
+		})
+	}
+}
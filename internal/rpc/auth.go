@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alexroel/gin-tasks-api/pkg/jwt"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods son las llamadas que no requieren token
+var publicMethods = map[string]bool{
+	tasksv1.AuthService_Register_FullMethodName: true,
+	tasksv1.AuthService_Login_FullMethodName:    true,
+}
+
+type userIDKey struct{}
+
+// AuthInterceptor valida el token JWT del metadato "authorization: Bearer <token>",
+// con las mismas reglas que el middleware de autenticación de la API REST.
+func AuthInterceptor(jwtSecret string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		// Obtener el token del metadato authorization
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "Token no proporcionado")
+		}
+
+		// Verificar formato del token "Bearer <token>"
+		parts := strings.Split(values[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "Formato de token inválido")
+		}
+
+		// Validar el token
+		claims, err := jwt.ValidateToken(parts[1], jwtSecret)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "Token inválido: "+err.Error())
+		}
+
+		return handler(context.WithValue(ctx, userIDKey{}, claims.UserID), req)
+	}
+}
+
+// authenticatedUser obtiene el ID del usuario autenticado por el interceptor
+func authenticatedUser(ctx context.Context) (uint, error) {
+	id, ok := ctx.Value(userIDKey{}).(uint)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "Usuario no autenticado")
+	}
+	return id, nil
+}
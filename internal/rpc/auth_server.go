@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authServer implementa AuthService sobre AuthServiceInterface
+type authServer struct {
+	tasksv1.UnimplementedAuthServiceServer
+	auth           service.AuthServiceInterface
+	requireVersion bool
+}
+
+// Register registra un nuevo usuario
+func (s *authServer) Register(ctx context.Context, in *tasksv1.RegisterRequest) (*tasksv1.RegisterResponse, error) {
+	req := domain.UserCreate{FullName: in.FullName, Email: in.Email, Password: in.Password}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.Register(ctx, &req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.RegisterResponse{User: toUser(user)}, nil
+}
+
+// Login autentica al usuario y devuelve un token JWT
+func (s *authServer) Login(ctx context.Context, in *tasksv1.LoginRequest) (*tasksv1.LoginResponse, error) {
+	req := domain.UserLogin{Email: in.Email, Password: in.Password}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	token, user, err := s.auth.Login(ctx, &req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.LoginResponse{Token: token, User: toUser(user)}, nil
+}
+
+// RefreshToken emite un nuevo token JWT para el usuario autenticado
+func (s *authServer) RefreshToken(ctx context.Context, _ *tasksv1.RefreshTokenRequest) (*tasksv1.RefreshTokenResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, user, err := s.auth.RefreshToken(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.RefreshTokenResponse{Token: token, User: toUser(user)}, nil
+}
+
+// GetProfile obtiene el perfil del usuario autenticado
+func (s *authServer) GetProfile(ctx context.Context, _ *tasksv1.GetProfileRequest) (*tasksv1.GetProfileResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "Usuario no encontrado")
+	}
+	return &tasksv1.GetProfileResponse{User: toUser(user)}, nil
+}
+
+// UpdateProfile actualiza los campos indicados del perfil
+func (s *authServer) UpdateProfile(ctx context.Context, in *tasksv1.UpdateProfileRequest) (*tasksv1.UpdateProfileResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+	req := domain.UserUpdate{FullName: in.FullName, Email: in.Email, Password: in.Password}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.UpdateProfile(ctx, userID, &req, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.UpdateProfileResponse{User: toUser(user)}, nil
+}
+
+// PatchProfile aplica un JSON Merge Patch o un JSON Patch al perfil
+func (s *authServer) PatchProfile(ctx context.Context, in *tasksv1.PatchProfileRequest) (*tasksv1.PatchProfileResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.PatchProfile(ctx, userID, in.ContentType, in.Patch, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.PatchProfileResponse{User: toUser(user)}, nil
+}
+
+// DeleteAccount elimina la cuenta del usuario autenticado
+func (s *authServer) DeleteAccount(ctx context.Context, in *tasksv1.DeleteAccountRequest) (*tasksv1.DeleteAccountResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.DeleteAccount(ctx, userID, version); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.DeleteAccountResponse{}, nil
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/jwt"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testSecret = "secreto-de-pruebas-de-grpc"
+
+// fakeAuth implementa Login y GetUserByID; las demás operaciones no se usan
+type fakeAuth struct {
+	service.AuthServiceInterface
+	user *domain.User
+}
+
+func (f *fakeAuth) Login(ctx context.Context, req *domain.UserLogin) (string, *domain.User, error) {
+	return "token", f.user, nil
+}
+
+func (f *fakeAuth) GetUserByID(ctx context.Context, userID uint) (*domain.User, error) {
+	if userID != f.user.ID {
+		return nil, service.ErrUserNotFound
+	}
+	return f.user, nil
+}
+
+// newTestClient levanta el servidor gRPC sobre una conexión en memoria
+func newTestClient(t *testing.T) tasksv1.AuthServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(&fakeAuth{user: &domain.User{ID: 7, FullName: "Ana", Email: "ana@example.com"}}, nil, testSecret, false)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return tasksv1.NewAuthServiceClient(conn)
+}
+
+func token(t *testing.T, userID uint, secret string, expiresIn time.Duration) string {
+	t.Helper()
+	token, err := jwt.GenerateToken(userID, "ana@example.com", secret, expiresIn)
+	require.NoError(t, err)
+	return token
+}
+
+// Register y Login no requieren token
+func TestAuthInterceptorPublicMethods(t *testing.T) {
+	client := newTestClient(t)
+
+	resp, err := client.Login(context.Background(), &tasksv1.LoginRequest{Email: "ana@example.com", Password: "secreto123"})
+	require.NoError(t, err)
+	assert.Equal(t, "token", resp.Token)
+
+	// Un token inválido en una llamada pública se ignora
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalido")
+	_, err = client.Login(ctx, &tasksv1.LoginRequest{Email: "ana@example.com", Password: "secreto123"})
+	assert.NoError(t, err)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name        string
+		metadata    []string
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{"sin metadatos", nil, codes.Unauthenticated, "Token no proporcionado"},
+		{"authorization vacío", []string{"authorization", ""}, codes.Unauthenticated, "Formato de token inválido"},
+		{"sin esquema", []string{"authorization", token(t, 7, testSecret, time.Hour)}, codes.Unauthenticated, "Formato de token inválido"},
+		{"esquema Basic", []string{"authorization", "Basic YW5hOnNlY3JldG8="}, codes.Unauthenticated, "Formato de token inválido"},
+		{"esquema en minúsculas", []string{"authorization", "bearer " + token(t, 7, testSecret, time.Hour)}, codes.Unauthenticated, "Formato de token inválido"},
+		{"partes de más", []string{"authorization", "Bearer a b"}, codes.Unauthenticated, "Formato de token inválido"},
+		{"token mal formado", []string{"authorization", "Bearer abc"}, codes.Unauthenticated, "Token inválido"},
+		{"otra clave", []string{"authorization", "Bearer " + token(t, 7, "otro-secreto", time.Hour)}, codes.Unauthenticated, "Token inválido"},
+		{"token expirado", []string{"authorization", "Bearer " + token(t, 7, testSecret, -time.Minute)}, codes.Unauthenticated, "Token inválido"},
+		{"token válido", []string{"authorization", "Bearer " + token(t, 7, testSecret, time.Hour)}, codes.OK, ""},
+		// El usuario del token es el que llega al servicio
+		{"usuario del token", []string{"authorization", "Bearer " + token(t, 8, testSecret, time.Hour)}, codes.NotFound, "usuario no encontrado"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.AppendToOutgoingContext(ctx, tt.metadata...)
+			}
+			resp, err := client.GetProfile(ctx, &tasksv1.GetProfileRequest{})
+
+			st := status.Convert(err)
+			assert.Equal(t, tt.wantCode, st.Code(), st.Message())
+			assert.Contains(t, st.Message(), tt.wantMessage)
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, "ana@example.com", resp.User.Email)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+)
+
+// toUser convierte un usuario al mensaje User, sin datos sensibles
+func toUser(user *domain.User) *tasksv1.User {
+	return &tasksv1.User{
+		Id:        uint32(user.ID),
+		FullName:  user.FullName,
+		Email:     user.Email,
+		Version:   uint32(user.Version),
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// toTask convierte una tarea al mensaje Task
+func toTask(task *domain.Task) *tasksv1.Task {
+	message := fromTaskResponse(task.ToResponse())
+	if task.DeletedAt.Valid {
+		message.DeletedAt = task.DeletedAt.Time.Unix()
+	}
+	return message
+}
+
+// toTasks convierte una lista de tareas a mensajes Task
+func toTasks(tasks []domain.Task) []*tasksv1.Task {
+	messages := make([]*tasksv1.Task, len(tasks))
+	for i := range tasks {
+		messages[i] = toTask(&tasks[i])
+	}
+	return messages
+}
+
+// fromTaskResponse convierte la respuesta de una tarea al mensaje Task
+func fromTaskResponse(task domain.TaskResponse) *tasksv1.Task {
+	return &tasksv1.Task{
+		Id:        uint32(task.ID),
+		Title:     task.Title,
+		Completed: task.Completed,
+		Position:  task.Position,
+		UserId:    uint32(task.UserID),
+		ClientId:  task.ClientID,
+		Version:   uint32(task.Version),
+		CreatedAt: task.CreatedAt,
+		UpdatedAt: task.UpdatedAt,
+	}
+}
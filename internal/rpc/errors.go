@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"errors"
+	"log"
+
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/patch"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus traduce los errores de los servicios a estados gRPC, con los mismos criterios
+// que los códigos HTTP de la API REST. Los errores inesperados se registran en el log
+// y no exponen su detalle al cliente.
+func toStatus(err error) error {
+	var syntaxErr *filter.SyntaxError
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrTaskUnauthorized):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrUserAlreadyExists),
+		errors.Is(err, service.ErrEmailAlreadyRegistered):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrPreconditionFailed),
+		errors.Is(err, patch.ErrTestFailed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrConcurrentModification):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, service.ErrInvalidMove),
+		errors.Is(err, service.ErrMoveAnchorNotFound),
+		errors.Is(err, service.ErrEmptySearchQuery),
+		errors.Is(err, service.ErrUnsupportedLang),
+		errors.Is(err, service.ErrInvalidSyncToken),
+		errors.Is(err, service.ErrInvalidDocument),
+		errors.Is(err, patch.ErrUnsupportedMediaType),
+		errors.Is(err, patch.ErrInvalidPatch),
+		errors.Is(err, patch.ErrCannotApply),
+		errors.As(err, &syntaxErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		log.Printf("Error en llamada gRPC: %v", err)
+		return status.Error(codes.Internal, "error interno del servidor")
+	}
+}
+
+// validate valida una petición con las mismas reglas de binding que la API REST
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return status.Error(codes.InvalidArgument, "Datos inválidos: "+err.Error())
+	}
+	return nil
+}
+
+// expectedVersion convierte la versión esperada de la petición; 0 indica que no se verifica.
+// Si requireVersion es true la versión es obligatoria, igual que If-Match con REQUIRE_IF_MATCH.
+func expectedVersion(version uint32, requireVersion bool) (uint, error) {
+	if version == 0 && requireVersion {
+		return 0, status.Error(codes.FailedPrecondition, "la versión esperada es requerida")
+	}
+	return uint(version), nil
+}
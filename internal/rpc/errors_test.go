@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	"github.com/alexroel/gin-tasks-api/pkg/patch"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{"tarea no encontrada", service.ErrTaskNotFound, codes.NotFound},
+		{"usuario no encontrado", service.ErrUserNotFound, codes.NotFound},
+		{"tarea ajena", service.ErrTaskUnauthorized, codes.PermissionDenied},
+		{"credenciales inválidas", service.ErrInvalidCredentials, codes.Unauthenticated},
+		{"usuario existente", service.ErrUserAlreadyExists, codes.AlreadyExists},
+		{"email registrado", service.ErrEmailAlreadyRegistered, codes.AlreadyExists},
+		{"versión distinta", service.ErrPreconditionFailed, codes.FailedPrecondition},
+		{"test de JSON Patch", patch.ErrTestFailed, codes.FailedPrecondition},
+		{"modificación concurrente", service.ErrConcurrentModification, codes.Aborted},
+		{"movimiento inválido", service.ErrInvalidMove, codes.InvalidArgument},
+		{"referencia inexistente", service.ErrMoveAnchorNotFound, codes.InvalidArgument},
+		{"búsqueda vacía", service.ErrEmptySearchQuery, codes.InvalidArgument},
+		{"idioma no soportado", service.ErrUnsupportedLang, codes.InvalidArgument},
+		{"token de sincronización", service.ErrInvalidSyncToken, codes.InvalidArgument},
+		{"documento inválido", service.ErrInvalidDocument, codes.InvalidArgument},
+		{"tipo de patch", patch.ErrUnsupportedMediaType, codes.InvalidArgument},
+		{"patch inválido", patch.ErrInvalidPatch, codes.InvalidArgument},
+		{"patch no aplicable", patch.ErrCannotApply, codes.InvalidArgument},
+		{"sintaxis del filtro", &filter.SyntaxError{Pos: 3, Msg: "se esperaba un valor"}, codes.InvalidArgument},
+		{"error envuelto", fmt.Errorf("al mover: %w", service.ErrTaskNotFound), codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(toStatus(tt.err))
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.err.Error(), st.Message())
+		})
+	}
+}
+
+// Los errores inesperados no exponen su detalle al cliente
+func TestToStatusInternal(t *testing.T) {
+	st, ok := status.FromError(toStatus(errors.New("dial tcp 10.0.0.5:5432: connection refused")))
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "error interno del servidor", st.Message())
+}
@@ -0,0 +1,44 @@
+// Package rpc expone los servicios de autenticación y de tareas mediante gRPC,
+// en un puerto propio y con los mismos servicios que la API REST.
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewServer crea el servidor gRPC con los servicios de autenticación y de tareas.
+// Las llamadas se autentican con el token JWT del metadato authorization, salvo Register y Login.
+// Si requireVersion es true, las llamadas que modifican recursos deben indicar la versión esperada.
+func NewServer(authService service.AuthServiceInterface, taskService service.TaskService, jwtSecret string, requireVersion bool) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(AuthInterceptor(jwtSecret)))
+	tasksv1.RegisterAuthServiceServer(server, &authServer{auth: authService, requireVersion: requireVersion})
+	tasksv1.RegisterTaskServiceServer(server, &taskServer{tasks: taskService, requireVersion: requireVersion})
+	return server
+}
+
+// NewGateway crea un proxy HTTP/JSON que traduce las rutas /v1/... a llamadas al servidor gRPC
+// en grpcAddr, siguiendo las anotaciones google.api.http de los archivos .proto.
+// El encabezado Authorization se reenvía como metadato, por lo que la autenticación es la misma.
+// Los campos usan los nombres de los .proto (user_id, created_at), igual que la API REST.
+func NewGateway(ctx context.Context, grpcAddr string) (http.Handler, error) {
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}))
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := tasksv1.RegisterAuthServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, err
+	}
+	if err := tasksv1.RegisterTaskServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
@@ -0,0 +1,343 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/service"
+	tasksv1 "github.com/alexroel/gin-tasks-api/pkg/pb/tasks/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Límites de la búsqueda, iguales a los de la API REST
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// taskServer implementa TaskService sobre service.TaskService
+type taskServer struct {
+	tasksv1.UnimplementedTaskServiceServer
+	tasks          service.TaskService
+	requireVersion bool
+}
+
+// taskID valida el ID de tarea de la petición
+func taskID(id uint32) (uint, error) {
+	if id == 0 {
+		return 0, status.Error(codes.InvalidArgument, "ID de tarea inválido")
+	}
+	return uint(id), nil
+}
+
+// CreateTask crea una tarea
+func (s *taskServer) CreateTask(ctx context.Context, in *tasksv1.CreateTaskRequest) (*tasksv1.CreateTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := domain.CreateTask{Title: in.Title}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.Create(ctx, userID, &req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.CreateTaskResponse{Task: toTask(task)}, nil
+}
+
+// GetTask obtiene una tarea del usuario
+func (s *taskServer) GetTask(ctx context.Context, in *tasksv1.GetTaskRequest) (*tasksv1.GetTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.GetByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if task.UserID != userID {
+		return nil, toStatus(service.ErrTaskUnauthorized)
+	}
+	return &tasksv1.GetTaskResponse{Task: toTask(task)}, nil
+}
+
+// ListTasks obtiene las tareas del usuario, opcionalmente filtradas
+func (s *taskServer) ListTasks(ctx context.Context, in *tasksv1.ListTasksRequest) (*tasksv1.ListTasksResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []domain.Task
+	if in.Filter != "" {
+		tasks, err = s.tasks.Filter(ctx, userID, in.Filter)
+	} else {
+		tasks, err = s.tasks.GetByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.ListTasksResponse{Tasks: toTasks(tasks)}, nil
+}
+
+// UpdateTask actualiza los campos indicados de una tarea
+func (s *taskServer) UpdateTask(ctx context.Context, in *tasksv1.UpdateTaskRequest) (*tasksv1.UpdateTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	req := domain.UpdateTask{Title: in.Title, Completed: in.Completed}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.Update(ctx, id, userID, &req, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.UpdateTaskResponse{Task: toTask(task)}, nil
+}
+
+// PatchTask aplica un JSON Merge Patch o un JSON Patch a una tarea
+func (s *taskServer) PatchTask(ctx context.Context, in *tasksv1.PatchTaskRequest) (*tasksv1.PatchTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.Patch(ctx, id, userID, in.ContentType, in.Patch, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.PatchTaskResponse{Task: toTask(task)}, nil
+}
+
+// UpdateTaskStatus marca una tarea como completada o pendiente
+func (s *taskServer) UpdateTaskStatus(ctx context.Context, in *tasksv1.UpdateTaskStatusRequest) (*tasksv1.UpdateTaskStatusResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.UpdateStatus(ctx, id, userID, in.Completed, version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.UpdateTaskStatusResponse{Task: toTask(task)}, nil
+}
+
+// DeleteTask mueve una tarea a la papelera
+func (s *taskServer) DeleteTask(ctx context.Context, in *tasksv1.DeleteTaskRequest) (*tasksv1.DeleteTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(in.Version, s.requireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tasks.Delete(ctx, id, userID, version); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.DeleteTaskResponse{}, nil
+}
+
+// ListTrash obtiene las tareas en la papelera
+func (s *taskServer) ListTrash(ctx context.Context, _ *tasksv1.ListTrashRequest) (*tasksv1.ListTrashResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.tasks.GetTrash(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.ListTrashResponse{Tasks: toTasks(tasks)}, nil
+}
+
+// RestoreTask restaura una tarea de la papelera
+func (s *taskServer) RestoreTask(ctx context.Context, in *tasksv1.RestoreTaskRequest) (*tasksv1.RestoreTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.tasks.Restore(ctx, id, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.RestoreTaskResponse{Task: toTask(task)}, nil
+}
+
+// PurgeTask elimina definitivamente una tarea de la papelera
+func (s *taskServer) PurgeTask(ctx context.Context, in *tasksv1.PurgeTaskRequest) (*tasksv1.PurgeTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tasks.Purge(ctx, id, userID); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.PurgeTaskResponse{}, nil
+}
+
+// SearchTasks busca tareas por texto
+func (s *taskServer) SearchTasks(ctx context.Context, in *tasksv1.SearchTasksRequest) (*tasksv1.SearchTasksResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	results, err := s.tasks.Search(ctx, userID, in.Query, in.Lang, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &tasksv1.SearchTasksResponse{Results: make([]*tasksv1.SearchResult, len(results))}
+	for i := range results {
+		response.Results[i] = &tasksv1.SearchResult{
+			Task:    toTask(&results[i].Task),
+			Rank:    results[i].Rank,
+			Snippet: results[i].Snippet,
+		}
+	}
+	return response, nil
+}
+
+// MoveTask reordena una tarea entre after_id y before_id
+func (s *taskServer) MoveTask(ctx context.Context, in *tasksv1.MoveTaskRequest) (*tasksv1.MoveTaskResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := taskID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var req domain.MoveTask
+	if in.AfterId != nil {
+		afterID := uint(*in.AfterId)
+		req.AfterID = &afterID
+	}
+	if in.BeforeId != nil {
+		beforeID := uint(*in.BeforeId)
+		req.BeforeID = &beforeID
+	}
+
+	task, err := s.tasks.Move(ctx, id, userID, &req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasksv1.MoveTaskResponse{Task: toTask(task)}, nil
+}
+
+// GetChanges obtiene las tareas que cambiaron desde un token de sincronización
+func (s *taskServer) GetChanges(ctx context.Context, in *tasksv1.GetChangesRequest) (*tasksv1.GetChangesResponse, error) {
+	// Obtener ID del usuario autenticado
+	userID, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit < 1 {
+		limit = domain.DefaultSyncLimit
+	}
+	if limit > domain.MaxSyncLimit {
+		limit = domain.MaxSyncLimit
+	}
+
+	changes, err := s.tasks.Changes(ctx, userID, in.Token, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &tasksv1.GetChangesResponse{
+		Changes: make([]*tasksv1.Change, len(changes.Changes)),
+		Token:   changes.Token,
+		HasMore: changes.HasMore,
+		Reset_:  changes.Reset,
+	}
+	for i, change := range changes.Changes {
+		response.Changes[i] = &tasksv1.Change{
+			Id:       uint32(change.ID),
+			ClientId: change.ClientID,
+			Deleted:  change.Deleted,
+		}
+		if change.Task != nil {
+			response.Changes[i].Task = fromTaskResponse(*change.Task)
+		}
+	}
+	return response, nil
+}
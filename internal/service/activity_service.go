@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+// ActivityService define las operaciones del historial de actividad
+type ActivityService interface {
+	Record(ctx context.Context, actorID uint, action, entityType string, entityID uint, before, after interface{})
+	Write(ctx context.Context, repo repository.ActivityRepository, actorID uint, action, entityType string, entityID uint, before, after interface{}) error
+	Notify(ctx context.Context, actorID uint, action, entityType string, entityID uint, before, after interface{})
+	GetTaskActivity(ctx context.Context, taskID, userID uint, page, limit int) (*domain.ActivityPage, error)
+	GetUserFeed(ctx context.Context, userID uint, page, limit int) (*domain.ActivityPage, error)
+	GetPurgedTaskIDs(ctx context.Context, userID uint, since int64) ([]uint, error)
+}
+
+type activityService struct {
+	repo      repository.ActivityRepository
+	taskRepo  repository.TaskRepository
+	notifiers []TaskNotifier
+}
+
+// NewActivityService crea una nueva instancia de ActivityService.
+// Cada acción registrada sobre una tarea se entrega además a los notifiers (eventos en tiempo real, webhooks).
+func NewActivityService(repo repository.ActivityRepository, taskRepo repository.TaskRepository, notifiers ...TaskNotifier) ActivityService {
+	return &activityService{repo: repo, taskRepo: taskRepo, notifiers: notifiers}
+}
+
+// Record agrega un registro al historial con las diferencias entre before y after y lo notifica.
+// Un error al registrar la actividad no interrumpe la operación principal, solo se registra en el log.
+func (s *activityService) Record(ctx context.Context, actorID uint, action, entityType string, entityID uint, before, after interface{}) {
+	if err := s.Write(ctx, s.repo, actorID, action, entityType, entityID, before, after); err != nil {
+		log.Println("Error al registrar la actividad:", err)
+	}
+	s.Notify(ctx, actorID, action, entityType, entityID, before, after)
+}
+
+// Write guarda el registro con repo, que puede estar ligado a la transacción de la operación
+// (TaskRepository.Activities). Devuelve el error para que la operación se revierta con él.
+// No notifica: Notify se llama después de confirmar la transacción.
+func (s *activityService) Write(ctx context.Context, repo repository.ActivityRepository, actorID uint, action, entityType string, entityID uint, before, after interface{}) error {
+	activity := &domain.Activity{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	changes, err := diffChanges(before, after)
+	if err != nil {
+		log.Println("Error al calcular los cambios de la actividad:", err)
+	}
+	if len(changes) > 0 {
+		data, err := json.Marshal(changes)
+		if err != nil {
+			log.Println("Error al serializar los cambios de la actividad:", err)
+		} else {
+			activity.Changes = string(data)
+		}
+	}
+
+	return repo.Create(ctx, activity)
+}
+
+// Notify entrega una acción sobre una tarea a los notifiers (eventos en tiempo real, webhooks)
+func (s *activityService) Notify(ctx context.Context, actorID uint, action, entityType string, entityID uint, before, after interface{}) {
+	if entityType != domain.EntityTask {
+		return
+	}
+	for _, notifier := range s.notifiers {
+		notifier.NotifyTask(ctx, actorID, action, entityID, before, after)
+	}
+}
+
+// GetTaskActivity obtiene el historial de una tarea del usuario
+func (s *activityService) GetTaskActivity(ctx context.Context, taskID, userID uint, page, limit int) (*domain.ActivityPage, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		// El historial también está disponible para las tareas en la papelera
+		task, err = s.taskRepo.GetDeletedByID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if task.UserID != userID {
+		return nil, ErrTaskUnauthorized
+	}
+
+	activities, total, err := s.repo.GetByEntity(ctx, domain.EntityTask, taskID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	return newActivityPage(activities, page, limit, total), nil
+}
+
+// GetUserFeed obtiene el historial de acciones realizadas por el usuario
+func (s *activityService) GetUserFeed(ctx context.Context, userID uint, page, limit int) (*domain.ActivityPage, error) {
+	activities, total, err := s.repo.GetByActorID(ctx, userID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	return newActivityPage(activities, page, limit, total), nil
+}
+
+// newActivityPage construye la respuesta paginada del historial
+func newActivityPage(activities []domain.Activity, page, limit int, total int64) *domain.ActivityPage {
+	items := make([]domain.ActivityResponse, 0, len(activities))
+	for _, activity := range activities {
+		items = append(items, activity.ToResponse())
+	}
+	return &domain.ActivityPage{
+		Items: items,
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}
+}
+
+// diffChanges compara dos representaciones JSON y devuelve solo los campos que cambiaron.
+// Si before es nil se trata de una creación; si after es nil, de una eliminación.
+func diffChanges(before, after interface{}) (map[string]domain.FieldChange, error) {
+	beforeMap, err := toFieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := toFieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for key, value := range afterMap {
+		if old, ok := beforeMap[key]; !ok || !reflect.DeepEqual(old, value) {
+			changes[key] = domain.FieldChange{From: beforeMap[key], To: value}
+		}
+	}
+	for key, value := range beforeMap {
+		if _, ok := afterMap[key]; !ok {
+			changes[key] = domain.FieldChange{From: value}
+		}
+	}
+
+	// La fecha de actualización y la versión cambian siempre y no aportan información
+	delete(changes, "updated_at")
+	delete(changes, "version")
+	return changes, nil
+}
+
+// toFieldMap convierte un valor a un mapa de campos usando su representación JSON
+func toFieldMap(v interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// GetPurgedTaskIDs obtiene los IDs de las tareas que el usuario eliminó definitivamente desde la fecha dada
+func (s *activityService) GetPurgedTaskIDs(ctx context.Context, userID uint, since int64) ([]uint, error) {
+	return s.repo.GetEntityIDsSince(ctx, userID, domain.ActionTaskPurged, since)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+var (
+	ErrAppPasswordNotFound     = errors.New("contraseña de aplicación no encontrada")
+	ErrAppPasswordUnauthorized = errors.New("no tienes permiso para acceder a esta contraseña de aplicación")
+	ErrInvalidAppPassword      = errors.New("usuario o contraseña de aplicación incorrectos")
+)
+
+// appPasswordTouchInterval evita escribir el último uso en cada petición autenticada
+const appPasswordTouchInterval = 5 * time.Minute
+
+// AppPasswordService define las operaciones de las contraseñas de aplicación
+type AppPasswordService interface {
+	Create(ctx context.Context, userID uint, req *domain.CreateAppPassword) (*domain.AppPassword, string, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.AppPassword, error)
+	Delete(ctx context.Context, id, userID uint) error
+	Authenticate(ctx context.Context, email, password string) (*domain.User, error)
+}
+
+type appPasswordService struct {
+	repo     repository.AppPasswordRepository
+	userRepo repository.UserRepository
+}
+
+// NewAppPasswordService crea una nueva instancia de AppPasswordService
+func NewAppPasswordService(repo repository.AppPasswordRepository, userRepo repository.UserRepository) AppPasswordService {
+	return &appPasswordService{repo: repo, userRepo: userRepo}
+}
+
+// Create genera una contraseña de aplicación. Devuelve el secreto, que no se vuelve a mostrar.
+func (s *appPasswordService) Create(ctx context.Context, userID uint, req *domain.CreateAppPassword) (*domain.AppPassword, string, error) {
+	secret, err := newAppPassword()
+	if err != nil {
+		return nil, "", err
+	}
+
+	password := &domain.AppPassword{
+		Name:         req.Name,
+		PasswordHash: hashAppPassword(secret),
+		UserID:       userID,
+	}
+	if err := s.repo.Create(ctx, password); err != nil {
+		return nil, "", err
+	}
+
+	return password, formatAppPassword(secret), nil
+}
+
+// GetByUserID obtiene las contraseñas de aplicación de un usuario
+func (s *appPasswordService) GetByUserID(ctx context.Context, userID uint) ([]domain.AppPassword, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// Delete revoca una contraseña de aplicación; los clientes que la usan dejan de autenticarse
+func (s *appPasswordService) Delete(ctx context.Context, id, userID uint) error {
+	password, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if password == nil {
+		return ErrAppPasswordNotFound
+	}
+	if password.UserID != userID {
+		return ErrAppPasswordUnauthorized
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Authenticate verifica el email del usuario y una de sus contraseñas de aplicación
+func (s *appPasswordService) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	if email == "" || password == "" {
+		return nil, ErrInvalidAppPassword
+	}
+
+	// Los espacios y guiones facilitan copiar la contraseña; no forman parte del secreto
+	secret := strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(password))
+	appPassword, err := s.repo.GetByHash(ctx, hashAppPassword(secret))
+	if err != nil {
+		return nil, err
+	}
+	if appPassword == nil {
+		return nil, ErrInvalidAppPassword
+	}
+
+	user, err := s.userRepo.GetByID(ctx, appPassword.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !strings.EqualFold(user.Email, email) {
+		return nil, ErrInvalidAppPassword
+	}
+
+	now := time.Now()
+	if appPassword.LastUsedAt < now.Add(-appPasswordTouchInterval).Unix() {
+		if err := s.repo.TouchLastUsed(ctx, appPassword.ID, now.Unix()); err != nil {
+			log.Println("Error al registrar el uso de la contraseña de aplicación:", err)
+		}
+	}
+	return user, nil
+}
+
+// newAppPassword genera el secreto de una contraseña de aplicación: 32 caracteres hexadecimales
+func newAppPassword() (string, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// formatAppPassword agrupa el secreto en bloques de 4 caracteres para que sea fácil de copiar
+func formatAppPassword(secret string) string {
+	groups := make([]string, 0, len(secret)/4)
+	for i := 0; i < len(secret); i += 4 {
+		groups = append(groups, secret[i:min(i+4, len(secret))])
+	}
+	return strings.Join(groups, "-")
+}
+
+// hashAppPassword calcula el hash con el que se guarda y busca una contraseña de aplicación.
+// El secreto es aleatorio y largo, por lo que no necesita un hash lento como bcrypt.
+func hashAppPassword(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,33 +3,54 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
-	"github.com/alexroel/gin-tasks-api/internal/config"
 	"github.com/alexroel/gin-tasks-api/internal/domain"
 	"github.com/alexroel/gin-tasks-api/internal/repository"
 	"github.com/alexroel/gin-tasks-api/pkg/jwt"
 	"github.com/alexroel/gin-tasks-api/pkg/utils"
 )
 
+var (
+	ErrUserAlreadyExists      = errors.New("Ya existe un usuario con ese email")
+	ErrInvalidCredentials     = errors.New("Credenciales inválidas")
+	ErrUserNotFound           = errors.New("usuario no encontrado")
+	ErrEmailAlreadyRegistered = errors.New("el email ya está registrado")
+)
+
 // AuthServiceInterface define las operaciones del servicio de autenticación
 type AuthServiceInterface interface {
 	Register(ctx context.Context, req *domain.UserCreate) (*domain.User, error)
+	CreateUser(ctx context.Context, req *domain.UserCreate, isAdmin bool) (*domain.User, error)
+	ResetPassword(ctx context.Context, email, password string) (*domain.User, error)
 	Login(ctx context.Context, req *domain.UserLogin) (string, *domain.User, error)
+	RefreshToken(ctx context.Context, userID uint) (string, *domain.User, error)
 	GetUserByID(ctx context.Context, userID uint) (*domain.User, error)
-	UpdateProfile(ctx context.Context, userID uint, req *domain.UserUpdate) (*domain.User, error)
-	DeleteAccount(ctx context.Context, userID uint) error
+	UpdateProfile(ctx context.Context, userID uint, req *domain.UserUpdate, version uint) (*domain.User, error)
+	PatchProfile(ctx context.Context, userID uint, contentType string, patchData []byte, version uint) (*domain.User, error)
+	DeleteAccount(ctx context.Context, userID, version uint) error
 }
 
 type AuthService struct {
-	repo repository.UserRepository
+	repo        repository.UserRepository
+	activity    ActivityService
+	jwtSecret   string
+	jwtExpireIn time.Duration
 }
 
-func NewAuthService(repo repository.UserRepository) *AuthService {
-	return &AuthService{repo: repo}
+// NewAuthService crea el servicio de autenticación.
+// Los tokens se firman con jwtSecret y expiran tras jwtExpireIn.
+func NewAuthService(repo repository.UserRepository, activity ActivityService, jwtSecret string, jwtExpireIn time.Duration) *AuthService {
+	return &AuthService{repo: repo, activity: activity, jwtSecret: jwtSecret, jwtExpireIn: jwtExpireIn}
 }
 
-// Register registra un nuevo usuario
+// Register registra un nuevo usuario. El registro público nunca crea administradores.
 func (s *AuthService) Register(ctx context.Context, req *domain.UserCreate) (*domain.User, error) {
+	return s.CreateUser(ctx, req, false)
+}
+
+// CreateUser crea un usuario, opcionalmente con permisos de administrador
+func (s *AuthService) CreateUser(ctx context.Context, req *domain.UserCreate, isAdmin bool) (*domain.User, error) {
 	// Validar si el usuario ya existe
 	ok, err := s.repo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
@@ -37,7 +58,7 @@ func (s *AuthService) Register(ctx context.Context, req *domain.UserCreate) (*do
 	}
 
 	if ok {
-		return nil, errors.New("Ya existe un usuario con ese email")
+		return nil, ErrUserAlreadyExists
 	}
 
 	// Hashear la contraseña
@@ -50,6 +71,7 @@ func (s *AuthService) Register(ctx context.Context, req *domain.UserCreate) (*do
 		FullName: req.FullName,
 		Email:    req.Email,
 		Password: hashedPassword,
+		IsAdmin:  isAdmin,
 	}
 
 	// Crear el usuario
@@ -66,18 +88,39 @@ func (s *AuthService) Login(ctx context.Context, req *domain.UserLogin) (string,
 	// Buscar el usuario por email
 	user, err := s.repo.GetByEmail(ctx, req.Email)
 	if err != nil || user == nil {
-		return "", nil, errors.New("Credenciales inválidas")
+		s.activity.Record(ctx, 0, domain.ActionAuthLoginFailed, domain.EntityUser, 0, nil, map[string]string{"email": req.Email})
+		return "", nil, ErrInvalidCredentials
 	}
 	// Verificar la contraseña
 	if !utils.CheckPassword(user.Password, req.Password) {
-		return "", nil, errors.New("Credenciales inválidas")
+		s.activity.Record(ctx, user.ID, domain.ActionAuthLoginFailed, domain.EntityUser, user.ID, nil, nil)
+		return "", nil, ErrInvalidCredentials
 	}
 	// Generar token
-	token, err := jwt.GenerateToken(user.ID, user.Email, config.AppConfig.JWTSecret, config.AppConfig.JWTExpireIn)
+	token, err := jwt.GenerateToken(user.ID, user.Email, s.jwtSecret, s.jwtExpireIn)
 	if err != nil {
 		return "", nil, errors.New("Error al generar Token")
 	}
 
+	s.activity.Record(ctx, user.ID, domain.ActionAuthLogin, domain.EntityUser, user.ID, nil, nil)
+	return token, user, nil
+}
+
+// RefreshToken emite un nuevo token para el usuario autenticado, con la expiración completa.
+// El usuario debe seguir existiendo: una cuenta eliminada no puede renovar su token.
+func (s *AuthService) RefreshToken(ctx context.Context, userID uint) (string, *domain.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if user == nil {
+		return "", nil, ErrUserNotFound
+	}
+
+	token, err := jwt.GenerateToken(user.ID, user.Email, s.jwtSecret, s.jwtExpireIn)
+	if err != nil {
+		return "", nil, errors.New("Error al generar Token")
+	}
 	return token, user, nil
 }
 
@@ -86,17 +129,68 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID uint) (*domain.Use
 	return s.repo.GetByID(ctx, userID)
 }
 
-// UpdateProfile actualiza el perfil del usuario autenticado
-func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *domain.UserUpdate) (*domain.User, error) {
+// UpdateProfile actualiza el perfil del usuario autenticado.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *domain.UserUpdate, version uint) (*domain.User, error) {
 	// Obtener usuario existente
 	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("usuario no encontrado")
+		return nil, ErrUserNotFound
 	}
+	if err := checkVersion(user.Version, version); err != nil {
+		return nil, err
+	}
+
+	return s.saveProfile(ctx, user, req, version)
+}
 
+// PatchProfile aplica un JSON Merge Patch o un JSON Patch al perfil del usuario autenticado.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *AuthService) PatchProfile(ctx context.Context, userID uint, contentType string, patchData []byte, version uint) (*domain.User, error) {
+	// Obtener usuario existente
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if err := checkVersion(user.Version, version); err != nil {
+		return nil, err
+	}
+
+	var doc domain.UserDocument
+	if err := applyPatch(user.ToDocument(), contentType, patchData, &doc); err != nil {
+		return nil, err
+	}
+
+	req := &domain.UserUpdate{
+		FullName: &doc.FullName,
+		Email:    &doc.Email,
+		Password: doc.Password,
+	}
+	return s.saveProfile(ctx, user, req, version)
+}
+
+// ResetPassword reemplaza la contraseña del usuario con el email indicado sin pedir la actual.
+// Es una operación administrativa: queda registrada en el historial igual que un cambio de contraseña.
+func (s *AuthService) ResetPassword(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return s.saveProfile(ctx, user, &domain.UserUpdate{Password: &password}, 0)
+}
+
+// saveProfile aplica los cambios al usuario y lo guarda con control de versión
+func (s *AuthService) saveProfile(ctx context.Context, user *domain.User, req *domain.UserUpdate, version uint) (*domain.User, error) {
 	// Actualizar campos si se proporcionan
 	if req.FullName != nil {
 		user.FullName = *req.FullName
@@ -109,7 +203,7 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *domai
 			return nil, err
 		}
 		if exists {
-			return nil, errors.New("el email ya está registrado")
+			return nil, ErrEmailAlreadyRegistered
 		}
 		user.Email = *req.Email
 	}
@@ -123,22 +217,34 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *domai
 	}
 
 	if err := s.repo.Update(ctx, user); err != nil {
-		return nil, err
+		return nil, versionError(err, version)
 	}
 
+	if req.Password != nil {
+		s.activity.Record(ctx, user.ID, domain.ActionAuthPasswordChanged, domain.EntityUser, user.ID, nil, nil)
+	}
 	return user, nil
 }
 
-// DeleteAccount elimina la cuenta del usuario autenticado
-func (s *AuthService) DeleteAccount(ctx context.Context, userID uint) error {
+// DeleteAccount elimina la cuenta del usuario autenticado.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID, version uint) error {
 	// Verificar que el usuario existe
 	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 	if user == nil {
-		return errors.New("usuario no encontrado")
+		return ErrUserNotFound
+	}
+	if err := checkVersion(user.Version, version); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, userID, user.Version); err != nil {
+		return versionError(err, version)
 	}
 
-	return s.repo.Delete(ctx, userID)
+	s.activity.Record(ctx, userID, domain.ActionAuthAccountDeleted, domain.EntityUser, userID, user.ToResponse(), nil)
+	return nil
 }
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/exporter"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/pkg/ical"
+)
+
+var (
+	ErrCalendarFeedNotFound     = errors.New("feed de calendario no encontrado")
+	ErrCalendarFeedUnauthorized = errors.New("no tienes permiso para acceder a este feed de calendario")
+)
+
+// calendarRefreshInterval es el intervalo de actualización que se sugiere a los clientes de calendario
+const calendarRefreshInterval = "PT15M"
+
+// CalendarService define las operaciones de negocio para los feeds de calendario
+type CalendarService interface {
+	Create(ctx context.Context, userID uint, req *domain.CreateCalendarFeed) (*domain.CalendarFeed, string, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.CalendarFeed, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.CalendarFeed, error)
+	Update(ctx context.Context, id, userID uint, req *domain.UpdateCalendarFeed) (*domain.CalendarFeed, error)
+	Delete(ctx context.Context, id, userID uint) error
+	Rotate(ctx context.Context, id, userID uint) (*domain.CalendarFeed, string, error)
+	GetByToken(ctx context.Context, token string) (*domain.CalendarFeed, error)
+	FeedETag(ctx context.Context, feed *domain.CalendarFeed) (string, error)
+	WriteFeed(ctx context.Context, feed *domain.CalendarFeed, w io.Writer) error
+}
+
+type calendarService struct {
+	repo     repository.CalendarFeedRepository
+	taskRepo repository.TaskRepository
+}
+
+// NewCalendarService crea una nueva instancia de CalendarService
+func NewCalendarService(repo repository.CalendarFeedRepository, taskRepo repository.TaskRepository) CalendarService {
+	return &calendarService{repo: repo, taskRepo: taskRepo}
+}
+
+// Create crea un feed después de validar su filtro. Devuelve el token secreto, que no se
+// vuelve a mostrar porque solo se guarda su hash.
+func (s *calendarService) Create(ctx context.Context, userID uint, req *domain.CreateCalendarFeed) (*domain.CalendarFeed, string, error) {
+	if _, err := s.parseFilter(req.Filter); err != nil {
+		return nil, "", err
+	}
+
+	token, err := newCalendarToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	feed := &domain.CalendarFeed{
+		Name:      req.Name,
+		Filter:    req.Filter,
+		TokenHash: hashCalendarToken(token),
+		UserID:    userID,
+	}
+	if err := s.repo.Create(ctx, feed); err != nil {
+		return nil, "", err
+	}
+
+	return feed, token, nil
+}
+
+// GetByUserID obtiene los feeds de calendario de un usuario
+func (s *calendarService) GetByUserID(ctx context.Context, userID uint) ([]domain.CalendarFeed, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// GetByID obtiene un feed verificando que pertenece al usuario
+func (s *calendarService) GetByID(ctx context.Context, id, userID uint) (*domain.CalendarFeed, error) {
+	feed, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if feed.UserID != userID {
+		return nil, ErrCalendarFeedUnauthorized
+	}
+	return feed, nil
+}
+
+// Update actualiza el nombre o el filtro de un feed; el token no cambia
+func (s *calendarService) Update(ctx context.Context, id, userID uint, req *domain.UpdateCalendarFeed) (*domain.CalendarFeed, error) {
+	feed, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar campos si se proporcionan
+	if req.Name != nil {
+		feed.Name = *req.Name
+	}
+	if req.Filter != nil {
+		if _, err := s.parseFilter(*req.Filter); err != nil {
+			return nil, err
+		}
+		feed.Filter = *req.Filter
+	}
+
+	if err := s.repo.Update(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// Delete elimina un feed; su URL deja de funcionar de inmediato
+func (s *calendarService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Rotate reemplaza el token de un feed. La URL anterior deja de funcionar.
+func (s *calendarService) Rotate(ctx context.Context, id, userID uint) (*domain.CalendarFeed, string, error) {
+	feed, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newCalendarToken()
+	if err != nil {
+		return nil, "", err
+	}
+	feed.TokenHash = hashCalendarToken(token)
+
+	if err := s.repo.Update(ctx, feed); err != nil {
+		return nil, "", err
+	}
+
+	return feed, token, nil
+}
+
+// GetByToken obtiene el feed al que pertenece un token
+func (s *calendarService) GetByToken(ctx context.Context, token string) (*domain.CalendarFeed, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feed, err := s.repo.GetByTokenHash(ctx, hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	return feed, nil
+}
+
+// FeedETag calcula el ETag del contenido de un feed sin leer sus tareas: cambia cuando se
+// modifica el feed o cuando cambia el conjunto de tareas que cumplen su filtro.
+func (s *calendarService) FeedETag(ctx context.Context, feed *domain.CalendarFeed) (string, error) {
+	query, err := s.parseFilter(feed.Filter)
+	if err != nil {
+		return "", err
+	}
+	stats, err := s.taskRepo.GetFilterStats(ctx, feed.UserID, query)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d:%d:%s;%d:%d:%d", feed.ID, feed.UpdatedAt, feed.Filter, stats.Count, stats.LastUpdated, stats.VersionSum)
+	// Los filtros con fechas relativas cambian su resultado con el paso del tiempo
+	if query != nil {
+		fmt.Fprintf(hash, ";%s", time.Now().Format("2006010215"))
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`, nil
+}
+
+// WriteFeed escribe el calendario del feed con una VTODO por cada tarea que cumple su filtro
+func (s *calendarService) WriteFeed(ctx context.Context, feed *domain.CalendarFeed, w io.Writer) error {
+	query, err := s.parseFilter(feed.Filter)
+	if err != nil {
+		return err
+	}
+
+	writer := ical.NewWriter(w)
+	exporter.BeginCalendar(writer, feed.Name)
+	writer.Property("REFRESH-INTERVAL;VALUE=DURATION", calendarRefreshInterval)
+	writer.Property("X-PUBLISHED-TTL", calendarRefreshInterval)
+	if err := writer.Err(); err != nil {
+		return err
+	}
+
+	err = s.taskRepo.StreamByFilter(ctx, feed.UserID, query, func(task *domain.Task) error {
+		exporter.WriteTodo(writer, task)
+		return writer.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.End("VCALENDAR")
+	return writer.Err()
+}
+
+// parseFilter compila el filtro de un feed; un filtro vacío incluye todas las tareas
+func (s *calendarService) parseFilter(expression string) (*filter.Query, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	return filter.Parse(expression, time.Now())
+}
+
+// newCalendarToken genera el token secreto de la URL de un feed
+func newCalendarToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashCalendarToken calcula el hash con el que se guarda y busca un token
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
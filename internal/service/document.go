@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/alexroel/gin-tasks-api/pkg/patch"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrInvalidDocument indica que el documento resultante de un PATCH no cumple las validaciones
+var ErrInvalidDocument = errors.New("el documento resultante no es válido")
+
+// documentValidator usa las etiquetas binding, igual que la validación de Gin en los handlers
+var documentValidator = newDocumentValidator()
+
+func newDocumentValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	return v
+}
+
+// applyPatch aplica un patch a la representación JSON de current y decodifica el resultado en target,
+// validándolo con sus etiquetas binding. Los campos desconocidos en el resultado son un error.
+func applyPatch(current interface{}, contentType string, patchData []byte, target interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	patched, err := patch.Apply(contentType, doc, patchData)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if err := documentValidator.Struct(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return nil
+}
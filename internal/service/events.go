@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+)
+
+// TaskNotifier recibe las acciones registradas sobre las tareas para notificarlas fuera del historial.
+// before y after son el estado de la tarea antes y después del cambio; cualquiera puede ser nil.
+type TaskNotifier interface {
+	NotifyTask(ctx context.Context, userID uint, action string, taskID uint, before, after interface{})
+}
+
+// EventPublisher publica eventos en tiempo real para los clientes conectados de un usuario
+type EventPublisher interface {
+	Publish(ctx context.Context, userID uint, eventType string, data interface{}) error
+}
+
+// eventNotifier publica las acciones sobre tareas como eventos en tiempo real
+type eventNotifier struct {
+	events EventPublisher
+}
+
+// NewEventNotifier crea un TaskNotifier que publica eventos en tiempo real
+func NewEventNotifier(events EventPublisher) TaskNotifier {
+	return &eventNotifier{events: events}
+}
+
+// taskEventTypes traduce las acciones del historial a los eventos en tiempo real de tareas
+var taskEventTypes = map[string]string{
+	domain.ActionTaskCreated:       domain.EventTaskCreated,
+	domain.ActionTaskUpdated:       domain.EventTaskUpdated,
+	domain.ActionTaskStatusChanged: domain.EventTaskUpdated,
+	domain.ActionTaskMoved:         domain.EventTaskUpdated,
+	domain.ActionTaskRestored:      domain.EventTaskUpdated,
+	domain.ActionTaskDeleted:       domain.EventTaskDeleted,
+	domain.ActionTaskPurged:        domain.EventTaskDeleted,
+}
+
+// NotifyTask publica el evento en tiempo real correspondiente a una acción sobre una tarea
+func (n *eventNotifier) NotifyTask(ctx context.Context, userID uint, action string, taskID uint, before, after interface{}) {
+	eventType, ok := taskEventTypes[action]
+	if !ok {
+		return
+	}
+
+	event := domain.TaskEvent{ID: taskID, Action: action}
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			log.Println("Error al serializar el evento:", err)
+			return
+		}
+		event.Task = data
+	}
+
+	if err := n.events.Publish(ctx, userID, eventType, event); err != nil {
+		log.Println("Error al publicar el evento:", err)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+var (
+	ErrFilterNotFound     = errors.New("filtro no encontrado")
+	ErrFilterUnauthorized = errors.New("no tienes permiso para acceder a este filtro")
+)
+
+// FilterService define las operaciones de negocio para filtros guardados (listas inteligentes)
+type FilterService interface {
+	Create(ctx context.Context, userID uint, req *domain.CreateFilter) (*domain.SavedFilter, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.SavedFilter, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.SavedFilter, error)
+	Update(ctx context.Context, id, userID uint, req *domain.UpdateFilter) (*domain.SavedFilter, error)
+	Delete(ctx context.Context, id, userID uint) error
+	GetTasks(ctx context.Context, id, userID uint) ([]domain.Task, error)
+}
+
+type filterService struct {
+	repo     repository.FilterRepository
+	taskRepo repository.TaskRepository
+}
+
+// NewFilterService crea una nueva instancia de FilterService
+func NewFilterService(repo repository.FilterRepository, taskRepo repository.TaskRepository) FilterService {
+	return &filterService{repo: repo, taskRepo: taskRepo}
+}
+
+// Create guarda un nuevo filtro después de validar su expresión
+func (s *filterService) Create(ctx context.Context, userID uint, req *domain.CreateFilter) (*domain.SavedFilter, error) {
+	if _, err := filter.Parse(req.Query, time.Now()); err != nil {
+		return nil, err
+	}
+
+	savedFilter := &domain.SavedFilter{
+		Name:   req.Name,
+		Query:  req.Query,
+		UserID: userID,
+	}
+
+	if err := s.repo.Create(ctx, savedFilter); err != nil {
+		return nil, err
+	}
+
+	return savedFilter, nil
+}
+
+// GetByUserID obtiene los filtros guardados de un usuario
+func (s *filterService) GetByUserID(ctx context.Context, userID uint) ([]domain.SavedFilter, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// GetByID obtiene un filtro guardado verificando que pertenece al usuario
+func (s *filterService) GetByID(ctx context.Context, id, userID uint) (*domain.SavedFilter, error) {
+	savedFilter, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if savedFilter == nil {
+		return nil, ErrFilterNotFound
+	}
+	if savedFilter.UserID != userID {
+		return nil, ErrFilterUnauthorized
+	}
+	return savedFilter, nil
+}
+
+// Update actualiza un filtro guardado
+func (s *filterService) Update(ctx context.Context, id, userID uint, req *domain.UpdateFilter) (*domain.SavedFilter, error) {
+	savedFilter, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar campos si se proporcionan
+	if req.Name != nil {
+		savedFilter.Name = *req.Name
+	}
+	if req.Query != nil {
+		if _, err := filter.Parse(*req.Query, time.Now()); err != nil {
+			return nil, err
+		}
+		savedFilter.Query = *req.Query
+	}
+
+	if err := s.repo.Update(ctx, savedFilter); err != nil {
+		return nil, err
+	}
+
+	return savedFilter, nil
+}
+
+// Delete elimina un filtro guardado
+func (s *filterService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// GetTasks obtiene las tareas que cumplen un filtro guardado.
+// Las fechas relativas se evalúan en cada consulta.
+func (s *filterService) GetTasks(ctx context.Context, id, userID uint) ([]domain.Task, error) {
+	savedFilter, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := filter.Parse(savedFilter.Query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByFilter(ctx, userID, query)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+var (
+	ErrIdempotencyKeyMismatch   = errors.New("la clave de idempotencia ya se usó con una petición diferente")
+	ErrIdempotencyKeyInProgress = errors.New("la petición original con esta clave de idempotencia sigue en curso")
+)
+
+const (
+	// idempotencyPollInterval es la frecuencia con la que se consulta una petición concurrente en curso
+	idempotencyPollInterval = 100 * time.Millisecond
+	// idempotencyWaitTimeout es el tiempo máximo que se espera a que termine la petición original
+	idempotencyWaitTimeout = 10 * time.Second
+)
+
+// IdempotencyService define las operaciones para reintentar peticiones POST de forma segura
+type IdempotencyService interface {
+	Begin(ctx context.Context, userID uint, key, requestHash string) (*domain.IdempotencyKey, error)
+	Complete(ctx context.Context, userID uint, key string, statusCode int, header http.Header, body []byte) error
+	Release(ctx context.Context, userID uint, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	repo  repository.IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyService crea una nueva instancia de IdempotencyService.
+// ttl es el tiempo durante el cual se conserva la respuesta de cada clave y lease el tiempo que
+// una petición en curso la mantiene reservada.
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl, lease time.Duration) IdempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl, lease: lease}
+}
+
+// Begin reserva la clave para la petición actual. Devuelve nil si la petición debe ejecutarse,
+// o la clave ya completada si se trata de un reintento cuya respuesta debe repetirse.
+// Si otra petición con la misma clave está en curso, espera a que termine; si su reserva vence
+// antes (la petición no terminó ni liberó la clave), la toma y la petición actual se ejecuta.
+func (s *idempotencyService) Begin(ctx context.Context, userID uint, key, requestHash string) (*domain.IdempotencyKey, error) {
+	deadline := time.Now().Add(idempotencyWaitTimeout)
+
+	for {
+		now := time.Now()
+		reservation := &domain.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(s.ttl).Unix(),
+			LockedUntil: now.Add(s.lease).Unix(),
+		}
+		created, err := s.repo.Create(ctx, reservation)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return nil, nil
+		}
+
+		existing, err := s.repo.Get(ctx, userID, key)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case existing == nil:
+			// La petición original falló y liberó la clave: se vuelve a intentar la reserva
+			continue
+		case existing.ExpiresAt <= now.Unix():
+			// La clave expiró pero aún no se purgó: se reserva de nuevo en una sola operación
+			// condicionada a que siga expirada. Si otra petición se adelantó, la siguiente vuelta
+			// encuentra su reserva en curso.
+			renewed, err := s.repo.Renew(ctx, reservation, now.Unix())
+			if err != nil {
+				return nil, err
+			}
+			if renewed {
+				return nil, nil
+			}
+			continue
+		case existing.RequestHash != requestHash:
+			return nil, ErrIdempotencyKeyMismatch
+		case existing.Completed():
+			return existing, nil
+		case existing.LockedUntil <= now.Unix():
+			// La petición original no terminó a tiempo: se toma su reserva como con una clave expirada
+			renewed, err := s.repo.Renew(ctx, reservation, now.Unix())
+			if err != nil {
+				return nil, err
+			}
+			if renewed {
+				return nil, nil
+			}
+			continue
+		}
+
+		// La petición original sigue en curso
+		if time.Now().After(deadline) {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+// Complete guarda la respuesta de la petición, con sus encabezados, para repetirla en los reintentos
+func (s *idempotencyService) Complete(ctx context.Context, userID uint, key string, statusCode int, header http.Header, body []byte) error {
+	headers, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	return s.repo.Complete(ctx, userID, key, statusCode, string(headers), string(body))
+}
+
+// Release libera la clave para que un reintento vuelva a ejecutar la petición
+func (s *idempotencyService) Release(ctx context.Context, userID uint, key string) error {
+	return s.repo.Delete(ctx, userID, key)
+}
+
+// PurgeExpired elimina las claves cuyo tiempo de vida terminó
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now().Unix())
+}
+
+// StartIdempotencyPurger elimina periódicamente las claves de idempotencia expiradas.
+// Se ejecuta hasta que el contexto se cancela.
+func StartIdempotencyPurger(ctx context.Context, idempotency IdempotencyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := idempotency.PurgeExpired(ctx)
+		if err != nil {
+			log.Println("Error al purgar las claves de idempotencia:", err)
+		} else if purged > 0 {
+			log.Printf("Claves de idempotencia purgadas: %d\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
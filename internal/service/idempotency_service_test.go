@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Una reserva en curso cuyo plazo venció (la petición original no terminó ni liberó la clave)
+// la toma el siguiente intento; mientras el plazo está vigente el intento espera
+func TestIdempotencyBeginTakesOverExpiredLease(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name        string
+		existing    domain.IdempotencyKey
+		requestHash string
+		wantErr     error
+		wantStored  bool
+	}{
+		{"reserva vencida", domain.IdempotencyKey{RequestHash: "h", ExpiresAt: now + 3600, LockedUntil: now - 1}, "h", nil, false},
+		{"reserva anterior al plazo", domain.IdempotencyKey{RequestHash: "h", ExpiresAt: now + 3600}, "h", nil, false},
+		{"reserva vigente", domain.IdempotencyKey{RequestHash: "h", ExpiresAt: now + 3600, LockedUntil: now + 60}, "h", context.DeadlineExceeded, false},
+		{"reserva vencida de otra petición", domain.IdempotencyKey{RequestHash: "otro", ExpiresAt: now + 3600, LockedUntil: now - 1}, "h", ErrIdempotencyKeyMismatch, false},
+		{"respuesta guardada", domain.IdempotencyKey{RequestHash: "h", StatusCode: 201, ExpiresAt: now + 3600, LockedUntil: now - 1}, "h", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, db := newSyncTestService(t)
+			repo := repository.NewIdempotencyRepository(db)
+			svc := NewIdempotencyService(repo, time.Hour, time.Minute)
+
+			existing := tt.existing
+			existing.UserID, existing.Key = 1, "clave"
+			require.NoError(t, db.Create(&existing).Error)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			stored, err := svc.Begin(ctx, 1, "clave", tt.requestHash)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStored, stored != nil)
+			if tt.wantErr != nil || tt.wantStored {
+				return
+			}
+
+			// La reserva tomada es de la petición actual, con un plazo nuevo
+			reservation, err := repo.Get(context.Background(), 1, "clave")
+			require.NoError(t, err)
+			assert.Equal(t, tt.requestHash, reservation.RequestHash)
+			assert.False(t, reservation.Completed())
+			assert.Greater(t, reservation.LockedUntil, time.Now().Unix())
+		})
+	}
+}
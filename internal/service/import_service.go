@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/importer"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/pkg/position"
+)
+
+var (
+	ErrInvalidImportFile = errors.New("archivo de importación inválido")
+	ErrImportJobNotFound = errors.New("trabajo de importación no encontrado")
+	ErrImportJobDenied   = errors.New("no tienes permiso para acceder a este trabajo de importación")
+)
+
+const (
+	// importBatchSize es la cantidad de tareas que se insertan por sentencia
+	importBatchSize = 200
+	// importStaleAfter es el tiempo sin progreso tras el que un trabajo se considera interrumpido
+	importStaleAfter = 10 * time.Minute
+)
+
+// ImportService define las operaciones de importación de tareas
+type ImportService interface {
+	Import(ctx context.Context, userID uint, filename string, data []byte, opts *domain.ImportOptions) (*domain.ImportResult, *domain.ImportJob, error)
+	GetJob(ctx context.Context, id, userID uint) (*domain.ImportJob, error)
+}
+
+type importService struct {
+	tasks          repository.TaskRepository
+	jobs           repository.ImportJobRepository
+	activity       ActivityService
+	asyncThreshold int
+}
+
+// NewImportService crea una nueva instancia de ImportService.
+// Los archivos con más de asyncThreshold tareas se importan en segundo plano.
+func NewImportService(tasks repository.TaskRepository, jobs repository.ImportJobRepository, activity ActivityService, asyncThreshold int) ImportService {
+	return &importService{tasks: tasks, jobs: jobs, activity: activity, asyncThreshold: asyncThreshold}
+}
+
+// Import interpreta el archivo y crea sus tareas al final de la lista del usuario.
+// En modo dry run solo informa qué tareas se crearían y qué líneas tienen errores.
+// Si el archivo supera el umbral se crea un trabajo en segundo plano y se devuelve en lugar del resultado.
+func (s *importService) Import(ctx context.Context, userID uint, filename string, data []byte, opts *domain.ImportOptions) (*domain.ImportResult, *domain.ImportJob, error) {
+	mapping, err := importer.ParseMapping(opts.Mapping)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	parsed, err := importer.Parse(opts.Format, filename, data, importer.Options{Mapping: mapping})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	result := &domain.ImportResult{
+		Format:  parsed.Format,
+		DryRun:  opts.DryRun,
+		Total:   len(parsed.Items),
+		Errors:  make([]domain.ImportLineError, 0, len(parsed.Errors)),
+		Ignored: parsed.Ignored,
+	}
+	for _, lineErr := range parsed.Errors {
+		if len(result.Errors) == domain.MaxImportErrors {
+			result.ErrorsTruncated = true
+			break
+		}
+		result.Errors = append(result.Errors, domain.ImportLineError{Line: lineErr.Line, Message: lineErr.Message})
+	}
+
+	if opts.DryRun {
+		result.Tasks = make([]domain.ImportedTask, 0, len(parsed.Items))
+		for _, item := range parsed.Items {
+			result.Tasks = append(result.Tasks, domain.ImportedTask{Line: item.Line, Title: item.Title, Completed: item.Completed})
+		}
+		return result, nil, nil
+	}
+
+	if len(parsed.Items) > s.asyncThreshold {
+		job, err := s.startJob(ctx, userID, filename, parsed, result)
+		return nil, job, err
+	}
+
+	created, err := s.createTasks(ctx, userID, parsed.Items, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Created = created
+	s.recordImport(ctx, userID, parsed.Format, created)
+	return result, nil, nil
+}
+
+// GetJob obtiene un trabajo de importación del usuario. Un trabajo que dejó de avanzar,
+// por ejemplo porque el servidor se reinició, se marca como fallido.
+func (s *importService) GetJob(ctx context.Context, id, userID uint) (*domain.ImportJob, error) {
+	job, err := s.jobs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrImportJobNotFound
+	}
+	if job.UserID != userID {
+		return nil, ErrImportJobDenied
+	}
+
+	running := job.Status == domain.ImportStatusPending || job.Status == domain.ImportStatusRunning
+	if running && job.UpdatedAt < time.Now().Add(-importStaleAfter).Unix() {
+		job.Status = domain.ImportStatusFailed
+		job.Error = "la importación se interrumpió; ninguna tarea del archivo se creó"
+		job.FinishedAt = time.Now().Unix()
+		if err := s.jobs.Update(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// startJob registra el trabajo y lo ejecuta en segundo plano
+func (s *importService) startJob(ctx context.Context, userID uint, filename string, parsed *importer.Result, result *domain.ImportResult) (*domain.ImportJob, error) {
+	job := &domain.ImportJob{
+		UserID:   userID,
+		Format:   parsed.Format,
+		Filename: filename,
+		Status:   domain.ImportStatusPending,
+		Total:    len(parsed.Items),
+	}
+	if data, err := json.Marshal(result.Errors); err == nil {
+		job.Errors = string(data)
+	}
+	if len(parsed.Ignored) > 0 {
+		if data, err := json.Marshal(parsed.Ignored); err == nil {
+			job.Ignored = string(data)
+		}
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// El trabajo continúa aunque la petición que lo creó termine
+	jobCopy := *job
+	go s.runJob(context.Background(), &jobCopy, parsed.Items)
+	return job, nil
+}
+
+// runJob importa las tareas de un trabajo y guarda su progreso y resultado
+func (s *importService) runJob(ctx context.Context, job *domain.ImportJob, items []importer.Item) {
+	job.Status = domain.ImportStatusRunning
+	if err := s.jobs.Update(ctx, job); err != nil {
+		log.Println("Error al iniciar el trabajo de importación:", err)
+	}
+
+	created, err := s.createTasks(ctx, job.UserID, items, func(processed int) {
+		if err := s.jobs.UpdateProgress(ctx, job.ID, processed); err != nil {
+			log.Println("Error al actualizar el progreso de la importación:", err)
+		}
+	})
+
+	job.FinishedAt = time.Now().Unix()
+	if err != nil {
+		job.Status = domain.ImportStatusFailed
+		job.Processed = 0
+		job.Error = "no se importó ninguna tarea: " + err.Error()
+	} else {
+		job.Status = domain.ImportStatusCompleted
+		job.Processed = created
+		job.Created = created
+		s.recordImport(ctx, job.UserID, job.Format, created)
+	}
+	if err := s.jobs.Update(ctx, job); err != nil {
+		log.Println("Error al finalizar el trabajo de importación:", err)
+	}
+}
+
+// createTasks inserta las tareas por lotes dentro de una sola transacción: si un lote falla
+// no se crea ninguna. progress, si no es nulo, recibe la cantidad procesada tras cada lote.
+func (s *importService) createTasks(ctx context.Context, userID uint, items []importer.Item, progress func(int)) (int, error) {
+	err := s.tasks.Transaction(ctx, func(repo repository.TaskRepository) error {
+		// Bloquear la lista para que las posiciones no se mezclen con otras altas
+		if err := repo.LockList(ctx, userID); err != nil {
+			return err
+		}
+		last, err := repo.GetLastPosition(ctx, userID)
+		if err != nil {
+			return err
+		}
+		key, err := position.KeyBetween(last, "")
+		if err != nil {
+			// Claves inválidas: el reequilibrio periódico asignará las posiciones
+			key = ""
+		}
+
+		for start := 0; start < len(items); start += importBatchSize {
+			end := start + importBatchSize
+			if end > len(items) {
+				end = len(items)
+			}
+
+			batch := make([]domain.Task, 0, end-start)
+			for _, item := range items[start:end] {
+				batch = append(batch, domain.Task{Title: item.Title, Completed: item.Completed, Position: key, UserID: userID})
+				if key != "" {
+					key, _ = position.KeyBetween(key, "")
+				}
+			}
+			if err := repo.CreateBatch(ctx, batch); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(end)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// recordImport registra la importación en el historial como una sola acción.
+// Las tareas importadas no generan eventos individuales.
+func (s *importService) recordImport(ctx context.Context, userID uint, format string, created int) {
+	s.activity.Record(ctx, userID, domain.ActionTaskImported, domain.EntityUser, userID, nil, map[string]interface{}{
+		"format":  format,
+		"created": created,
+	})
+}
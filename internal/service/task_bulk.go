@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/pkg/position"
+)
+
+var (
+	ErrBulkEmpty             = errors.New("la operación masiva no contiene elementos")
+	ErrBulkTooManyItems      = errors.New("la operación masiva excede el máximo de elementos permitidos")
+	ErrBulkUnsupportedAction = errors.New("acción masiva no soportada: las tareas aún no tienen proyectos ni etiquetas")
+	ErrBulkAborted           = errors.New("la operación masiva se canceló porque uno o más elementos no son válidos")
+)
+
+// Mensajes de error por elemento de una operación masiva
+const (
+	bulkErrNotFound = "tarea no encontrada"
+	bulkErrNotRun   = "no aplicada: la operación se canceló"
+	bulkErrTitle    = "el título es requerido y debe tener entre 1 y 200 caracteres"
+)
+
+// Bulk aplica una acción sobre varias tareas del usuario dentro de una transacción.
+// En modo atomic, si algún elemento falla no se aplica ninguno y se devuelve ErrBulkAborted
+// junto con el resultado por elemento. En modo partial se aplican los elementos válidos.
+func (s *taskService) Bulk(ctx context.Context, userID uint, req *domain.BulkTaskRequest) (*domain.BulkTaskResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = domain.BulkModeAtomic
+	}
+
+	switch req.Action {
+	case domain.BulkActionCreate:
+		return s.bulkCreate(ctx, userID, req.Tasks, mode)
+	case domain.BulkActionComplete, domain.BulkActionReopen, domain.BulkActionDelete:
+		return s.bulkUpdate(ctx, userID, req.Action, req.IDs, mode)
+	default:
+		return nil, ErrBulkUnsupportedAction
+	}
+}
+
+// bulkUpdate completa, reabre o elimina varias tareas comprobando la propiedad en una sola consulta
+func (s *taskService) bulkUpdate(ctx context.Context, userID uint, action string, ids []uint, mode string) (*domain.BulkTaskResponse, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil, ErrBulkEmpty
+	}
+	if len(ids) > domain.MaxBulkItems {
+		return nil, ErrBulkTooManyItems
+	}
+
+	owned, err := s.repo.GetOwnedIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	ownedSet := make(map[uint]bool, len(owned))
+	for _, id := range owned {
+		ownedSet[id] = true
+	}
+
+	response := &domain.BulkTaskResponse{Action: action, Mode: mode}
+	aborted := mode == domain.BulkModeAtomic && len(owned) != len(ids)
+
+	var apply []uint
+	for _, id := range ids {
+		switch {
+		case !ownedSet[id]:
+			response.Results = append(response.Results, domain.BulkItemResult{ID: id, Error: bulkErrNotFound})
+		case aborted:
+			response.Results = append(response.Results, domain.BulkItemResult{ID: id, Error: bulkErrNotRun})
+		default:
+			apply = append(apply, id)
+			response.Results = append(response.Results, domain.BulkItemResult{ID: id, Success: true})
+		}
+	}
+	countResults(response)
+
+	if aborted {
+		return response, ErrBulkAborted
+	}
+	if len(apply) == 0 {
+		return response, nil
+	}
+
+	// Las tareas se leen dentro de la transacción para registrar su estado antes y después
+	before := make(map[uint]domain.TaskResponse, len(apply))
+	after := make(map[uint]domain.TaskResponse, len(apply))
+	err = s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		tasks, err := repo.GetByIDs(ctx, userID, apply)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			before[task.ID] = task.ToResponse()
+		}
+
+		if action != domain.BulkActionDelete {
+			if err := repo.UpdateStatusBatch(ctx, apply, action == domain.BulkActionComplete); err != nil {
+				return err
+			}
+			updated, err := repo.GetByIDs(ctx, userID, apply)
+			if err != nil {
+				return err
+			}
+			for _, task := range updated {
+				after[task.ID] = task.ToResponse()
+			}
+			return nil
+		}
+		if err := repo.DeleteBatch(ctx, apply); err != nil {
+			return err
+		}
+		for _, id := range apply {
+			if err := s.activity.Write(ctx, repo.Activities(), userID, domain.ActionTaskDeleted, domain.EntityTask, id, before[id], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range apply {
+		if action == domain.BulkActionDelete {
+			s.activity.Notify(ctx, userID, domain.ActionTaskDeleted, domain.EntityTask, id, nil, nil)
+			continue
+		}
+		s.activity.Record(ctx, userID, domain.ActionTaskStatusChanged, domain.EntityTask, id, before[id], after[id])
+	}
+
+	return response, nil
+}
+
+// bulkCreate crea varias tareas para el usuario
+func (s *taskService) bulkCreate(ctx context.Context, userID uint, items []domain.CreateTask, mode string) (*domain.BulkTaskResponse, error) {
+	if len(items) == 0 {
+		return nil, ErrBulkEmpty
+	}
+	if len(items) > domain.MaxBulkItems {
+		return nil, ErrBulkTooManyItems
+	}
+
+	response := &domain.BulkTaskResponse{Action: domain.BulkActionCreate, Mode: mode}
+	response.Results = make([]domain.BulkItemResult, len(items))
+
+	// Las tareas nuevas se agregan al final de la lista, en el orden recibido
+	key, err := s.nextPosition(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validar cada elemento con las mismas reglas que CreateTask
+	var tasks []domain.Task
+	var indexes []int
+	invalid := false
+	for i, item := range items {
+		index := i
+		response.Results[i].Index = &index
+		if !validTaskTitle(item.Title) {
+			response.Results[i].Error = bulkErrTitle
+			invalid = true
+			continue
+		}
+		tasks = append(tasks, domain.Task{Title: item.Title, Position: key, UserID: userID})
+		indexes = append(indexes, i)
+		if key != "" {
+			key, _ = position.KeyBetween(key, "")
+		}
+	}
+
+	if invalid && mode == domain.BulkModeAtomic {
+		for _, i := range indexes {
+			response.Results[i].Error = bulkErrNotRun
+		}
+		countResults(response)
+		return response, ErrBulkAborted
+	}
+
+	err = s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if len(tasks) == 0 {
+			return nil
+		}
+		if mode == domain.BulkModeAtomic {
+			return repo.CreateBatch(ctx, tasks)
+		}
+		// En modo partial cada tarea se crea en su propio savepoint
+		for i := range tasks {
+			err := repo.Transaction(ctx, func(inner repository.TaskRepository) error {
+				return inner.Create(ctx, &tasks[i])
+			})
+			if err != nil {
+				response.Results[indexes[i]].Error = err.Error()
+				tasks[i].ID = 0
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, task := range tasks {
+		if task.ID == 0 {
+			continue
+		}
+		taskResponse := task.ToResponse()
+		response.Results[indexes[i]].Success = true
+		response.Results[indexes[i]].Task = &taskResponse
+		s.activity.Record(ctx, userID, domain.ActionTaskCreated, domain.EntityTask, task.ID, nil, taskResponse)
+	}
+	countResults(response)
+
+	return response, nil
+}
+
+// validTaskTitle aplica las reglas de validación del título de CreateTask
+func validTaskTitle(title string) bool {
+	length := utf8.RuneCountInString(title)
+	return length >= 1 && length <= 200
+}
+
+// uniqueIDs elimina los IDs repetidos conservando el orden
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// countResults actualiza el conteo de elementos exitosos y fallidos
+func countResults(response *domain.BulkTaskResponse) {
+	response.Succeeded, response.Failed = 0, 0
+	for _, result := range response.Results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Las operaciones masivas registran en el historial el estado de cada tarea antes y después,
+// igual que las operaciones sobre una sola tarea
+func TestBulkRecordsTaskSnapshots(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		want   map[string]domain.FieldChange
+	}{
+		{"completar", domain.BulkActionComplete, map[string]domain.FieldChange{
+			"completed": {From: false, To: true},
+		}},
+		{"eliminar", domain.BulkActionDelete, map[string]domain.FieldChange{
+			"title":     {From: "Informe"},
+			"completed": {From: false},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, db := newSyncTestService(t)
+			var ids []uint
+			for i := 0; i < 2; i++ {
+				task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "Informe"})
+				require.NoError(t, err)
+				ids = append(ids, task.ID)
+			}
+
+			_, err := svc.Bulk(ctx, 1, &domain.BulkTaskRequest{Action: tt.action, IDs: ids})
+			require.NoError(t, err)
+
+			var activities []domain.Activity
+			require.NoError(t, db.Where("action <> ?", domain.ActionTaskCreated).Order("entity_id").Find(&activities).Error)
+			require.Len(t, activities, len(ids))
+			for i, activity := range activities {
+				assert.Equal(t, ids[i], activity.EntityID)
+				var changes map[string]domain.FieldChange
+				require.NoError(t, json.Unmarshal([]byte(activity.Changes), &changes), activity.Changes)
+				for field, want := range tt.want {
+					assert.Equal(t, want, changes[field], field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/pkg/position"
+)
+
+var (
+	ErrInvalidMove        = errors.New("debes indicar after_id o before_id, distintos de la tarea a mover y en orden")
+	ErrMoveAnchorNotFound = errors.New("la tarea de referencia no existe")
+)
+
+// maxPositionLength es la longitud de clave a partir de la cual se reequilibra la lista
+const maxPositionLength = 16
+
+// Move reordena una tarea entre dos tareas de referencia actualizando solo su posición.
+// La lista del usuario se bloquea durante la transacción para que los movimientos concurrentes
+// se apliquen uno tras otro sobre posiciones actualizadas.
+func (s *taskService) Move(ctx context.Context, id, userID uint, req *domain.MoveTask) (*domain.Task, error) {
+	if req.AfterID == nil && req.BeforeID == nil {
+		return nil, ErrInvalidMove
+	}
+	if (req.AfterID != nil && *req.AfterID == id) || (req.BeforeID != nil && *req.BeforeID == id) {
+		return nil, ErrInvalidMove
+	}
+
+	var task *domain.Task
+	var before domain.TaskResponse
+	err := s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if err := repo.LockList(ctx, userID); err != nil {
+			return err
+		}
+
+		var err error
+		task, err = repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return ErrTaskNotFound
+		}
+		if task.UserID != userID {
+			return ErrTaskUnauthorized
+		}
+		before = task.ToResponse()
+
+		key, err := s.moveKey(ctx, repo, task, req)
+		if errors.Is(err, position.ErrInvalidRange) {
+			// Claves vacías o repetidas: reequilibrar la lista y volver a calcular
+			if err := s.rebalanceList(ctx, repo, userID); err != nil {
+				return err
+			}
+			key, err = s.moveKey(ctx, repo, task, req)
+			if errors.Is(err, position.ErrInvalidRange) {
+				return ErrInvalidMove
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		task.Position = key
+		return repo.UpdatePosition(ctx, id, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.activity.Record(ctx, userID, domain.ActionTaskMoved, domain.EntityTask, id, before, task.ToResponse())
+	return task, nil
+}
+
+// moveKey calcula la nueva clave de la tarea a partir de las tareas de referencia
+func (s *taskService) moveKey(ctx context.Context, repo repository.TaskRepository, task *domain.Task, req *domain.MoveTask) (string, error) {
+	var lower, upper string
+
+	if req.AfterID != nil {
+		anchor, err := s.getAnchor(ctx, repo, *req.AfterID, task.UserID)
+		if err != nil {
+			return "", err
+		}
+		lower = anchor.Position
+	}
+	if req.BeforeID != nil {
+		anchor, err := s.getAnchor(ctx, repo, *req.BeforeID, task.UserID)
+		if err != nil {
+			return "", err
+		}
+		upper = anchor.Position
+	}
+
+	// Las tareas sin posición requieren reequilibrar la lista
+	if (req.AfterID != nil && lower == "") || (req.BeforeID != nil && upper == "") {
+		return "", position.ErrInvalidRange
+	}
+
+	// Con una sola referencia, el otro extremo es la tarea vecina
+	var err error
+	switch {
+	case req.BeforeID == nil:
+		upper, err = repo.GetAdjacentPosition(ctx, task.UserID, lower, task.ID, true)
+	case req.AfterID == nil:
+		lower, err = repo.GetAdjacentPosition(ctx, task.UserID, upper, task.ID, false)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return position.KeyBetween(lower, upper)
+}
+
+// getAnchor obtiene una tarea de referencia del mismo usuario
+func (s *taskService) getAnchor(ctx context.Context, repo repository.TaskRepository, id, userID uint) (*domain.Task, error) {
+	anchor, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if anchor == nil || anchor.UserID != userID {
+		return nil, ErrMoveAnchorNotFound
+	}
+	return anchor, nil
+}
+
+// RebalancePositions reasigna claves uniformes en las listas con tareas sin posición
+// o con claves demasiado largas. Devuelve la cantidad de listas reequilibradas.
+func (s *taskService) RebalancePositions(ctx context.Context) (int, error) {
+	userIDs, err := s.repo.GetUsersToRebalance(ctx, maxPositionLength)
+	if err != nil {
+		return 0, err
+	}
+
+	rebalanced := 0
+	for _, userID := range userIDs {
+		err := s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+			if err := repo.LockList(ctx, userID); err != nil {
+				return err
+			}
+			return s.rebalanceList(ctx, repo, userID)
+		})
+		if err != nil {
+			return rebalanced, err
+		}
+		rebalanced++
+	}
+	return rebalanced, nil
+}
+
+// rebalanceList reasigna claves uniformes a las tareas del usuario conservando su orden.
+// Debe ejecutarse dentro de una transacción con la lista bloqueada.
+func (s *taskService) rebalanceList(ctx context.Context, repo repository.TaskRepository, userID uint) error {
+	tasks, err := repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	keys := position.Spread(len(tasks))
+	for i, task := range tasks {
+		if task.Position == keys[i] {
+			continue
+		}
+		if err := repo.UpdatePosition(ctx, task.ID, keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextPosition calcula la clave para agregar una tarea al final de la lista del usuario.
+// Si la lista tiene claves inválidas devuelve una vacía y el reequilibrio periódico la asignará.
+func (s *taskService) nextPosition(ctx context.Context, userID uint) (string, error) {
+	last, err := s.repo.GetLastPosition(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	key, err := position.KeyBetween(last, "")
+	if err != nil {
+		return "", nil
+	}
+	return key, nil
+}
+
+// StartPositionRebalancer reequilibra periódicamente las posiciones de las listas de tareas.
+// Se ejecuta hasta que el contexto se cancela.
+func StartPositionRebalancer(ctx context.Context, tasks TaskService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		rebalanced, err := tasks.RebalancePositions(ctx)
+		if err != nil {
+			log.Println("Error al reequilibrar las posiciones de las tareas:", err)
+		} else if rebalanced > 0 {
+			log.Printf("Posiciones reequilibradas en %d listas de tareas\n", rebalanced)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
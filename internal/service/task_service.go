@@ -3,47 +3,86 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/exporter"
+	"github.com/alexroel/gin-tasks-api/internal/filter"
 	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"gorm.io/gorm"
 )
 
 var (
-	ErrTaskNotFound    = errors.New("tarea no encontrada")
+	ErrTaskNotFound     = errors.New("tarea no encontrada")
 	ErrTaskUnauthorized = errors.New("no tienes permiso para acceder a esta tarea")
+	ErrEmptySearchQuery = errors.New("la búsqueda debe contener al menos una palabra")
+	ErrUnsupportedLang  = errors.New("idioma de búsqueda no soportado")
 )
 
+// maxSearchTerms limita la cantidad de palabras de una búsqueda
+const maxSearchTerms = 10
+
 // TaskService define las operaciones de negocio para tareas
 type TaskService interface {
 	Create(ctx context.Context, userID uint, req *domain.CreateTask) (*domain.Task, error)
 	GetAll(ctx context.Context) ([]domain.Task, error)
 	GetByID(ctx context.Context, id uint) (*domain.Task, error)
 	GetByUserID(ctx context.Context, userID uint) ([]domain.Task, error)
-	Update(ctx context.Context, id, userID uint, req *domain.UpdateTask) (*domain.Task, error)
-	Delete(ctx context.Context, id, userID uint) error
-	UpdateStatus(ctx context.Context, id, userID uint, completed bool) (*domain.Task, error)
+	GetByIDs(ctx context.Context, userID uint, ids []uint) ([]domain.Task, error)
+	Update(ctx context.Context, id, userID uint, req *domain.UpdateTask, version uint) (*domain.Task, error)
+	Patch(ctx context.Context, id, userID uint, contentType string, patchData []byte, version uint) (*domain.Task, error)
+	Delete(ctx context.Context, id, userID, version uint) error
+	UpdateStatus(ctx context.Context, id, userID uint, completed bool, version uint) (*domain.Task, error)
+	GetTrash(ctx context.Context, userID uint) ([]domain.Task, error)
+	Restore(ctx context.Context, id, userID uint) (*domain.Task, error)
+	RestoreAndUpdate(ctx context.Context, id, userID uint, req *domain.UpdateTask) (*domain.Task, error)
+	Purge(ctx context.Context, id, userID uint) error
+	PurgeExpired(ctx context.Context, retention time.Duration) (int64, error)
+	Search(ctx context.Context, userID uint, query, lang string, limit int) ([]domain.TaskSearchResult, error)
+	Filter(ctx context.Context, userID uint, expression string) ([]domain.Task, error)
+	Export(ctx context.Context, userID uint, expression string, writer exporter.Writer) error
+	Bulk(ctx context.Context, userID uint, req *domain.BulkTaskRequest) (*domain.BulkTaskResponse, error)
+	Move(ctx context.Context, id, userID uint, req *domain.MoveTask) (*domain.Task, error)
+	RebalancePositions(ctx context.Context) (int, error)
+	Changes(ctx context.Context, userID uint, token string, limit int) (*domain.SyncChanges, error)
+	Sync(ctx context.Context, userID uint, req *domain.SyncRequest) (*domain.SyncResult, error)
+	SyncToken(ctx context.Context, userID uint) (string, error)
+	GetByClientID(ctx context.Context, userID uint, clientID string) (*domain.Task, error)
 }
 
 type taskService struct {
-	repo repository.TaskRepository
+	repo           repository.TaskRepository
+	activity       ActivityService
+	trashRetention time.Duration
 }
 
-// NewTaskService crea una nueva instancia de TaskService
-func NewTaskService(repo repository.TaskRepository) TaskService {
-	return &taskService{repo: repo}
+// NewTaskService crea una nueva instancia de TaskService.
+// trashRetention es el tiempo que las tareas eliminadas permanecen en la papelera.
+func NewTaskService(repo repository.TaskRepository, activity ActivityService, trashRetention time.Duration) TaskService {
+	return &taskService{repo: repo, activity: activity, trashRetention: trashRetention}
 }
 
 // Create crea una nueva tarea para un usuario
 func (s *taskService) Create(ctx context.Context, userID uint, req *domain.CreateTask) (*domain.Task, error) {
+	// Las tareas nuevas se agregan al final de la lista
+	key, err := s.nextPosition(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &domain.Task{
-		Title:  req.Title,
-		UserID: userID,
+		Title:    req.Title,
+		Position: key,
+		UserID:   userID,
 	}
 
 	if err := s.repo.Create(ctx, task); err != nil {
 		return nil, err
 	}
 
+	s.activity.Record(ctx, userID, domain.ActionTaskCreated, domain.EntityTask, task.ID, nil, task.ToResponse())
 	return task, nil
 }
 
@@ -69,8 +108,18 @@ func (s *taskService) GetByUserID(ctx context.Context, userID uint) ([]domain.Ta
 	return s.repo.GetByUserID(ctx, userID)
 }
 
-// Update actualiza una tarea existente
-func (s *taskService) Update(ctx context.Context, id, userID uint, req *domain.UpdateTask) (*domain.Task, error) {
+// GetByIDs obtiene en una sola consulta las tareas del usuario con los IDs dados.
+// Los IDs que no existen o pertenecen a otro usuario se omiten.
+func (s *taskService) GetByIDs(ctx context.Context, userID uint, ids []uint) ([]domain.Task, error) {
+	if len(ids) == 0 {
+		return []domain.Task{}, nil
+	}
+	return s.repo.GetByIDs(ctx, userID, ids)
+}
+
+// Update actualiza una tarea existente.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *taskService) Update(ctx context.Context, id, userID uint, req *domain.UpdateTask, version uint) (*domain.Task, error) {
 	// Obtener tarea existente
 	task, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -84,6 +133,11 @@ func (s *taskService) Update(ctx context.Context, id, userID uint, req *domain.U
 	if task.UserID != userID {
 		return nil, ErrTaskUnauthorized
 	}
+	if err := checkVersion(task.Version, version); err != nil {
+		return nil, err
+	}
+
+	before := task.ToResponse()
 
 	// Actualizar campos si se proporcionan
 	if req.Title != nil {
@@ -94,14 +148,53 @@ func (s *taskService) Update(ctx context.Context, id, userID uint, req *domain.U
 	}
 
 	if err := s.repo.Update(ctx, task); err != nil {
+		return nil, versionError(err, version)
+	}
+
+	s.activity.Record(ctx, userID, domain.ActionTaskUpdated, domain.EntityTask, task.ID, before, task.ToResponse())
+	return task, nil
+}
+
+// Patch aplica un JSON Merge Patch o un JSON Patch a una tarea existente.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *taskService) Patch(ctx context.Context, id, userID uint, contentType string, patchData []byte, version uint) (*domain.Task, error) {
+	// Obtener tarea existente
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	// Verificar que la tarea pertenece al usuario
+	if task.UserID != userID {
+		return nil, ErrTaskUnauthorized
+	}
+	if err := checkVersion(task.Version, version); err != nil {
 		return nil, err
 	}
 
+	var doc domain.TaskDocument
+	if err := applyPatch(task.ToDocument(), contentType, patchData, &doc); err != nil {
+		return nil, err
+	}
+
+	before := task.ToResponse()
+	task.Title = doc.Title
+	task.Completed = doc.Completed
+
+	if err := s.repo.Update(ctx, task); err != nil {
+		return nil, versionError(err, version)
+	}
+
+	s.activity.Record(ctx, userID, domain.ActionTaskUpdated, domain.EntityTask, task.ID, before, task.ToResponse())
 	return task, nil
 }
 
-// Delete elimina una tarea por su ID
-func (s *taskService) Delete(ctx context.Context, id, userID uint) error {
+// Delete elimina una tarea por su ID.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *taskService) Delete(ctx context.Context, id, userID, version uint) error {
 	// Obtener tarea existente
 	task, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -115,12 +208,27 @@ func (s *taskService) Delete(ctx context.Context, id, userID uint) error {
 	if task.UserID != userID {
 		return ErrTaskUnauthorized
 	}
+	if err := checkVersion(task.Version, version); err != nil {
+		return err
+	}
 
-	return s.repo.Delete(ctx, id)
+	err = s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if err := repo.Delete(ctx, id, task.Version); err != nil {
+			return versionError(err, version)
+		}
+		return s.activity.Write(ctx, repo.Activities(), userID, domain.ActionTaskDeleted, domain.EntityTask, id, task.ToResponse(), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.activity.Notify(ctx, userID, domain.ActionTaskDeleted, domain.EntityTask, id, nil, nil)
+	return nil
 }
 
-// UpdateStatus actualiza el estado de completado de una tarea
-func (s *taskService) UpdateStatus(ctx context.Context, id, userID uint, completed bool) (*domain.Task, error) {
+// UpdateStatus actualiza el estado de completado de una tarea.
+// version es la versión esperada por el cliente (If-Match), o 0 si no se indicó.
+func (s *taskService) UpdateStatus(ctx context.Context, id, userID uint, completed bool, version uint) (*domain.Task, error) {
 	// Obtener tarea existente
 	task, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -134,12 +242,173 @@ func (s *taskService) UpdateStatus(ctx context.Context, id, userID uint, complet
 	if task.UserID != userID {
 		return nil, ErrTaskUnauthorized
 	}
+	if err := checkVersion(task.Version, version); err != nil {
+		return nil, err
+	}
+
+	before := task.ToResponse()
 
 	// Actualizar estado
-	if err := s.repo.UpdateStatus(ctx, id, completed); err != nil {
-		return nil, err
+	if err := s.repo.UpdateStatus(ctx, id, completed, task.Version); err != nil {
+		return nil, versionError(err, version)
 	}
 
 	task.Completed = completed
+	task.Version++
+	s.activity.Record(ctx, userID, domain.ActionTaskStatusChanged, domain.EntityTask, id, before, task.ToResponse())
+	return task, nil
+}
+
+// GetTrash obtiene las tareas eliminadas de un usuario
+func (s *taskService) GetTrash(ctx context.Context, userID uint) ([]domain.Task, error) {
+	return s.repo.GetDeletedByUserID(ctx, userID)
+}
+
+// Restore recupera una tarea de la papelera
+func (s *taskService) Restore(ctx context.Context, id, userID uint) (*domain.Task, error) {
+	task, err := s.getDeletedTask(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Restore incrementa la versión en la base de datos
+	task.Version++
+	task.DeletedAt = gorm.DeletedAt{}
+	s.activity.Record(ctx, userID, domain.ActionTaskRestored, domain.EntityTask, id, nil, task.ToResponse())
 	return task, nil
 }
+
+// RestoreAndUpdate saca una tarea de la papelera y le aplica los cambios en una sola transacción:
+// si la actualización falla, la tarea sigue eliminada
+func (s *taskService) RestoreAndUpdate(ctx context.Context, id, userID uint, req *domain.UpdateTask) (*domain.Task, error) {
+	task, err := s.getDeletedTask(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var before domain.TaskResponse
+	err = s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if err := repo.Restore(ctx, id); err != nil {
+			return err
+		}
+		// Restore incrementa la versión en la base de datos
+		task.Version++
+		task.DeletedAt = gorm.DeletedAt{}
+		before = task.ToResponse()
+
+		if req.Title != nil {
+			task.Title = *req.Title
+		}
+		if req.Completed != nil {
+			task.Completed = *req.Completed
+		}
+		return repo.Update(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.activity.Record(ctx, userID, domain.ActionTaskRestored, domain.EntityTask, id, nil, before)
+	s.activity.Record(ctx, userID, domain.ActionTaskUpdated, domain.EntityTask, id, before, task.ToResponse())
+	return task, nil
+}
+
+// Purge elimina definitivamente una tarea de la papelera
+func (s *taskService) Purge(ctx context.Context, id, userID uint) error {
+	task, err := s.getDeletedTask(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	// Sync obtiene las tareas eliminadas definitivamente del historial: el registro no puede perderse
+	err = s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if err := repo.HardDelete(ctx, id); err != nil {
+			return err
+		}
+		return s.activity.Write(ctx, repo.Activities(), userID, domain.ActionTaskPurged, domain.EntityTask, id, task.ToResponse(), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.activity.Notify(ctx, userID, domain.ActionTaskPurged, domain.EntityTask, id, nil, nil)
+	return nil
+}
+
+// PurgeExpired elimina definitivamente las tareas que llevan en la papelera más tiempo que la retención
+func (s *taskService) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+}
+
+// getDeletedTask obtiene una tarea de la papelera verificando que pertenece al usuario
+func (s *taskService) getDeletedTask(ctx context.Context, id, userID uint) (*domain.Task, error) {
+	task, err := s.repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if task.UserID != userID {
+		return nil, ErrTaskUnauthorized
+	}
+	return task, nil
+}
+
+// Search busca tareas del usuario por texto, ordenadas por relevancia
+func (s *taskService) Search(ctx context.Context, userID uint, query, lang string, limit int) ([]domain.TaskSearchResult, error) {
+	if lang != "" && lang != domain.SearchLangSpanish && lang != domain.SearchLangEnglish {
+		return nil, ErrUnsupportedLang
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+
+	return s.repo.Search(ctx, userID, terms, lang, limit)
+}
+
+// searchTerms divide la búsqueda en palabras en minúsculas, descartando signos y operadores
+func searchTerms(query string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(fields) > maxSearchTerms {
+		fields = fields[:maxSearchTerms]
+	}
+	return fields
+}
+
+// Filter obtiene las tareas de un usuario que cumplen una expresión de filtro
+func (s *taskService) Filter(ctx context.Context, userID uint, expression string) ([]domain.Task, error) {
+	query, err := filter.Parse(expression, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByFilter(ctx, userID, query)
+}
+
+// Export escribe las tareas del usuario que cumplen la expresión de filtro (todas si está vacía)
+// a medida que se leen de la base de datos. La expresión se valida antes de escribir nada.
+func (s *taskService) Export(ctx context.Context, userID uint, expression string, writer exporter.Writer) error {
+	var query *filter.Query
+	if expression != "" {
+		var err error
+		if query, err = filter.Parse(expression, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Begin(); err != nil {
+		return err
+	}
+	if err := s.repo.StreamByFilter(ctx, userID, query, writer.Write); err != nil {
+		return err
+	}
+	return writer.End()
+}
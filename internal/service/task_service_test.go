@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// La restauración registra en el historial la tarea restaurada y devuelve su versión nueva
+func TestRestoreRecordsTask(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newSyncTestService(t)
+	task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "Informe"})
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(ctx, task.ID, 1, 0))
+
+	restored, err := svc.Restore(ctx, task.ID, 1)
+	require.NoError(t, err)
+	stored, err := svc.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Version, restored.Version)
+
+	var activity domain.Activity
+	require.NoError(t, db.Where("action = ?", domain.ActionTaskRestored).Take(&activity).Error)
+	var changes map[string]domain.FieldChange
+	require.NoError(t, json.Unmarshal([]byte(activity.Changes), &changes), activity.Changes)
+	assert.Equal(t, domain.FieldChange{To: "Informe"}, changes["title"])
+	assert.Equal(t, domain.FieldChange{To: false}, changes["completed"])
+}
+
+func TestRestoreAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newSyncTestService(t)
+	task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "Informe"})
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(ctx, task.ID, 1, 0))
+
+	restored, err := svc.RestoreAndUpdate(ctx, task.ID, 1, &domain.UpdateTask{Title: strPtr("Informe final"), Completed: boolPtr(true)})
+	require.NoError(t, err)
+	assert.Equal(t, "Informe final", restored.Title)
+	assert.True(t, restored.Completed)
+
+	stored, err := svc.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Informe final", stored.Title)
+	assert.True(t, stored.Completed)
+	assert.Equal(t, restored.Version, stored.Version)
+
+	trash, err := svc.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+
+	_, err = svc.RestoreAndUpdate(ctx, task.ID, 1, &domain.UpdateTask{Title: strPtr("Otra vez")})
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+// Si la actualización falla, la restauración se revierte y la tarea sigue en la papelera
+func TestRestoreAndUpdateRollsBack(t *testing.T) {
+	ctx := context.Background()
+	svc, db := newSyncTestService(t)
+	task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "Informe"})
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(ctx, task.ID, 1, 0))
+
+	errUpdate := errors.New("fallo de escritura")
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_task_update", func(tx *gorm.DB) {
+		// Restore actualiza con un mapa; Update guarda la tarea completa
+		if _, ok := tx.Statement.Dest.(*domain.Task); ok {
+			_ = tx.AddError(errUpdate)
+		}
+	}))
+
+	_, err = svc.RestoreAndUpdate(ctx, task.ID, 1, &domain.UpdateTask{Title: strPtr("Informe final")})
+	require.ErrorIs(t, err, errUpdate)
+
+	trash, err := svc.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, "Informe", trash[0].Title)
+	assert.Equal(t, task.Version+1, trash[0].Version)
+}
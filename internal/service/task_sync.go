@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+var (
+	ErrInvalidSyncToken     = errors.New("el token de sincronización no es válido")
+	ErrSyncTooManyMutations = errors.New("la sincronización excede el máximo de mutaciones permitidas")
+)
+
+// Mensajes de error por mutación de una sincronización
+const (
+	syncErrOp        = "operación no soportada: debe ser create, update o delete"
+	syncErrClientID  = "client_id es requerido y debe tener como máximo 64 caracteres"
+	syncErrTarget    = "se requiere id o client_id para identificar la tarea"
+	syncErrDeleted   = "la tarea fue eliminada"
+	syncErrConflict  = "la tarea se modificó en el servidor después del cambio del cliente"
+	syncErrNoChanges = "la mutación no contiene campos"
+)
+
+// syncSafetyWindow es el margen durante el que el token no avanza, para no perder cambios
+// de transacciones que confirman tarde o de réplicas con el reloj desfasado.
+// Los cambios dentro de este margen pueden volver a enviarse en la siguiente sincronización.
+const syncSafetyWindow = 5 * time.Second
+
+// syncCursor es la posición en el orden (updated_at, id) hasta la que el cliente recibió cambios
+type syncCursor struct {
+	updatedAt int64
+	id        uint
+}
+
+// before indica si el cursor es anterior a other
+func (c syncCursor) before(other syncCursor) bool {
+	return c.updatedAt < other.updatedAt || (c.updatedAt == other.updatedAt && c.id < other.id)
+}
+
+// encodeSyncToken convierte el cursor en un token opaco para el cliente
+func encodeSyncToken(c syncCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("v1.%d.%d", c.updatedAt, c.id)))
+}
+
+// decodeSyncToken obtiene el cursor de un token de sincronización
+func decodeSyncToken(token string) (syncCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return syncCursor{}, ErrInvalidSyncToken
+	}
+	parts := strings.Split(string(data), ".")
+	if len(parts) != 3 || parts[0] != "v1" {
+		return syncCursor{}, ErrInvalidSyncToken
+	}
+	updatedAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || updatedAt < 0 {
+		return syncCursor{}, ErrInvalidSyncToken
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return syncCursor{}, ErrInvalidSyncToken
+	}
+	return syncCursor{updatedAt: updatedAt, id: uint(id)}, nil
+}
+
+// Changes obtiene las tareas del usuario creadas, modificadas o eliminadas después del token.
+// Sin token, o con un token más antiguo que la retención de la papelera (cuyos tombstones
+// pueden haberse purgado), devuelve todas las tareas con Reset activado.
+func (s *taskService) Changes(ctx context.Context, userID uint, token string, limit int) (*domain.SyncChanges, error) {
+	now := time.Now()
+
+	var cursor syncCursor
+	reset := token == ""
+	if !reset {
+		var err error
+		if cursor, err = decodeSyncToken(token); err != nil {
+			return nil, err
+		}
+		if cursor.updatedAt < now.Add(-s.trashRetention).Unix() {
+			reset = true
+			cursor = syncCursor{}
+		}
+	}
+
+	tasks, err := s.repo.GetChanges(ctx, userID, cursor.updatedAt, cursor.id, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := len(tasks) > limit
+	if hasMore {
+		tasks = tasks[:limit]
+	}
+
+	response := &domain.SyncChanges{Changes: []domain.SyncChange{}, HasMore: hasMore, Reset: reset}
+	next := cursor
+	for i := range tasks {
+		task := &tasks[i]
+		next = syncCursor{updatedAt: task.UpdatedAt, id: task.ID}
+
+		change := domain.SyncChange{ID: task.ID, ClientID: task.ClientID, Deleted: task.DeletedAt.Valid}
+		if change.Deleted {
+			// Un cliente que reconstruye su copia no necesita los tombstones
+			if reset {
+				continue
+			}
+		} else {
+			taskResponse := task.ToResponse()
+			change.Task = &taskResponse
+		}
+		response.Changes = append(response.Changes, change)
+	}
+
+	// Las tareas eliminadas definitivamente ya no tienen fila; se obtienen del historial
+	if !reset {
+		purged, err := s.activity.GetPurgedTaskIDs(ctx, userID, cursor.updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range purged {
+			response.Changes = append(response.Changes, domain.SyncChange{ID: id, Deleted: true})
+		}
+	}
+
+	// Al llegar al final, el token no avanza más allá del margen de seguridad
+	if !hasMore {
+		horizon := syncCursor{updatedAt: now.Add(-syncSafetyWindow).Unix()}
+		if horizon.before(next) {
+			next = horizon
+		}
+		if next.before(cursor) {
+			next = cursor
+		}
+	}
+	response.Token = encodeSyncToken(next)
+
+	return response, nil
+}
+
+// SyncToken devuelve el token que corresponde al último cambio de las tareas del usuario sin
+// leerlas. Cambia cada vez que se crea, modifica o elimina una tarea, por lo que sirve para
+// saber si hay cambios antes de pedirlos.
+func (s *taskService) SyncToken(ctx context.Context, userID uint) (string, error) {
+	last, err := s.repo.GetLastChange(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var cursor syncCursor
+	if last != nil {
+		cursor = syncCursor{updatedAt: last.UpdatedAt, id: last.ID}
+	}
+	// Igual que en Changes, el token no avanza más allá del margen de seguridad
+	horizon := syncCursor{updatedAt: time.Now().Add(-syncSafetyWindow).Unix()}
+	if horizon.before(cursor) {
+		cursor = horizon
+	}
+	return encodeSyncToken(cursor), nil
+}
+
+// GetByClientID obtiene una tarea del usuario, incluso eliminada, por el ID que le asignó el cliente al crearla
+func (s *taskService) GetByClientID(ctx context.Context, userID uint, clientID string) (*domain.Task, error) {
+	task, err := s.repo.GetByClientID(ctx, userID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// Sync aplica en orden las mutaciones hechas por el cliente sin conexión y reporta el resultado de cada una.
+// Cada mutación se aplica de forma independiente; una mutación rechazada no impide aplicar las demás.
+//
+// Política de conflictos (last-writer-wins por campo):
+//   - Si base_version coincide con la versión actual, se aplican todos los campos.
+//   - Si no, cada campo se resuelve por separado: un campo que el servidor no modificó desde Base
+//     se aplica; un campo que cambió en ambos lados lo gana la modificación más reciente según
+//     updated_at, y el servidor gana los empates.
+//   - updated_at del cliente se limita a la hora del servidor para que un reloj adelantado no gane siempre.
+//   - Una eliminación se aplica si base_version coincide o si es más reciente que la última modificación.
+func (s *taskService) Sync(ctx context.Context, userID uint, req *domain.SyncRequest) (*domain.SyncResult, error) {
+	if len(req.Mutations) > domain.MaxSyncMutations {
+		return nil, ErrSyncTooManyMutations
+	}
+
+	now := time.Now().Unix()
+	result := &domain.SyncResult{Results: make([]domain.SyncMutationResult, 0, len(req.Mutations))}
+	for i := range req.Mutations {
+		mutation := &req.Mutations[i]
+		if mutation.UpdatedAt > now {
+			mutation.UpdatedAt = now
+		}
+
+		var item domain.SyncMutationResult
+		switch mutation.Op {
+		case domain.SyncOpCreate:
+			item = s.syncCreate(ctx, userID, mutation)
+		case domain.SyncOpUpdate:
+			item = s.syncUpdate(ctx, userID, mutation)
+		case domain.SyncOpDelete:
+			item = s.syncDelete(ctx, userID, mutation)
+		default:
+			item = syncRejected(syncErrOp)
+		}
+
+		item.Index = i
+		item.ClientID = mutation.ClientID
+		if item.Task != nil {
+			item.ID = item.Task.ID
+		} else if item.ID == 0 {
+			item.ID = mutation.ID
+		}
+
+		switch item.Status {
+		case domain.SyncStatusApplied:
+			result.Applied++
+		case domain.SyncStatusMerged:
+			result.Merged++
+		default:
+			result.Rejected++
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return result, nil
+}
+
+// syncCreate crea una tarea generada por el cliente. Reenviar la misma mutación no crea duplicados.
+func (s *taskService) syncCreate(ctx context.Context, userID uint, mutation *domain.SyncMutation) domain.SyncMutationResult {
+	if mutation.ClientID == "" || len(mutation.ClientID) > 64 {
+		return syncRejected(syncErrClientID)
+	}
+	if mutation.Fields.Title == nil || !validTaskTitle(*mutation.Fields.Title) {
+		return syncRejected(bulkErrTitle)
+	}
+
+	existing, err := s.repo.GetByClientID(ctx, userID, mutation.ClientID)
+	if err != nil {
+		return syncRejected(err.Error())
+	}
+	if existing != nil {
+		return syncExisting(existing)
+	}
+
+	key, err := s.nextPosition(ctx, userID)
+	if err != nil {
+		return syncRejected(err.Error())
+	}
+
+	clientID := mutation.ClientID
+	task := &domain.Task{
+		Title:    *mutation.Fields.Title,
+		Position: key,
+		UserID:   userID,
+		ClientID: &clientID,
+	}
+	if mutation.Fields.Completed != nil {
+		task.Completed = *mutation.Fields.Completed
+	}
+
+	if err := s.repo.Create(ctx, task); err != nil {
+		// Otra petición con el mismo client_id pudo crearla al mismo tiempo
+		if existing, _ := s.repo.GetByClientID(ctx, userID, mutation.ClientID); existing != nil {
+			return syncExisting(existing)
+		}
+		return syncRejected(err.Error())
+	}
+
+	s.activity.Record(ctx, userID, domain.ActionTaskCreated, domain.EntityTask, task.ID, nil, task.ToResponse())
+	return syncApplied(domain.SyncStatusApplied, task)
+}
+
+// syncUpdate aplica los campos modificados por el cliente resolviendo los conflictos campo por campo
+func (s *taskService) syncUpdate(ctx context.Context, userID uint, mutation *domain.SyncMutation) domain.SyncMutationResult {
+	fields := mutation.Fields
+	if fields.Title == nil && fields.Completed == nil {
+		return syncRejected(syncErrNoChanges)
+	}
+	if fields.Title != nil && !validTaskTitle(*fields.Title) {
+		return syncRejected(bulkErrTitle)
+	}
+
+	task, rejection := s.syncTarget(ctx, userID, mutation)
+	if rejection != nil {
+		return *rejection
+	}
+	if task.DeletedAt.Valid {
+		item := syncRejected(syncErrDeleted)
+		item.ID = task.ID
+		return item
+	}
+
+	// Sin conflicto si el cliente partió de la versión actual; si no, gana el cambio más reciente
+	clientWins := mutation.BaseVersion == task.Version || mutation.UpdatedAt > task.UpdatedAt
+	var base domain.SyncFields
+	if mutation.Base != nil {
+		base = *mutation.Base
+	}
+
+	before := task.ToResponse()
+	changed := false
+	var rejected []string
+
+	if fields.Title != nil && *fields.Title != task.Title {
+		if clientWins || (base.Title != nil && *base.Title == task.Title) {
+			task.Title = *fields.Title
+			changed = true
+		} else {
+			rejected = append(rejected, "title")
+		}
+	}
+	if fields.Completed != nil && *fields.Completed != task.Completed {
+		if clientWins || (base.Completed != nil && *base.Completed == task.Completed) {
+			task.Completed = *fields.Completed
+			changed = true
+		} else {
+			rejected = append(rejected, "completed")
+		}
+	}
+
+	if changed {
+		if err := s.repo.Update(ctx, task); err != nil {
+			item := syncRejected(versionError(err, 0).Error())
+			item.ID = task.ID
+			return item
+		}
+		s.activity.Record(ctx, userID, domain.ActionTaskUpdated, domain.EntityTask, task.ID, before, task.ToResponse())
+	}
+
+	switch {
+	case len(rejected) == 0:
+		return syncApplied(domain.SyncStatusApplied, task)
+	case changed:
+		item := syncApplied(domain.SyncStatusMerged, task)
+		item.RejectedFields = rejected
+		return item
+	default:
+		item := syncApplied(domain.SyncStatusRejected, task)
+		item.RejectedFields = rejected
+		item.Error = syncErrConflict
+		return item
+	}
+}
+
+// syncDelete elimina una tarea salvo que se haya modificado en el servidor después de eliminarla en el cliente
+func (s *taskService) syncDelete(ctx context.Context, userID uint, mutation *domain.SyncMutation) domain.SyncMutationResult {
+	task, rejection := s.syncTarget(ctx, userID, mutation)
+	if rejection != nil {
+		// Una tarea que ya no existe se considera eliminada
+		if rejection.Error == bulkErrNotFound {
+			return domain.SyncMutationResult{Status: domain.SyncStatusApplied}
+		}
+		return *rejection
+	}
+	if task.DeletedAt.Valid {
+		return domain.SyncMutationResult{ID: task.ID, Status: domain.SyncStatusApplied}
+	}
+
+	if mutation.BaseVersion != task.Version && mutation.UpdatedAt <= task.UpdatedAt {
+		item := syncApplied(domain.SyncStatusRejected, task)
+		item.Error = syncErrConflict
+		return item
+	}
+
+	err := s.repo.Transaction(ctx, func(repo repository.TaskRepository) error {
+		if err := repo.Delete(ctx, task.ID, task.Version); err != nil {
+			return err
+		}
+		return s.activity.Write(ctx, repo.Activities(), userID, domain.ActionTaskDeleted, domain.EntityTask, task.ID, task.ToResponse(), nil)
+	})
+	if err != nil {
+		item := syncRejected(versionError(err, 0).Error())
+		item.ID = task.ID
+		return item
+	}
+
+	s.activity.Notify(ctx, userID, domain.ActionTaskDeleted, domain.EntityTask, task.ID, nil, nil)
+	return domain.SyncMutationResult{ID: task.ID, Status: domain.SyncStatusApplied}
+}
+
+// syncTarget obtiene la tarea de una mutación, incluso eliminada, por ID o por client_id
+func (s *taskService) syncTarget(ctx context.Context, userID uint, mutation *domain.SyncMutation) (*domain.Task, *domain.SyncMutationResult) {
+	var task *domain.Task
+	var err error
+	switch {
+	case mutation.ID != 0:
+		task, err = s.repo.GetByID(ctx, mutation.ID)
+		if err == nil && task == nil {
+			task, err = s.repo.GetDeletedByID(ctx, mutation.ID)
+		}
+	case mutation.ClientID != "":
+		task, err = s.repo.GetByClientID(ctx, userID, mutation.ClientID)
+	default:
+		rejection := syncRejected(syncErrTarget)
+		return nil, &rejection
+	}
+
+	if err != nil {
+		rejection := syncRejected(err.Error())
+		return nil, &rejection
+	}
+	if task == nil {
+		rejection := syncRejected(bulkErrNotFound)
+		return nil, &rejection
+	}
+	if task.UserID != userID {
+		rejection := syncRejected(ErrTaskUnauthorized.Error())
+		return nil, &rejection
+	}
+	return task, nil
+}
+
+// syncExisting devuelve el resultado de una creación repetida con el estado actual de la tarea
+func syncExisting(task *domain.Task) domain.SyncMutationResult {
+	if task.DeletedAt.Valid {
+		item := syncRejected(syncErrDeleted)
+		item.ID = task.ID
+		return item
+	}
+	return syncApplied(domain.SyncStatusApplied, task)
+}
+
+// syncApplied construye el resultado de una mutación con el estado actual de la tarea
+func syncApplied(status string, task *domain.Task) domain.SyncMutationResult {
+	taskResponse := task.ToResponse()
+	return domain.SyncMutationResult{Status: status, Task: &taskResponse}
+}
+
+// syncRejected construye el resultado de una mutación rechazada
+func syncRejected(message string) domain.SyncMutationResult {
+	return domain.SyncMutationResult{Status: domain.SyncStatusRejected, Error: message}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newSyncTestService crea un TaskService sobre una base SQLite temporal con un usuario
+func newSyncTestService(t *testing.T) (TaskService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t, nil)
+	require.NoError(t, db.Create(&domain.User{FullName: "Ana", Email: "ana@example.com", Password: "x"}).Error)
+
+	taskRepo := repository.NewTaskRepository(db)
+	activity := NewActivityService(repository.NewActivityRepository(db), taskRepo)
+	return NewTaskService(taskRepo, activity, time.Hour), db
+}
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+
+// En todos los casos la tarea se creó como "original" (versión 1) y el servidor cambió después
+// su título a "servidor" (versión 2) en serverAt
+func TestSyncUpdateConflicts(t *testing.T) {
+	now := time.Now().Unix()
+	serverAt := now - 1000
+	base := &domain.SyncFields{Title: strPtr("original"), Completed: boolPtr(false)}
+
+	tests := []struct {
+		name          string
+		serverAt      int64
+		mutation      domain.SyncMutation
+		wantStatus    string
+		wantRejected  []string
+		wantTitle     string
+		wantCompleted bool
+	}{
+		{"versión actual", serverAt, domain.SyncMutation{BaseVersion: 2, UpdatedAt: now - 2000,
+			Fields: domain.SyncFields{Title: strPtr("cliente")}},
+			domain.SyncStatusApplied, nil, "cliente", false},
+		{"campo que el servidor no modificó", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000, Base: base,
+			Fields: domain.SyncFields{Completed: boolPtr(true)}},
+			domain.SyncStatusApplied, nil, "servidor", true},
+		{"mismo campo, gana el servidor por ser más reciente", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("cliente")}},
+			domain.SyncStatusRejected, []string{"title"}, "servidor", false},
+		{"mismo campo, gana el cliente por ser más reciente", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 10, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("cliente")}},
+			domain.SyncStatusApplied, nil, "cliente", false},
+		{"fusión por campo", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("cliente"), Completed: boolPtr(true)}},
+			domain.SyncStatusMerged, []string{"title"}, "servidor", true},
+		{"sin base no se puede fusionar", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000,
+			Fields: domain.SyncFields{Completed: boolPtr(true)}},
+			domain.SyncStatusRejected, []string{"completed"}, "servidor", false},
+		{"empate, gana el servidor", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: serverAt, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("cliente")}},
+			domain.SyncStatusRejected, []string{"title"}, "servidor", false},
+		{"un reloj adelantado se limita a la hora del servidor", now + 600, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now + 3600, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("cliente")}},
+			domain.SyncStatusRejected, []string{"title"}, "servidor", false},
+		{"valor igual al del servidor", serverAt, domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000, Base: base,
+			Fields: domain.SyncFields{Title: strPtr("servidor")}},
+			domain.SyncStatusApplied, nil, "servidor", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, db := newSyncTestService(t)
+			task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "original"})
+			require.NoError(t, err)
+			_, err = svc.Update(ctx, task.ID, 1, &domain.UpdateTask{Title: strPtr("servidor")}, 0)
+			require.NoError(t, err)
+			require.NoError(t, db.Model(&domain.Task{}).Where("id = ?", task.ID).UpdateColumn("updated_at", tt.serverAt).Error)
+
+			mutation := tt.mutation
+			mutation.Op = domain.SyncOpUpdate
+			mutation.ID = task.ID
+			result, err := svc.Sync(ctx, 1, &domain.SyncRequest{Mutations: []domain.SyncMutation{mutation}})
+			require.NoError(t, err)
+			require.Len(t, result.Results, 1)
+
+			item := result.Results[0]
+			assert.Equal(t, tt.wantStatus, item.Status, item.Error)
+			assert.Equal(t, tt.wantRejected, item.RejectedFields)
+			stored, err := svc.GetByID(ctx, task.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, stored.Title)
+			assert.Equal(t, tt.wantCompleted, stored.Completed)
+		})
+	}
+}
+
+func TestSyncDeleteConflicts(t *testing.T) {
+	now := time.Now().Unix()
+	serverAt := now - 1000
+
+	tests := []struct {
+		name        string
+		mutation    domain.SyncMutation
+		wantStatus  string
+		wantDeleted bool
+	}{
+		{"versión actual", domain.SyncMutation{BaseVersion: 2, UpdatedAt: now - 2000}, domain.SyncStatusApplied, true},
+		{"modificada en el servidor después", domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 2000}, domain.SyncStatusRejected, false},
+		{"eliminada en el cliente después", domain.SyncMutation{BaseVersion: 1, UpdatedAt: now - 10}, domain.SyncStatusApplied, true},
+		{"tarea inexistente", domain.SyncMutation{ID: 999, UpdatedAt: now}, domain.SyncStatusApplied, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, db := newSyncTestService(t)
+			task, err := svc.Create(ctx, 1, &domain.CreateTask{Title: "original"})
+			require.NoError(t, err)
+			_, err = svc.Update(ctx, task.ID, 1, &domain.UpdateTask{Title: strPtr("servidor")}, 0)
+			require.NoError(t, err)
+			require.NoError(t, db.Model(&domain.Task{}).Where("id = ?", task.ID).UpdateColumn("updated_at", serverAt).Error)
+
+			mutation := tt.mutation
+			mutation.Op = domain.SyncOpDelete
+			if mutation.ID == 0 {
+				mutation.ID = task.ID
+			}
+			result, err := svc.Sync(ctx, 1, &domain.SyncRequest{Mutations: []domain.SyncMutation{mutation}})
+			require.NoError(t, err)
+			require.Len(t, result.Results, 1)
+			assert.Equal(t, tt.wantStatus, result.Results[0].Status, result.Results[0].Error)
+
+			_, err = svc.GetByID(ctx, task.ID)
+			if tt.wantDeleted {
+				assert.ErrorIs(t, err, ErrTaskNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartTrashPurger elimina periódicamente las tareas que superaron el periodo de retención
+// de la papelera. Se ejecuta hasta que el contexto se cancela.
+func StartTrashPurger(ctx context.Context, tasks TaskService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := tasks.PurgeExpired(ctx, retention)
+		if err != nil {
+			log.Println("Error al purgar la papelera:", err)
+		} else if purged > 0 {
+			log.Printf("Papelera purgada: %d tareas eliminadas definitivamente\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+)
+
+var (
+	ErrPreconditionFailed     = errors.New("el recurso fue modificado: la versión indicada en If-Match no coincide")
+	ErrConcurrentModification = errors.New("el recurso fue modificado por otra petición, vuelve a intentarlo")
+)
+
+// checkVersion verifica la versión esperada por el cliente (If-Match).
+// Una versión esperada igual a 0 indica que el cliente no envió If-Match.
+func checkVersion(current, expected uint) error {
+	if expected != 0 && current != expected {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+// versionError traduce un conflicto de versión del repositorio al error correspondiente:
+// 412 si el cliente envió If-Match, o 409 si la carrera ocurrió entre la lectura y la escritura.
+func versionError(err error, expected uint) error {
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		return err
+	}
+	if expected != 0 {
+		return ErrPreconditionFailed
+	}
+	return ErrConcurrentModification
+}
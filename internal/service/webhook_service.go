@@ -0,0 +1,582 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/domain"
+	"github.com/alexroel/gin-tasks-api/internal/repository"
+	"github.com/alexroel/gin-tasks-api/pkg/webhook"
+)
+
+var (
+	ErrWebhookNotFound     = errors.New("webhook no encontrado")
+	ErrWebhookUnauthorized = errors.New("no tienes permiso para acceder a este webhook")
+	ErrWebhookInactive     = errors.New("el webhook está desactivado: reactívalo antes de reenviar entregas")
+	ErrInvalidWebhookURL   = errors.New("la URL del webhook debe usar http o https")
+	ErrWebhookAddress      = errors.New("la URL del webhook apunta a una dirección interna o reservada")
+	ErrInvalidWebhookEvent = errors.New("evento de webhook desconocido")
+	ErrDeliveryNotFound    = errors.New("entrega no encontrada")
+)
+
+const (
+	// webhookTimeout es el tiempo máximo de espera de cada intento de entrega
+	webhookTimeout = 10 * time.Second
+	// webhookBaseBackoff es la espera tras el primer intento fallido; se duplica en cada reintento
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxBackoff limita la espera entre reintentos
+	webhookMaxBackoff = 6 * time.Hour
+	// webhookBatchSize es la cantidad de entregas que se reservan en cada ciclo del dispatcher
+	webhookBatchSize = 50
+	// webhookWorkers es la cantidad de entregas que se envían en paralelo
+	webhookWorkers = 8
+	// webhookLease es el tiempo durante el cual una entrega reservada no se vuelve a reservar;
+	// debe cubrir el envío de un lote completo
+	webhookLease = 2 * time.Minute
+)
+
+// webhookBlockedPrefixes son rangos reservados que no cubren los métodos de netip.Addr
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "esta red"
+	netip.MustParsePrefix("100.64.0.0/10"), // NAT de operador (RFC 6598)
+	netip.MustParsePrefix("192.0.0.0/24"),  // asignaciones de protocolo de la IETF
+	netip.MustParsePrefix("198.18.0.0/15"), // pruebas de rendimiento (RFC 2544)
+	netip.MustParsePrefix("240.0.0.0/4"),   // reservado, incluye 255.255.255.255
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, traduce a direcciones IPv4
+	netip.MustParsePrefix("2001:db8::/32"), // documentación
+}
+
+// WebhookService define las operaciones de negocio para webhooks salientes
+type WebhookService interface {
+	TaskNotifier
+	Create(ctx context.Context, userID uint, req *domain.CreateWebhook) (*domain.Webhook, error)
+	GetByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.Webhook, error)
+	Update(ctx context.Context, id, userID uint, req *domain.UpdateWebhook) (*domain.Webhook, error)
+	Delete(ctx context.Context, id, userID uint) error
+	GetDeliveries(ctx context.Context, id, userID uint, page, limit int) (*domain.DeliveryPage, error)
+	Redeliver(ctx context.Context, id, deliveryID, userID uint) (*domain.WebhookDelivery, error)
+	DispatchDue(ctx context.Context) (int, error)
+}
+
+type webhookService struct {
+	repo         repository.WebhookRepository
+	client       *http.Client
+	maxAttempts  int
+	disableAfter int
+}
+
+// NewWebhookService crea una nueva instancia de WebhookService.
+// maxAttempts es la cantidad de intentos de cada entrega; disableAfter, la cantidad de intentos
+// fallidos consecutivos tras la cual el webhook se desactiva automáticamente.
+func NewWebhookService(repo repository.WebhookRepository, maxAttempts, disableAfter int) WebhookService {
+	return &webhookService{repo: repo, client: newWebhookClient(), maxAttempts: maxAttempts, disableAfter: disableAfter}
+}
+
+// newWebhookClient crea el cliente HTTP de las entregas. Solo se conecta a direcciones públicas:
+// la comprobación se hace al conectar, con la IP ya resuelta, así que un nombre que resuelve
+// a una red interna (o que cambia de IP tras registrarse) también se rechaza.
+// No usa proxy, porque la conexión al proxy ocultaría el destino real.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublicWebhookAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrWebhookAddress, addr)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		// Las redirecciones no se siguen: cuentan como una respuesta fallida
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicWebhookAddr indica si una dirección es válida como destino de un webhook: no puede ser
+// de loopback, privada (RFC 1918 o ULA), de enlace local (incluida 169.254.169.254),
+// no especificada, multicast ni de otro rango reservado
+func isPublicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Create registra un webhook y genera su secreto de firma
+func (s *webhookService) Create(ctx context.Context, userID uint, req *domain.CreateWebhook) (*domain.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &domain.Webhook{
+		URL:    req.URL,
+		Secret: secret,
+		Events: events,
+		Active: true,
+		UserID: userID,
+	}
+	if err := s.repo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// GetByUserID obtiene los webhooks de un usuario
+func (s *webhookService) GetByUserID(ctx context.Context, userID uint) ([]domain.Webhook, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// GetByID obtiene un webhook verificando que pertenece al usuario
+func (s *webhookService) GetByID(ctx context.Context, id, userID uint) (*domain.Webhook, error) {
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	if hook.UserID != userID {
+		return nil, ErrWebhookUnauthorized
+	}
+	return hook, nil
+}
+
+// Update actualiza un webhook. Reactivarlo reinicia su contador de fallos.
+func (s *webhookService) Update(ctx context.Context, id, userID uint, req *domain.UpdateWebhook) (*domain.Webhook, error) {
+	hook, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		hook.Events = events
+	}
+	activeChanged := req.Active != nil && *req.Active != hook.Active
+	if activeChanged {
+		hook.Active = *req.Active
+	}
+
+	if err := s.repo.Update(ctx, hook, activeChanged); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete elimina un webhook; sus entregas pendientes se descartan al procesarse
+func (s *webhookService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// GetDeliveries obtiene el historial de entregas de un webhook
+func (s *webhookService) GetDeliveries(ctx context.Context, id, userID uint, page, limit int) (*domain.DeliveryPage, error) {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.repo.GetDeliveries(ctx, id, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		items = append(items, delivery.ToResponse())
+	}
+	return &domain.DeliveryPage{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+// Redeliver encola una nueva entrega con el mismo contenido que una entrega anterior
+func (s *webhookService) Redeliver(ctx context.Context, id, deliveryID, userID uint) (*domain.WebhookDelivery, error) {
+	hook, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !hook.Active {
+		return nil, ErrWebhookInactive
+	}
+
+	original, err := s.repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.WebhookID != hook.ID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	deliveries := []domain.WebhookDelivery{newDelivery(hook.ID, original.Event, original.Payload)}
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// NotifyTask encola una entrega para cada webhook activo del usuario suscrito a los eventos de la acción.
+// Los errores se registran en el log para no interrumpir la operación sobre la tarea.
+func (s *webhookService) NotifyTask(ctx context.Context, userID uint, action string, taskID uint, before, after interface{}) {
+	events := webhookEvents(action, before, after)
+	if len(events) == 0 {
+		return
+	}
+
+	hooks, err := s.repo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		log.Println("Error al obtener los webhooks:", err)
+		return
+	}
+
+	var deliveries []domain.WebhookDelivery
+	for _, event := range events {
+		var payload string
+		for _, hook := range hooks {
+			if !hook.Subscribed(event) {
+				continue
+			}
+			if payload == "" {
+				if payload, err = webhookPayload(event, action, taskID, after); err != nil {
+					log.Println("Error al serializar el webhook:", err)
+					return
+				}
+			}
+			deliveries = append(deliveries, newDelivery(hook.ID, event, payload))
+		}
+	}
+
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		log.Println("Error al encolar las entregas de webhooks:", err)
+	}
+}
+
+// DispatchDue envía las entregas pendientes cuyo próximo intento ya venció y devuelve cuántas procesó
+func (s *webhookService) DispatchDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now.Unix(), now.Add(webhookLease).Unix(), webhookBatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		hooks = make(map[uint]*domain.Webhook)
+		slots = make(chan struct{}, webhookWorkers)
+	)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		hook, cached := hooks[delivery.WebhookID]
+		if !cached {
+			if hook, err = s.repo.GetByID(ctx, delivery.WebhookID); err != nil {
+				wg.Wait()
+				return i, err
+			}
+			hooks[delivery.WebhookID] = hook
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.deliver(ctx, hook, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// deliver realiza un intento de entrega y guarda su resultado
+func (s *webhookService) deliver(ctx context.Context, hook *domain.Webhook, delivery *domain.WebhookDelivery) {
+	if hook == nil || !hook.Active {
+		delivery.Status = domain.DeliveryStatusFailed
+		delivery.Error = "el webhook fue eliminado o desactivado"
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Println("Error al guardar la entrega del webhook:", err)
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.Error = ""
+	delivery.ResponseCode = 0
+
+	now := time.Now()
+	if err := s.send(ctx, hook, delivery, now.Unix()); err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if delivery.ResponseCode >= 200 && delivery.ResponseCode < 300 {
+		delivery.Status = domain.DeliveryStatusSucceeded
+		delivery.DeliveredAt = now.Unix()
+		if err := s.repo.RecordSuccess(ctx, hook.ID); err != nil {
+			log.Println("Error al actualizar el webhook:", err)
+		}
+	} else {
+		if delivery.Attempts >= s.maxAttempts {
+			delivery.Status = domain.DeliveryStatusFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts)).Unix()
+		}
+		if err := s.repo.RecordFailure(ctx, hook.ID, s.disableAfter, now.Unix()); err != nil {
+			log.Println("Error al actualizar el webhook:", err)
+		}
+	}
+
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		log.Println("Error al guardar la entrega del webhook:", err)
+	}
+}
+
+// send envía la petición firmada y guarda el código de la respuesta.
+// El cuerpo no se guarda: el historial no debe exponer lo que responde el receptor.
+func (s *webhookService) send(ctx context.Context, hook *domain.Webhook, delivery *domain.WebhookDelivery, timestamp int64) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gin-tasks-api-webhooks/1.0")
+	req.Header.Set(webhook.HeaderEvent, delivery.Event)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(hook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	delivery.ResponseCode = resp.StatusCode
+	return nil
+}
+
+// StartWebhookDispatcher procesa periódicamente la cola de entregas de webhooks.
+// Se ejecuta hasta que el contexto se cancela.
+func StartWebhookDispatcher(ctx context.Context, webhooks WebhookService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Mientras haya entregas vencidas se procesan lotes sin esperar al siguiente ciclo
+		for {
+			processed, err := webhooks.DispatchDue(ctx)
+			if err != nil {
+				log.Println("Error al enviar las entregas de webhooks:", err)
+			}
+			if err != nil || processed < webhookBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// webhookEvents traduce una acción del historial a los eventos de webhook correspondientes.
+// Una actualización que cambia completed (PUT, PATCH, sync, CalDAV) emite además
+// task.completed o task.reopened, como el cambio de estado.
+func webhookEvents(action string, before, after interface{}) []string {
+	switch action {
+	case domain.ActionTaskCreated:
+		return []string{domain.WebhookEventTaskCreated}
+	case domain.ActionTaskUpdated:
+		events := []string{domain.WebhookEventTaskUpdated}
+		if event, ok := completionEvent(before, after); ok {
+			events = append(events, event)
+		}
+		return events
+	case domain.ActionTaskStatusChanged:
+		fields, err := toFieldMap(after)
+		if err == nil && fields["completed"] == true {
+			return []string{domain.WebhookEventTaskCompleted}
+		}
+		return []string{domain.WebhookEventTaskReopened}
+	case domain.ActionTaskMoved:
+		return []string{domain.WebhookEventTaskMoved}
+	case domain.ActionTaskDeleted:
+		return []string{domain.WebhookEventTaskDeleted}
+	case domain.ActionTaskRestored:
+		return []string{domain.WebhookEventTaskRestored}
+	case domain.ActionTaskPurged:
+		return []string{domain.WebhookEventTaskPurged}
+	default:
+		return nil
+	}
+}
+
+// completionEvent devuelve task.completed o task.reopened si completed cambió entre before y after
+func completionEvent(before, after interface{}) (string, bool) {
+	beforeFields, err := toFieldMap(before)
+	if err != nil {
+		return "", false
+	}
+	afterFields, err := toFieldMap(after)
+	if err != nil {
+		return "", false
+	}
+	was, okBefore := beforeFields["completed"].(bool)
+	is, okAfter := afterFields["completed"].(bool)
+	if !okBefore || !okAfter || was == is {
+		return "", false
+	}
+	if is {
+		return domain.WebhookEventTaskCompleted, true
+	}
+	return domain.WebhookEventTaskReopened, true
+}
+
+// webhookPayload construye el cuerpo JSON de una entrega
+func webhookPayload(event, action string, taskID uint, after interface{}) (string, error) {
+	taskEvent := domain.TaskEvent{ID: taskID, Action: action}
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			return "", err
+		}
+		taskEvent.Task = data
+	}
+	data, err := json.Marshal(taskEvent)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(domain.WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().Unix(),
+		Data:      data,
+	})
+	return string(payload), err
+}
+
+// newDelivery crea una entrega pendiente para enviarse de inmediato
+func newDelivery(webhookID uint, event, payload string) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        domain.DeliveryStatusPending,
+		NextAttemptAt: time.Now().Unix(),
+	}
+}
+
+// webhookBackoff calcula la espera antes del siguiente intento: 30s, 1m, 2m, 4m... hasta 6h
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
+
+// validateWebhookURL verifica que la URL sea absoluta, use http o https y, si el host es una IP,
+// que sea pública. Los nombres se comprueban al entregar, cuando se resuelven.
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+	if addr, err := netip.ParseAddr(parsed.Hostname()); err == nil && !isPublicWebhookAddr(addr) {
+		return ErrWebhookAddress
+	}
+	return nil
+}
+
+// normalizeWebhookEvents valida los eventos suscritos y los guarda sin duplicados, separados por comas
+func normalizeWebhookEvents(events []string) (string, error) {
+	valid := make(map[string]bool, len(domain.WebhookEvents)+1)
+	valid[domain.WebhookEventAll] = true
+	for _, event := range domain.WebhookEvents {
+		valid[event] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !valid[event] {
+			return "", fmt.Errorf("%w: %q", ErrInvalidWebhookEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			normalized = append(normalized, event)
+		}
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// newWebhookSecret genera un secreto aleatorio para firmar las entregas
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
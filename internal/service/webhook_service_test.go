@@ -0,0 +1,85 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicWebhookAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"100.64.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, isPublicWebhookAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://hooks.example.com/tasks", nil},
+		{"http://93.184.216.34:8080/hook", nil},
+		{"ftp://example.com/hook", ErrInvalidWebhookURL},
+		{"/relative/path", ErrInvalidWebhookURL},
+		{"http://", ErrInvalidWebhookURL},
+		{"http://127.0.0.1:18099/admin", ErrWebhookAddress},
+		{"http://[::1]/hook", ErrWebhookAddress},
+		{"http://169.254.169.254/latest/meta-data", ErrWebhookAddress},
+		{"http://10.0.0.5/hook", ErrWebhookAddress},
+		{"http://0.0.0.0:8080/", ErrWebhookAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.ErrorIs(t, validateWebhookURL(tt.url), tt.want)
+		})
+	}
+}
+
+// Un nombre que resuelve a loopback pasa la validación del registro, pero no la conexión
+func TestWebhookClientRejectsInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	for _, url := range []string{server.URL, "http://localhost:" + server.URL[len("http://127.0.0.1:"):]} {
+		resp, err := newWebhookClient().Post(url, "application/json", nil)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		require.Error(t, err, url)
+		assert.ErrorIs(t, err, ErrWebhookAddress, url)
+	}
+	assert.False(t, called)
+}
@@ -0,0 +1,53 @@
+// Package testutil reúne las utilidades que comparten las pruebas de varios paquetes.
+package testutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/alexroel/gin-tasks-api/internal/migrations"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// OpenDB conecta con la base de datos de cfg y le aplica todas las migraciones pendientes.
+// Si las migraciones fallan, cierra la conexión.
+func OpenDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := config.ConnectDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migrations.New(db)
+	if err == nil {
+		_, err = migrator.Up(context.Background())
+	}
+	if err != nil {
+		config.CloseDB(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+// NewDB crea una base de datos con el esquema de las migraciones que se cierra al terminar
+// la prueba. Con cfg nil usa una base SQLite temporal.
+func NewDB(t testing.TB, cfg *config.Config) *gorm.DB {
+	t.Helper()
+	if cfg == nil {
+		cfg = SQLiteConfig(t)
+	}
+	db, err := OpenDB(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { config.CloseDB(db) })
+	return db
+}
+
+// SQLiteConfig devuelve la configuración de una base SQLite en el directorio temporal de la prueba
+func SQLiteConfig(t testing.TB) *config.Config {
+	return &config.Config{
+		GinMode:     "test",
+		DBDriver:    config.DriverSQLite,
+		URLDatabase: filepath.Join(t.TempDir(), "tasks.db"),
+	}
+}
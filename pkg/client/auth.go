@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// User es un usuario de la API
+type User struct {
+	ID        uint   `json:"id"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	IsAdmin   bool   `json:"is_admin"`
+	Version   uint   `json:"version"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// SignUpInput son los datos de registro de un usuario
+type SignUpInput struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// session es la respuesta de login y de renovación del token
+type session struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+// SignUp registra un usuario. No inicia sesión: para eso se usa Login.
+func (c *Client) SignUp(ctx context.Context, input SignUpInput) (*User, error) {
+	var out struct {
+		User User `json:"user"`
+	}
+	err := c.do(ctx, &request{method: http.MethodPost, path: "/api/auth/signup", body: input}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out.User, nil
+}
+
+// Login inicia sesión y guarda el token en el TokenStore del cliente
+func (c *Client) Login(ctx context.Context, email, password string) (*User, error) {
+	var out session
+	body := map[string]string{"email": email, "password": password}
+	if err := c.do(ctx, &request{method: http.MethodPost, path: "/api/auth/login", body: body}, &out); err != nil {
+		return nil, err
+	}
+	if err := c.tokens.SetToken(out.Token); err != nil {
+		return nil, err
+	}
+	return &out.User, nil
+}
+
+// Logout cierra la sesión local eliminando el token guardado.
+// Los tokens JWT no se revocan en el servidor: siguen siendo válidos hasta su expiración.
+func (c *Client) Logout() error {
+	return c.tokens.SetToken("")
+}
+
+// Refresh renueva el token de sesión aunque todavía no esté por expirar
+func (c *Client) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	token, err := c.tokens.Token()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return ErrNotLoggedIn
+	}
+	_, err = c.refresh(ctx, token)
+	return err
+}
+
+// Profile obtiene el perfil del usuario autenticado
+func (c *Client) Profile(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/auth/profile", auth: true, idempotent: true}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// refresh pide un token nuevo con el token actual y lo guarda. Requiere refreshMu.
+func (c *Client) refresh(ctx context.Context, token string) (string, error) {
+	var out session
+	r := &request{method: http.MethodPost, path: "/api/auth/refresh", token: token}
+	if err := c.send(ctx, r, &out); err != nil {
+		return "", err
+	}
+	if err := c.tokens.SetToken(out.Token); err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// validToken devuelve el token de sesión, renovándolo antes si expira dentro de la ventana de renovación.
+// Si la renovación falla y el token todavía no expiró, se sigue usando el token actual.
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	token, err := c.tokens.Token()
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", ErrNotLoggedIn
+	}
+	if !c.needsRefresh(token) {
+		return token, nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Otra llamada pudo renovar el token mientras se esperaba el lock
+	if token, err = c.tokens.Token(); err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", ErrNotLoggedIn
+	}
+	if !c.needsRefresh(token) {
+		return token, nil
+	}
+
+	refreshed, err := c.refresh(ctx, token)
+	if err != nil {
+		if expiry, ok := tokenExpiry(token); ok && time.Now().Before(expiry) && ctx.Err() == nil {
+			return token, nil
+		}
+		return "", err
+	}
+	return refreshed, nil
+}
+
+// needsRefresh indica si el token expira dentro de la ventana de renovación
+func (c *Client) needsRefresh(token string) bool {
+	if c.refreshWindow <= 0 {
+		return false
+	}
+	expiry, ok := tokenExpiry(token)
+	return ok && time.Until(expiry) < c.refreshWindow
+}
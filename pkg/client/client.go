@@ -0,0 +1,329 @@
+// Package client es el SDK de Go para la API REST de tareas.
+//
+// Un Client guarda el token de sesión en un TokenStore, lo renueva automáticamente antes de que
+// expire, reintenta con backoff exponencial las llamadas idempotentes y convierte las respuestas
+// de error de la API en errores tipados (ver APIError).
+//
+//	c, err := client.New("http://localhost:8080")
+//	if err != nil { ... }
+//	if _, err := c.Login(ctx, "ana@example.com", "secreto123"); err != nil { ... }
+//	task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Comprar pan"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Valores por defecto de las opciones
+const (
+	defaultUserAgent     = "gin-tasks-api-client"
+	defaultRefreshWindow = 5 * time.Minute
+	defaultTimeout       = 30 * time.Second
+)
+
+// RetryPolicy configura los reintentos de las llamadas idempotentes.
+// MaxAttempts cuenta el intento inicial: 1 desactiva los reintentos.
+// La espera entre intentos crece exponencialmente desde MinBackoff hasta MaxBackoff, con jitter.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy es la política de reintentos que usa un Client si no se indica otra
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// Client es un cliente de la API de tareas. Es seguro usarlo desde varias goroutines.
+type Client struct {
+	baseURL       *url.URL
+	httpClient    *http.Client
+	tokens        TokenStore
+	retry         RetryPolicy
+	refreshWindow time.Duration
+	userAgent     string
+
+	// refreshMu evita que varias llamadas concurrentes renueven el mismo token
+	refreshMu sync.Mutex
+}
+
+// Option configura un Client
+type Option func(*Client)
+
+// WithHTTPClient usa el http.Client indicado en lugar de uno con timeout de 30 segundos
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokenStore guarda el token de sesión en store en lugar de en memoria
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Client) {
+		c.tokens = store
+	}
+}
+
+// WithRetry configura los reintentos de las llamadas idempotentes
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRefreshWindow indica cuánto antes de su expiración se renueva el token.
+// Un valor de 0 desactiva la renovación automática.
+func WithRefreshWindow(window time.Duration) Option {
+	return func(c *Client) {
+		c.refreshWindow = window
+	}
+}
+
+// WithUserAgent cambia el encabezado User-Agent de las peticiones
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New crea un cliente para la API publicada en baseURL (por ejemplo http://localhost:8080)
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: URL base inválida: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("client: la URL base debe usar http o https: %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:       parsed,
+		httpClient:    &http.Client{Timeout: defaultTimeout},
+		tokens:        &MemoryTokenStore{},
+		retry:         DefaultRetryPolicy,
+		refreshWindow: defaultRefreshWindow,
+		userAgent:     defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// request describe una llamada a la API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	header http.Header
+
+	// auth indica que la llamada requiere el token de sesión
+	auth bool
+	// idempotent indica que la llamada se puede reintentar sin efectos duplicados
+	idempotent bool
+	// token es el token que se envía; lo completa do a partir del TokenStore
+	token string
+}
+
+// envelope es el formato común de las respuestas de la API (utils.Response)
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// do envía una petición autenticada si corresponde, renovando antes el token si está por expirar
+func (c *Client) do(ctx context.Context, r *request, out interface{}) error {
+	if r.auth {
+		token, err := c.validToken(ctx)
+		if err != nil {
+			return err
+		}
+		r.token = token
+	}
+	return c.send(ctx, r, out)
+}
+
+// send envía la petición, la reintenta si corresponde y decodifica el campo data de la respuesta en out
+func (c *Client) send(ctx context.Context, r *request, out interface{}) error {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return fmt.Errorf("client: no se pudo codificar la petición: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.roundTrip(ctx, r, body)
+		if err == nil && !(r.idempotent && retryableStatus(resp.StatusCode)) {
+			defer resp.Body.Close()
+			return decode(resp, out)
+		}
+
+		// Los errores del contexto no se reintentan
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return ctxErr
+		}
+		if !r.idempotent || attempt >= c.retry.MaxAttempts {
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			return decode(resp, out)
+		}
+
+		wait := c.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+				if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+					wait = c.retry.MaxBackoff
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// roundTrip construye y envía un intento de la petición
+func (c *Client) roundTrip(ctx context.Context, r *request, body []byte) (*http.Response, error) {
+	target := *c.baseURL
+	target.Path = c.baseURL.Path + r.path
+	if len(r.query) > 0 {
+		target.RawQuery = r.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: petición inválida: %w", err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// decode convierte una respuesta en el valor de data o en un *APIError
+func decode(resp *http.Response, out interface{}) error {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("client: no se pudo leer la respuesta: %w", err)
+	}
+
+	var env envelope
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &env); err != nil && resp.StatusCode < 400 {
+			return fmt.Errorf("client: respuesta inválida: %w", err)
+		}
+	}
+
+	if resp.StatusCode >= 400 || (len(raw) > 0 && !env.Success) {
+		message := env.Error
+		if message == "" {
+			message = env.Message
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: message}
+	}
+
+	if out == nil || len(env.Data) == 0 || string(env.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("client: respuesta inválida: %w", err)
+	}
+	return nil
+}
+
+// retryableStatus indica si un estado HTTP es transitorio
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff calcula la espera antes del siguiente intento: el doble del anterior,
+// limitado a MaxBackoff, con un jitter de hasta la mitad de la espera
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retry.MinBackoff
+	for i := 1; i < attempt && (c.retry.MaxBackoff <= 0 || wait < c.retry.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+		wait = c.retry.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter interpreta el encabezado Retry-After en segundos o como fecha HTTP
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
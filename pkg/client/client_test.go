@@ -0,0 +1,453 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexroel/gin-tasks-api/internal/app"
+	"github.com/alexroel/gin-tasks-api/internal/config"
+	"github.com/alexroel/gin-tasks-api/internal/testutil"
+	"github.com/alexroel/gin-tasks-api/pkg/client"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// router es el router real de la API
+var router http.Handler
+
+// TestMain construye la aplicación contra la base de datos de TEST_DATABASE_URL, con el
+// motor de TEST_DB_DRIVER (postgres por defecto). Sin esa variable usa una base SQLite temporal.
+func TestMain(m *testing.M) {
+	driver, dsn := os.Getenv("TEST_DB_DRIVER"), os.Getenv("TEST_DATABASE_URL")
+	if driver == "" {
+		driver = config.DriverPostgres
+	}
+	dir := ""
+	if dsn == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "tasks-sdk-test"); err != nil {
+			log.Fatal(err)
+		}
+		driver, dsn = config.DriverSQLite, filepath.Join(dir, "tasks.db")
+	}
+	os.Setenv("DB_DRIVER", driver)
+	os.Setenv("URL_DATABASE", dsn)
+	os.Setenv("JWT_SECRET", "secreto-de-pruebas-del-sdk")
+	os.Setenv("GIN_MODE", gin.TestMode)
+	gin.SetMode(gin.TestMode)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := testutil.OpenDB(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	application, err := app.New(cfg, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	application.Start(context.Background())
+	router = application.Router()
+
+	code := m.Run()
+	config.CloseDB(db)
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+	os.Exit(code)
+}
+
+// newServer inicia un servidor de pruebas con el router real, envuelto por wrap si no es nil
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	handler := router
+	if wrap != nil {
+		handler = wrap(router)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newClient crea un cliente sin esperas entre reintentos
+func newClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+	opts = append([]client.Option{client.WithRetry(client.RetryPolicy{MaxAttempts: 3})}, opts...)
+	c, err := client.New(baseURL, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+// signUpAndLogin registra un usuario nuevo e inicia sesión con él
+func signUpAndLogin(t *testing.T, c *client.Client) *client.User {
+	t.Helper()
+	ctx := context.Background()
+	input := client.SignUpInput{
+		FullName: "Usuario SDK",
+		Email:    fmt.Sprintf("sdk-%d@example.com", time.Now().UnixNano()),
+		Password: "secreto123",
+	}
+	_, err := c.SignUp(ctx, input)
+	require.NoError(t, err)
+	user, err := c.Login(ctx, input.Email, input.Password)
+	require.NoError(t, err)
+	return user
+}
+
+func TestAuth(t *testing.T) {
+	server := newServer(t, nil)
+	c := newClient(t, server.URL)
+	ctx := context.Background()
+
+	_, err := c.Profile(ctx)
+	assert.ErrorIs(t, err, client.ErrNotLoggedIn)
+
+	email := fmt.Sprintf("sdk-%d@example.com", time.Now().UnixNano())
+	created, err := c.SignUp(ctx, client.SignUpInput{FullName: "Ana Pérez", Email: email, Password: "secreto123"})
+	require.NoError(t, err)
+	assert.Equal(t, email, created.Email)
+
+	_, err = c.SignUp(ctx, client.SignUpInput{FullName: "Ana Pérez", Email: email, Password: "secreto123"})
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	_, err = c.Login(ctx, email, "incorrecta")
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.Message)
+
+	user, err := c.Login(ctx, email, "secreto123")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, user.ID)
+
+	profile, err := c.Profile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana Pérez", profile.FullName)
+	assert.Equal(t, uint(1), profile.Version)
+
+	require.NoError(t, c.Refresh(ctx))
+	_, err = c.Profile(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Logout())
+	_, err = c.Profile(ctx)
+	assert.ErrorIs(t, err, client.ErrNotLoggedIn)
+}
+
+func TestTasks(t *testing.T) {
+	server := newServer(t, nil)
+	c := newClient(t, server.URL)
+	ctx := context.Background()
+	user := signUpAndLogin(t, c)
+
+	task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Preparar informe mensual"})
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, task.UserID)
+	assert.Equal(t, uint(1), task.Version)
+	assert.False(t, task.Completed)
+
+	_, err = c.CreateTask(ctx, client.CreateTaskInput{Title: "Comprar pan"})
+	require.NoError(t, err)
+
+	_, err = c.CreateTask(ctx, client.CreateTaskInput{Title: ""})
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	got, err := c.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, got.Title)
+
+	tasks, err := c.ListTasks(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	tasks, err = c.ListTasks(ctx, &client.ListTasksOptions{Filter: "informe"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.ID, tasks[0].ID)
+
+	_, err = c.ListTasks(ctx, &client.ListTasksOptions{Filter: `title:"sin cerrar`})
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	title := "Preparar informe trimestral"
+	updated, err := c.UpdateTask(ctx, task.ID, client.UpdateTaskInput{Title: &title}, task.Version)
+	require.NoError(t, err)
+	assert.Equal(t, title, updated.Title)
+	assert.Equal(t, uint(2), updated.Version)
+
+	// Una versión desactualizada no modifica la tarea
+	_, err = c.UpdateTask(ctx, task.ID, client.UpdateTaskInput{Title: &title}, task.Version)
+	assert.ErrorIs(t, err, client.ErrPreconditionFailed)
+
+	done, err := c.SetTaskStatus(ctx, task.ID, true, updated.Version)
+	require.NoError(t, err)
+	assert.True(t, done.Completed)
+	assert.Equal(t, uint(3), done.Version)
+
+	tasks, err = c.ListTasks(ctx, &client.ListTasksOptions{Filter: "status:done"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.ID, tasks[0].ID)
+
+	assert.ErrorIs(t, c.DeleteTask(ctx, task.ID, updated.Version), client.ErrPreconditionFailed)
+	require.NoError(t, c.DeleteTask(ctx, task.ID, done.Version))
+
+	_, err = c.GetTask(ctx, task.ID)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestTasksOfAnotherUser(t *testing.T) {
+	server := newServer(t, nil)
+	ctx := context.Background()
+
+	owner := newClient(t, server.URL)
+	signUpAndLogin(t, owner)
+	task, err := owner.CreateTask(ctx, client.CreateTaskInput{Title: "Privada"})
+	require.NoError(t, err)
+
+	other := newClient(t, server.URL)
+	signUpAndLogin(t, other)
+	_, err = other.GetTask(ctx, task.ID)
+	assert.ErrorIs(t, err, client.ErrForbidden)
+}
+
+// countingStore cuenta los tokens guardados
+type countingStore struct {
+	client.MemoryTokenStore
+	sets atomic.Int32
+}
+
+func (s *countingStore) SetToken(token string) error {
+	s.sets.Add(1)
+	return s.MemoryTokenStore.SetToken(token)
+}
+
+func TestAutomaticRefresh(t *testing.T) {
+	var refreshes atomic.Int32
+	server := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/auth/refresh" {
+				refreshes.Add(1)
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+
+	// Sin renovación automática, el token solo cambia al iniciar sesión
+	store := &countingStore{}
+	c := newClient(t, server.URL, client.WithTokenStore(store), client.WithRefreshWindow(0))
+	signUpAndLogin(t, c)
+	_, err := c.Profile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), refreshes.Load())
+	assert.Equal(t, int32(1), store.sets.Load())
+
+	// Con una ventana mayor que la duración del token, cada llamada lo renueva antes de enviarse.
+	// Las renovaciones concurrentes se hacen de a una: cada token guardado proviene de una sola renovación.
+	store = &countingStore{}
+	c = newClient(t, server.URL, client.WithTokenStore(store), client.WithRefreshWindow(48*time.Hour))
+	signUpAndLogin(t, c)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Profile(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, refreshes.Load(), int32(1))
+	assert.Equal(t, refreshes.Load()+1, store.sets.Load())
+
+	token, err := store.Token()
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+// flaky responde 502 a las primeras n peticiones que cumplen match
+type flaky struct {
+	next     http.Handler
+	match    func(*http.Request) bool
+	failures atomic.Int32
+	// forward indica que la petición llega al router antes de fallar, como si se perdiera la respuesta
+	forward bool
+
+	mu   sync.Mutex
+	seen []*http.Request
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.match(r) {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	f.mu.Lock()
+	f.seen = append(f.seen, r)
+	f.mu.Unlock()
+	if f.failures.Add(-1) < 0 {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	if f.forward {
+		f.next.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	w.Header().Set("Retry-After", "0")
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+func (f *flaky) requests() []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.seen
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create reuses the idempotency key", func(t *testing.T) {
+		proxy := &flaky{forward: true, match: func(r *http.Request) bool {
+			return r.Method == http.MethodPost && r.URL.Path == "/api/tasks"
+		}}
+		proxy.failures.Store(2)
+		server := newServer(t, func(next http.Handler) http.Handler {
+			proxy.next = next
+			return proxy
+		})
+		c := newClient(t, server.URL)
+		signUpAndLogin(t, c)
+
+		task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Reintentada"})
+		require.NoError(t, err)
+
+		requests := proxy.requests()
+		require.Len(t, requests, 3)
+		key := requests[0].Header.Get("Idempotency-Key")
+		assert.NotEmpty(t, key)
+		for _, r := range requests {
+			assert.Equal(t, key, r.Header.Get("Idempotency-Key"))
+		}
+
+		// El servidor ejecutó la primera petición; los reintentos recibieron la respuesta guardada
+		tasks, err := c.ListTasks(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, task.ID, tasks[0].ID)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		proxy := &flaky{match: func(r *http.Request) bool { return r.Method == http.MethodGet }}
+		proxy.failures.Store(10)
+		server := newServer(t, func(next http.Handler) http.Handler {
+			proxy.next = next
+			return proxy
+		})
+		c := newClient(t, server.URL)
+		signUpAndLogin(t, c)
+
+		_, err := c.Profile(ctx)
+		assert.ErrorIs(t, err, client.ErrServer)
+		assert.Len(t, proxy.requests(), 3)
+	})
+
+	t.Run("does not retry non-idempotent calls", func(t *testing.T) {
+		proxy := &flaky{match: func(r *http.Request) bool { return r.Method == http.MethodPatch }}
+		proxy.failures.Store(1)
+		server := newServer(t, func(next http.Handler) http.Handler {
+			proxy.next = next
+			return proxy
+		})
+		c := newClient(t, server.URL)
+		signUpAndLogin(t, c)
+		task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Sin reintento"})
+		require.NoError(t, err)
+
+		_, err = c.SetTaskStatus(ctx, task.ID, true, 0)
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		assert.Len(t, proxy.requests(), 1)
+	})
+}
+
+func TestEvents(t *testing.T) {
+	server := newServer(t, nil)
+	c := newClient(t, server.URL)
+	signUpAndLogin(t, c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	received := make(chan client.Event, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Events(ctx, "", func(event client.Event) error {
+			received <- event
+			return nil
+		})
+	}()
+
+	// El stream se abre de forma asíncrona: se crean tareas hasta recibir el primer evento
+	var event client.Event
+	for event.Type == "" {
+		_, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Con evento"})
+		require.NoError(t, err)
+		select {
+		case event = <-received:
+		case <-time.After(200 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("no se recibió ningún evento")
+		}
+	}
+	assert.Equal(t, client.EventTaskCreated, event.Type)
+	assert.NotEmpty(t, event.ID)
+	require.NotNil(t, event.Task)
+	assert.Equal(t, event.TaskID, event.Task.ID)
+	assert.Equal(t, "Con evento", event.Task.Title)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasksctl", "token")
+	store := client.NewFileTokenStore(path)
+
+	token, err := store.Token()
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	require.NoError(t, store.SetToken("abc.def.ghi"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	token, err = client.NewFileTokenStore(path).Token()
+	require.NoError(t, err)
+	assert.Equal(t, "abc.def.ghi", token)
+
+	require.NoError(t, store.SetToken(""))
+	_, err = os.Stat(path)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestNew(t *testing.T) {
+	_, err := client.New("localhost:8080")
+	assert.Error(t, err)
+
+	_, err = client.New("http://localhost:8080/")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errores de la API según el estado HTTP de la respuesta. Se comparan con errors.Is:
+//
+//	if errors.Is(err, client.ErrPreconditionFailed) { ... }
+var (
+	ErrValidation           = errors.New("datos inválidos")
+	ErrUnauthorized         = errors.New("no autenticado")
+	ErrForbidden            = errors.New("sin permiso")
+	ErrNotFound             = errors.New("recurso no encontrado")
+	ErrConflict             = errors.New("conflicto con el estado actual del recurso")
+	ErrPreconditionFailed   = errors.New("la versión no coincide")
+	ErrPreconditionRequired = errors.New("se requiere la versión del recurso")
+	ErrRateLimited          = errors.New("demasiadas peticiones")
+	ErrServer               = errors.New("error del servidor")
+)
+
+// ErrNotLoggedIn indica que la llamada requiere sesión y no hay token guardado
+var ErrNotLoggedIn = errors.New("client: no hay sesión iniciada")
+
+// APIError es una respuesta de error de la API, con el mensaje del campo error del envoltorio
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is permite comparar el error con los errores de la API mediante errors.Is
+func (e *APIError) Is(target error) bool {
+	return statusError(e.StatusCode) == target
+}
+
+// statusError devuelve el error de la API correspondiente a un estado HTTP
+func statusError(status int) error {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusPreconditionRequired:
+		return ErrPreconditionRequired
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	if status >= 500 {
+		return ErrServer
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Tipos de los eventos en tiempo real
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+	// EventResync indica que se perdieron eventos: el cliente debe volver a cargar sus tareas
+	EventResync = "resync"
+)
+
+// maxEventSize es el tamaño máximo de una línea del stream de eventos
+const maxEventSize = 1 << 20
+
+// Event es un evento en tiempo real de las tareas del usuario
+type Event struct {
+	// ID identifica el evento; se pasa a Events para reanudar el stream sin perder eventos
+	ID   string
+	Type string
+	// TaskID es la tarea afectada; es 0 en los eventos resync
+	TaskID uint
+	// Action es la acción del historial que originó el evento, por ejemplo task.status_changed
+	Action string
+	// Task es el estado de la tarea tras el cambio; es nil si el evento no lo incluye.
+	// En las operaciones masivas puede traer solo los campos modificados.
+	Task *Task
+}
+
+// Events abre el stream de eventos en tiempo real (GET /api/events) y llama a fn con cada evento.
+// Si lastEventID no está vacío, el servidor reenvía primero los eventos posteriores a ese ID.
+// Devuelve nil cuando el servidor cierra el stream, el error de fn si fn falla, o el error de ctx al cancelarse.
+// La reconexión queda a cargo del llamador, con el ID del último evento recibido.
+func (c *Client) Events(ctx context.Context, lastEventID string, fn func(Event) error) error {
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String()+"/api/events", nil)
+	if err != nil {
+		return fmt.Errorf("client: petición inválida: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	// El stream no tiene duración máxima: se usa el mismo transporte sin timeout
+	stream := *c.httpClient
+	stream.Timeout = 0
+	resp, err := stream.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decode(resp, nil)
+	}
+
+	err = readEvents(bufio.NewScanner(resp.Body), fn)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// readEvents interpreta el formato Server-Sent Events: los campos de un evento terminan con una línea vacía
+// y las líneas que empiezan con ':' son comentarios (el servidor los usa como heartbeat)
+func readEvents(scanner *bufio.Scanner, fn func(Event) error) error {
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	var id, eventType string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if eventType != "" || len(data) > 0 {
+				event, err := parseEvent(id, eventType, strings.Join(data, "\n"))
+				if err != nil {
+					return err
+				}
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			id, eventType, data = "", "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// parseEvent convierte los campos SSE de un evento en un Event
+func parseEvent(id, eventType, data string) (Event, error) {
+	event := Event{ID: id, Type: eventType}
+	if eventType == EventResync || data == "" {
+		return event, nil
+	}
+	var payload struct {
+		ID     uint   `json:"id"`
+		Action string `json:"action"`
+		Task   *Task  `json:"task"`
+	}
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return event, fmt.Errorf("client: evento %s inválido: %w", eventType, err)
+	}
+	event.TaskID = payload.ID
+	event.Action = payload.Action
+	event.Task = payload.Task
+	return event, nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Task es una tarea de la API
+type Task struct {
+	ID        uint    `json:"id"`
+	Title     string  `json:"title"`
+	Completed bool    `json:"completed"`
+	Position  string  `json:"position"`
+	UserID    uint    `json:"user_id"`
+	ClientID  *string `json:"client_id,omitempty"`
+	Version   uint    `json:"version"`
+	CreatedAt int64   `json:"created_at"`
+	UpdatedAt int64   `json:"updated_at"`
+}
+
+// CreateTaskInput son los datos de una tarea nueva
+type CreateTaskInput struct {
+	Title string `json:"title"`
+}
+
+// UpdateTaskInput son los campos de una tarea que se modifican; los campos nil no cambian
+type UpdateTaskInput struct {
+	Title     *string `json:"title,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+}
+
+// ListTasksOptions filtra el listado de tareas
+type ListTasksOptions struct {
+	// Filter es una expresión del lenguaje de filtros, por ejemplo `status:open title:"informe mensual"`
+	Filter string
+}
+
+// ListTasks obtiene las tareas del usuario autenticado; opts puede ser nil
+func (c *Client) ListTasks(ctx context.Context, opts *ListTasksOptions) ([]Task, error) {
+	query := url.Values{}
+	if opts != nil && opts.Filter != "" {
+		query.Set("filter", opts.Filter)
+	}
+	var tasks []Task
+	r := &request{method: http.MethodGet, path: "/api/tasks", query: query, auth: true, idempotent: true}
+	if err := c.do(ctx, r, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTask obtiene una tarea por ID
+func (c *Client) GetTask(ctx context.Context, id uint) (*Task, error) {
+	var task Task
+	r := &request{method: http.MethodGet, path: taskPath(id), auth: true, idempotent: true}
+	if err := c.do(ctx, r, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CreateTask crea una tarea. La petición lleva una clave de idempotencia,
+// así que los reintentos no crean tareas duplicadas.
+func (c *Client) CreateTask(ctx context.Context, input CreateTaskInput) (*Task, error) {
+	key, err := idempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	var task Task
+	r := &request{
+		method:     http.MethodPost,
+		path:       "/api/tasks",
+		body:       input,
+		header:     http.Header{"Idempotency-Key": {key}},
+		auth:       true,
+		idempotent: true,
+	}
+	if err := c.do(ctx, r, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask modifica una tarea. Si version es mayor que cero, la tarea solo se modifica
+// si sigue en esa versión; en caso contrario el error cumple errors.Is(err, ErrPreconditionFailed).
+func (c *Client) UpdateTask(ctx context.Context, id uint, input UpdateTaskInput, version uint) (*Task, error) {
+	var task Task
+	r := &request{method: http.MethodPut, path: taskPath(id), body: input, header: ifMatch(version), auth: true, idempotent: true}
+	if err := c.do(ctx, r, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SetTaskStatus marca una tarea como completada o pendiente; version funciona igual que en UpdateTask
+func (c *Client) SetTaskStatus(ctx context.Context, id uint, completed bool, version uint) (*Task, error) {
+	var task Task
+	body := map[string]bool{"completed": completed}
+	r := &request{method: http.MethodPatch, path: taskPath(id) + "/status", body: body, header: ifMatch(version), auth: true}
+	if err := c.do(ctx, r, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask mueve una tarea a la papelera; version funciona igual que en UpdateTask
+func (c *Client) DeleteTask(ctx context.Context, id uint, version uint) error {
+	r := &request{method: http.MethodDelete, path: taskPath(id), header: ifMatch(version), auth: true, idempotent: true}
+	return c.do(ctx, r, nil)
+}
+
+// taskPath devuelve la ruta de una tarea
+func taskPath(id uint) string {
+	return "/api/tasks/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// ifMatch devuelve el encabezado If-Match de una versión; 0 indica que no se verifica
+func ifMatch(version uint) http.Header {
+	if version == 0 {
+		return nil
+	}
+	return http.Header{"If-Match": {fmt.Sprintf(`"v%d"`, version)}}
+}
+
+// idempotencyKey genera una clave de idempotencia aleatoria
+func idempotencyKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("client: no se pudo generar la clave de idempotencia: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenStore guarda el token de sesión del cliente. Un token vacío indica que no hay sesión.
+type TokenStore interface {
+	Token() (string, error)
+	SetToken(token string) error
+}
+
+// MemoryTokenStore guarda el token en memoria; es el almacenamiento por defecto
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token string
+}
+
+// Token devuelve el token guardado
+func (s *MemoryTokenStore) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+// SetToken reemplaza el token guardado
+func (s *MemoryTokenStore) SetToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
+
+// FileTokenStore guarda el token en un archivo legible solo por el usuario actual,
+// de modo que la sesión se conserva entre ejecuciones (por ejemplo, en una CLI)
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore crea un almacenamiento en path; el archivo y su directorio se crean al guardar
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Token devuelve el token del archivo, o vacío si el archivo no existe
+func (s *FileTokenStore) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetToken escribe el token con permisos 0600; un token vacío elimina el archivo
+func (s *FileTokenStore) SetToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token == "" {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	// Escribir en un archivo temporal y renombrarlo para no dejar un token a medias
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.WriteString(token + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// tokenExpiry lee la expiración (claim exp) de un JWT sin verificar su firma.
+// Solo se usa para decidir cuándo renovarlo: la verificación la hace el servidor.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
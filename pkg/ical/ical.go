@@ -0,0 +1,97 @@
+// Package ical escribe contenido iCalendar (RFC 5545): componentes, propiedades con el
+// texto escapado y líneas plegadas a 75 octetos con fin de línea CRLF.
+package ical
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MediaType es el tipo de contenido de los documentos iCalendar
+const MediaType = "text/calendar; charset=utf-8"
+
+// maxLineOctets es la longitud máxima de una línea sin contar el CRLF
+const maxLineOctets = 75
+
+// Writer escribe un documento iCalendar. El primer error de escritura se conserva y
+// las escrituras siguientes se ignoran; se consulta con Err.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+// NewWriter crea un Writer que escribe en w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Begin abre un componente, por ejemplo VCALENDAR o VTODO
+func (w *Writer) Begin(component string) {
+	w.line("BEGIN:" + component)
+}
+
+// End cierra un componente
+func (w *Writer) End(component string) {
+	w.line("END:" + component)
+}
+
+// Property escribe una propiedad con un valor que no requiere escape (fechas, números, enumerados)
+func (w *Writer) Property(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text escribe una propiedad de tipo TEXT escapando su valor
+func (w *Writer) Text(name, value string) {
+	w.line(name + ":" + EscapeText(value))
+}
+
+// Time escribe una propiedad DATE-TIME en UTC
+func (w *Writer) Time(name string, t time.Time) {
+	w.line(name + ":" + FormatTime(t))
+}
+
+// Err devuelve el primer error de escritura
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// line escribe una línea de contenido plegándola si excede la longitud máxima.
+// El corte nunca divide un carácter UTF-8.
+func (w *Writer) line(content string) {
+	if w.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Las líneas de continuación empiezan con un espacio que cuenta en la longitud
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	_, w.err = io.WriteString(w.w, b.String())
+}
+
+// textEscaper escapa los caracteres especiales de un valor TEXT
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// EscapeText escapa un valor de tipo TEXT
+func EscapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// FormatTime formatea una fecha como DATE-TIME en UTC (20060102T150405Z)
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
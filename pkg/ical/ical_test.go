@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterFoldsLongLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"ascii", strings.Repeat("a", 200)},
+		// "ñ" ocupa dos octetos: los cortes caen a veces en medio del carácter
+		{"utf-8", strings.Repeat("ñ", 100)},
+		{"justo en el límite", strings.Repeat("b", maxLineOctets-len("SUMMARY:"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			w.Text("SUMMARY", tt.value)
+			require.NoError(t, w.Err())
+
+			out := buf.String()
+			require.True(t, strings.HasSuffix(out, "\r\n"))
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, line := range lines {
+				assert.LessOrEqual(t, len(line), maxLineOctets, "línea %d", i)
+				assert.True(t, utf8.ValidString(line), "línea %d", i)
+				if i > 0 {
+					assert.True(t, strings.HasPrefix(line, " "), "línea %d", i)
+				}
+			}
+			if len("SUMMARY:"+tt.value) <= maxLineOctets {
+				assert.Len(t, lines, 1)
+			}
+
+			joined := lines[0]
+			for _, line := range lines[1:] {
+				joined += line[1:]
+			}
+			assert.Equal(t, "SUMMARY:"+tt.value, joined)
+		})
+	}
+}
+
+func TestWriterProperties(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Begin("VTODO")
+	w.Property("STATUS", "COMPLETED")
+	w.Time("DTSTAMP", time.Date(2024, 3, 5, 10, 4, 5, 0, time.FixedZone("", 3600)))
+	w.Text("SUMMARY", "Comprar pan; leche, huevos\nurgente")
+	w.End("VTODO")
+	require.NoError(t, w.Err())
+
+	assert.Equal(t, "BEGIN:VTODO\r\n"+
+		"STATUS:COMPLETED\r\n"+
+		"DTSTAMP:20240305T090405Z\r\n"+
+		`SUMMARY:Comprar pan\; leche\, huevos\nurgente`+"\r\n"+
+		"END:VTODO\r\n", buf.String())
+}
+
+type failingWriter struct{ writes int }
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	f.writes++
+	return 0, assert.AnError
+}
+
+// Después del primer error no se intenta escribir más
+func TestWriterKeepsFirstError(t *testing.T) {
+	out := &failingWriter{}
+	w := NewWriter(out)
+	w.Begin("VCALENDAR")
+	w.End("VCALENDAR")
+	assert.ErrorIs(t, w.Err(), assert.AnError)
+	assert.Equal(t, 1, out.writes)
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"sin cambios", "sin cambios"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"a\r\nb\nc\rd", `a\nb\nc\nd`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, EscapeText(tt.value))
+		})
+	}
+}
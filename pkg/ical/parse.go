@@ -0,0 +1,182 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxParseLine es la longitud máxima de una línea de contenido desplegada
+const maxParseLine = 1 << 20
+
+var ErrInvalidCalendar = errors.New("documento iCalendar inválido")
+
+// Component es un componente de un documento iCalendar, por ejemplo VCALENDAR o VTODO
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Property es una propiedad de un componente. Value conserva el valor sin desescapar;
+// los valores de tipo TEXT se obtienen con Text.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Text devuelve el valor de la propiedad como TEXT desescapado
+func (p *Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Get devuelve la primera propiedad con el nombre indicado, o nil si no existe
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if strings.EqualFold(c.Properties[i].Name, name) {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Children devuelve los subcomponentes con el nombre indicado
+func (c *Component) Children(name string) []*Component {
+	var children []*Component
+	for _, child := range c.Components {
+		if strings.EqualFold(child.Name, name) {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Parse lee un documento iCalendar con un único componente raíz. Acepta finales de línea
+// LF o CRLF y despliega las líneas plegadas.
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	var stack []*Component
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: línea %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+
+		switch strings.ToUpper(prop.Name) {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if root != nil {
+				return nil, fmt.Errorf("%w: más de un componente raíz", ErrInvalidCalendar)
+			} else {
+				root = component
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("%w: END:%s sin el BEGIN correspondiente", ErrInvalidCalendar, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: propiedad %s fuera de un componente", ErrInvalidCalendar, prop.Name)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: no contiene ningún componente", ErrInvalidCalendar)
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: falta END:%s", ErrInvalidCalendar, stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// unfold lee las líneas de contenido uniendo las líneas de continuación, que empiezan
+// con un espacio o una tabulación
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxParseLine)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseLine separa una línea de contenido en nombre, parámetros y valor.
+// Los dos puntos y los punto y coma dentro de parámetros entre comillas no son separadores.
+func parseLine(line string) (Property, error) {
+	prop := Property{}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, errors.New("falta el nombre de la propiedad")
+	}
+	prop.Name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return prop, fmt.Errorf("parámetro inválido en %s", prop.Name)
+		}
+		name := strings.ToUpper(rest[:eq])
+
+		// El valor termina en el siguiente ; o : que no esté entre comillas
+		j := eq + 1
+		quoted := false
+		for ; j < len(rest); j++ {
+			if rest[j] == '"' {
+				quoted = !quoted
+			} else if !quoted && (rest[j] == ';' || rest[j] == ':') {
+				break
+			}
+		}
+		if j == len(rest) {
+			return prop, fmt.Errorf("falta el valor de %s", prop.Name)
+		}
+
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[name] = strings.Trim(rest[eq+1:j], `"`)
+		i += 1 + j
+	}
+
+	prop.Value = line[i+1:]
+	return prop, nil
+}
+
+// textUnescaper revierte el escape de los valores TEXT
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// UnescapeText desescapa un valor de tipo TEXT
+func UnescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
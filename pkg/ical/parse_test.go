@@ -0,0 +1,110 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Un documento escrito con Writer se vuelve a leer con Parse sin perder el texto,
+// aunque tenga caracteres especiales y líneas plegadas
+func TestParseRoundTrip(t *testing.T) {
+	summary := "Preparar la reunión; revisar presupuesto, agenda y notas\\" + strings.Repeat(" ñandú", 20) + "\nsegunda línea"
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Begin("VCALENDAR")
+	w.Property("VERSION", "2.0")
+	w.Begin("VTODO")
+	w.Property("UID", "task-1@example.com")
+	w.Text("SUMMARY", summary)
+	w.Property("STATUS", "NEEDS-ACTION")
+	w.End("VTODO")
+	w.End("VCALENDAR")
+	require.NoError(t, w.Err())
+
+	calendar, err := Parse(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "VCALENDAR", calendar.Name)
+	assert.Equal(t, "2.0", calendar.Get("version").Value)
+
+	todos := calendar.Children("VTODO")
+	require.Len(t, todos, 1)
+	assert.Equal(t, "task-1@example.com", todos[0].Get("UID").Value)
+	assert.Equal(t, summary, todos[0].Get("SUMMARY").Text())
+	assert.Equal(t, "NEEDS-ACTION", todos[0].Get("STATUS").Value)
+	assert.Nil(t, todos[0].Get("COMPLETED"))
+}
+
+func TestParseUnfolds(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"CRLF y espacio", "BEGIN:VTODO\r\nSUMMARY:Comprar\r\n  pan y\r\n  leche\r\nEND:VTODO\r\n"},
+		{"LF y tabulación", "BEGIN:VTODO\nSUMMARY:Comprar\n\t pan y\n\t leche\nEND:VTODO\n"},
+		{"sin salto final", "BEGIN:VTODO\nSUMMARY:Comprar\n  pan y\n  leche\nEND:VTODO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := Parse(strings.NewReader(tt.doc))
+			require.NoError(t, err)
+			assert.Equal(t, "Comprar pan y leche", todo.Get("SUMMARY").Text())
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	doc := "BEGIN:VTODO\r\n" +
+		`ATTENDEE;cn="Pérez; Ana";ROLE=REQ-PARTICIPANT:mailto:ana@example.com` + "\r\n" +
+		`X-NOTE;X-URL="http://example.com:8080":texto` + "\r\n" +
+		"END:VTODO\r\n"
+
+	todo, err := Parse(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	attendee := todo.Get("ATTENDEE")
+	require.NotNil(t, attendee)
+	assert.Equal(t, map[string]string{"CN": "Pérez; Ana", "ROLE": "REQ-PARTICIPANT"}, attendee.Params)
+	assert.Equal(t, "mailto:ana@example.com", attendee.Value)
+
+	note := todo.Get("X-NOTE")
+	require.NotNil(t, note)
+	assert.Equal(t, "http://example.com:8080", note.Params["X-URL"])
+	assert.Equal(t, "texto", note.Value)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"vacío", ""},
+		{"sin componentes", "\r\n\r\n"},
+		{"dos raíces", "BEGIN:VTODO\r\nEND:VTODO\r\nBEGIN:VTODO\r\nEND:VTODO\r\n"},
+		{"END distinto", "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n"},
+		{"END sin BEGIN", "END:VTODO\r\n"},
+		{"falta END", "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VTODO\r\n"},
+		{"propiedad fuera de un componente", "SUMMARY:x\r\nBEGIN:VTODO\r\nEND:VTODO\r\n"},
+		{"sin nombre", "BEGIN:VTODO\r\n:valor\r\nEND:VTODO\r\n"},
+		{"sin dos puntos", "BEGIN:VTODO\r\nSUMMARY\r\nEND:VTODO\r\n"},
+		{"parámetro sin valor", "BEGIN:VTODO\r\nSUMMARY;LANGUAGE:x\r\nEND:VTODO\r\n"},
+		{"comillas sin cerrar", "BEGIN:VTODO\r\nSUMMARY;X=\"a:b\r\nEND:VTODO\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.doc))
+			assert.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	for _, value := range []string{"texto", `a\b`, "a;b,c", "una\nlínea", `\n literal`} {
+		assert.Equal(t, value, UnescapeText(EscapeText(value)), value)
+	}
+	assert.Equal(t, "a\nb", UnescapeText(`a\Nb`))
+}
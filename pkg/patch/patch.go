@@ -0,0 +1,73 @@
+// Package patch aplica documentos JSON Merge Patch (RFC 7396) y JSON Patch (RFC 6902).
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Tipos de contenido soportados
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("tipo de contenido no soportado: usa application/merge-patch+json o application/json-patch+json")
+	ErrInvalidPatch         = errors.New("documento de patch inválido")
+	ErrTestFailed           = errors.New("la operación test no se cumplió")
+	ErrCannotApply          = errors.New("el patch no se puede aplicar al documento")
+)
+
+// Apply aplica un patch al documento JSON según el tipo de contenido de la petición
+func Apply(contentType string, doc, patchData []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case MediaTypeMergePatch:
+		return applyMergePatch(doc, patchData)
+	case MediaTypeJSONPatch:
+		return applyJSONPatch(doc, patchData)
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+}
+
+// applyMergePatch aplica un JSON Merge Patch: los campos con null se eliminan del documento
+func applyMergePatch(doc, patchData []byte) ([]byte, error) {
+	// Un merge patch que no es un objeto reemplazaría el documento completo
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(patchData, &object); err != nil || object == nil {
+		return nil, fmt.Errorf("%w: se esperaba un objeto JSON", ErrInvalidPatch)
+	}
+
+	result, err := jsonpatch.MergePatch(doc, patchData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCannotApply, err)
+	}
+	return result, nil
+}
+
+// applyJSONPatch aplica una lista de operaciones JSON Patch de forma atómica
+func applyJSONPatch(doc, patchData []byte) ([]byte, error) {
+	operations, err := jsonpatch.DecodePatch(patchData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	result, err := operations.Apply(doc)
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, fmt.Errorf("%w: %v", ErrTestFailed, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrCannotApply, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `{"title":"Informe","completed":false,"tags":["a"]}`
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		want        string
+	}{
+		{"merge patch", MediaTypeMergePatch, `{"completed":true}`, `{"title":"Informe","completed":true,"tags":["a"]}`},
+		{"merge patch con null elimina el campo", MediaTypeMergePatch, `{"tags":null}`, `{"title":"Informe","completed":false}`},
+		{"merge patch con charset", MediaTypeMergePatch + "; charset=utf-8", `{"title":"Otro"}`, `{"title":"Otro","completed":false,"tags":["a"]}`},
+		{"json patch", MediaTypeJSONPatch, `[{"op":"replace","path":"/title","value":"Otro"}]`, `{"title":"Otro","completed":false,"tags":["a"]}`},
+		{"json patch con test", MediaTypeJSONPatch, `[{"op":"test","path":"/completed","value":false},{"op":"replace","path":"/completed","value":true}]`, `{"title":"Informe","completed":true,"tags":["a"]}`},
+		{"json patch en un array", MediaTypeJSONPatch, `[{"op":"add","path":"/tags/-","value":"b"}]`, `{"title":"Informe","completed":false,"tags":["a","b"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Apply(tt.contentType, []byte(testDoc), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(result))
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		want        error
+	}{
+		{"sin tipo de contenido", "", `{}`, ErrUnsupportedMediaType},
+		{"tipo de contenido malformado", "application/", `{}`, ErrUnsupportedMediaType},
+		{"JSON normal", "application/json", `{}`, ErrUnsupportedMediaType},
+		{"merge patch que no es un objeto", MediaTypeMergePatch, `["title"]`, ErrInvalidPatch},
+		{"merge patch nulo", MediaTypeMergePatch, `null`, ErrInvalidPatch},
+		{"merge patch malformado", MediaTypeMergePatch, `{"title":`, ErrInvalidPatch},
+		{"json patch que no es una lista", MediaTypeJSONPatch, `{"op":"remove","path":"/title"}`, ErrInvalidPatch},
+		{"json patch malformado", MediaTypeJSONPatch, `[{"op":`, ErrInvalidPatch},
+		{"test que no se cumple", MediaTypeJSONPatch, `[{"op":"test","path":"/completed","value":true}]`, ErrTestFailed},
+		{"eliminar un campo inexistente", MediaTypeJSONPatch, `[{"op":"remove","path":"/missing"}]`, ErrCannotApply},
+		{"operación desconocida", MediaTypeJSONPatch, `[{"op":"rename","path":"/title"}]`, ErrInvalidPatch},
+		{"índice fuera de rango", MediaTypeJSONPatch, `[{"op":"replace","path":"/tags/5","value":"x"}]`, ErrCannotApply},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Apply(tt.contentType, []byte(testDoc), []byte(tt.patch))
+			assert.ErrorIs(t, err, tt.want)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+// Un JSON Patch se aplica completo o no se aplica: una operación fallida no deja cambios parciales
+func TestApplyJSONPatchIsAtomic(t *testing.T) {
+	doc := []byte(testDoc)
+	_, err := Apply(MediaTypeJSONPatch, doc, []byte(`[{"op":"replace","path":"/title","value":"Otro"},{"op":"test","path":"/completed","value":true}]`))
+	require.ErrorIs(t, err, ErrTestFailed)
+	assert.JSONEq(t, testDoc, string(doc))
+}
@@ -0,0 +1,116 @@
+// Package position genera claves de orden fraccionarias (fractional indexing).
+//
+// Cada clave representa la parte fraccionaria de un número en base 36 (0.xyz) y se compara
+// como texto. Entre dos claves siempre existe otra, por lo que mover un elemento solo
+// requiere actualizar su propia clave. Las claves nunca terminan en '0'.
+package position
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits es el alfabeto de las claves; su orden coincide con el orden de texto
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidRange indica que las claves dadas no están en orden o no son válidas
+var ErrInvalidRange = errors.New("las claves de posición no forman un rango válido")
+
+// KeyBetween devuelve una clave estrictamente entre a y b.
+// Una clave vacía representa el extremo: a="" es el inicio de la lista y b="" el final.
+func KeyBetween(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidRange
+	}
+	if b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+	return midpoint(a, b), nil
+}
+
+// Spread devuelve n claves ordenadas y distribuidas uniformemente, útiles para reequilibrar una lista
+func Spread(n int) []string {
+	keys := make([]string, n)
+	if n == 0 {
+		return keys
+	}
+
+	// Ancho mínimo para que quepan n+1 intervalos
+	width, capacity := 1, len(digits)
+	for capacity <= n {
+		width++
+		capacity *= len(digits)
+	}
+
+	step := capacity / (n + 1)
+	for i := range keys {
+		keys[i] = strings.TrimRight(encode((i+1)*step, width), "0")
+	}
+	return keys
+}
+
+// midpoint calcula la clave intermedia entre a y b (b vacío significa 1.0)
+func midpoint(a, b string) string {
+	if b != "" {
+		// Conservar el prefijo común
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(a[min(n, len(a)):], b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+// encode convierte un número a base 36 con el ancho indicado
+func encode(value, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[value%len(digits)]
+		value /= len(digits)
+	}
+	return string(buf)
+}
+
+// digitAt devuelve el dígito en la posición i, o '0' si la clave es más corta
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return '0'
+}
+
+// valid comprueba que la clave use el alfabeto y no termine en '0'
+func valid(key string) bool {
+	if strings.HasSuffix(key, "0") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
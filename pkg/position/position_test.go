@@ -0,0 +1,117 @@
+package position
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"lista vacía", "", "", "i"},
+		{"al inicio", "", "i", "9"},
+		{"al final", "i", "", "r"},
+		{"dígitos separados", "a", "c", "b"},
+		{"dígitos consecutivos", "a", "b", "ai"},
+		{"prefijo común", "ab", "ad", "ac"},
+		{"clave más corta antes", "a", "a5", "a3"},
+		{"antes de la primera clave posible", "", "1", "0i"},
+		{"después de la última clave posible", "z", "", "zi"},
+		{"después de varias z", "zz", "", "zzi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := KeyBetween(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+			assert.Greater(t, key, tt.a)
+			if tt.b != "" {
+				assert.Less(t, key, tt.b)
+			}
+			assert.False(t, strings.HasSuffix(key, "0"))
+		})
+	}
+}
+
+func TestKeyBetweenInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"claves iguales", "a", "a"},
+		{"orden invertido", "c", "b"},
+		{"termina en cero", "a0", ""},
+		{"fuera del alfabeto", "A", ""},
+		{"b inválida", "", "b_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := KeyBetween(tt.a, tt.b)
+			assert.ErrorIs(t, err, ErrInvalidRange)
+		})
+	}
+}
+
+// Insertar repetidamente en el mismo hueco siempre produce una clave válida y ordenada
+func TestKeyBetweenRepeated(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		next func(a, b, key string) (string, string)
+	}{
+		{"siempre al inicio", "", "i", func(a, b, key string) (string, string) { return "", key }},
+		{"siempre al final", "i", "", func(a, b, key string) (string, string) { return key, "" }},
+		{"siempre detrás de la primera", "a", "b", func(a, b, key string) (string, string) { return a, key }},
+		{"siempre delante de la última", "a", "b", func(a, b, key string) (string, string) { return key, b }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a, tt.b
+			for i := 0; i < 200; i++ {
+				key, err := KeyBetween(a, b)
+				require.NoError(t, err, "iteración %d entre %q y %q", i, a, b)
+				require.Greater(t, key, a)
+				if b != "" {
+					require.Less(t, key, b)
+				}
+				require.True(t, valid(key), key)
+				a, b = tt.next(a, b, key)
+			}
+		})
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		n     int
+		width int
+	}{
+		{0, 0},
+		{1, 1},
+		{35, 1},
+		{36, 2},
+		{500, 2},
+		{1296, 3},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.n), func(t *testing.T) {
+			keys := Spread(tt.n)
+			require.Len(t, keys, tt.n)
+			for i, key := range keys {
+				require.NotEmpty(t, key)
+				require.True(t, valid(key), key)
+				require.LessOrEqual(t, len(key), tt.width)
+				if i > 0 {
+					require.Less(t, keys[i-1], key)
+				}
+			}
+		})
+	}
+}
@@ -26,3 +26,12 @@ func ErrorResponse(c *gin.Context, statusCode int, message string) {
 		Error:   message,
 	})
 }
+
+// ErrorResponseWithData envía una respuesta de error con datos adicionales (p. ej. detalle por elemento)
+func ErrorResponseWithData(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+		Data:    data,
+	})
+}
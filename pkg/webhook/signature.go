@@ -0,0 +1,59 @@
+// Package webhook firma y verifica las entregas de webhooks con HMAC-SHA256.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encabezados enviados en cada entrega
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix identifica el algoritmo de la firma
+const signaturePrefix = "sha256="
+
+var (
+	ErrInvalidSignature = errors.New("la firma del webhook no es válida")
+	ErrExpiredTimestamp = errors.New("la marca de tiempo del webhook está fuera de la tolerancia")
+)
+
+// Sign calcula la firma de una entrega: HMAC-SHA256 del texto "<timestamp>.<cuerpo>" con el secreto del webhook
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify comprueba la firma de una entrega recibida. tolerance limita la antigüedad de la marca
+// de tiempo para rechazar reenvíos de entregas capturadas; con 0 no se comprueba.
+func Verify(secret, timestampHeader, signature string, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrExpiredTimestamp
+		}
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Firma calculada de forma independiente: HMAC-SHA256("secreto", "1700000000.<cuerpo>")
+const knownSignature = "sha256=9567185de54a286805a145077271ed9ca55c4ec4fa530f771e46a4c3af8ace65"
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, knownSignature, Sign("secreto", 1700000000, []byte(`{"event":"task.created"}`)))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"task.created"}`)
+	now := time.Now().Unix()
+	stamp := strconv.FormatInt(now, 10)
+	valid := Sign("secreto", now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		tolerance time.Duration
+		want      error
+	}{
+		{"firma válida", "secreto", stamp, valid, body, 5 * time.Minute, nil},
+		{"cuerpo vacío", "secreto", stamp, Sign("secreto", now, nil), nil, 5 * time.Minute, nil},
+		{"firma antigua sin tolerancia", "secreto", "1700000000", knownSignature, body, 0, nil},
+		{"secreto distinto", "otro", stamp, valid, body, 5 * time.Minute, ErrInvalidSignature},
+		{"cuerpo modificado", "secreto", stamp, valid, []byte(`{"event":"task.deleted"}`), 5 * time.Minute, ErrInvalidSignature},
+		{"marca de tiempo modificada", "secreto", strconv.FormatInt(now-1, 10), valid, body, 5 * time.Minute, ErrInvalidSignature},
+		{"sin prefijo", "secreto", stamp, strings.TrimPrefix(valid, "sha256="), body, 5 * time.Minute, ErrInvalidSignature},
+		{"otro algoritmo", "secreto", stamp, "sha1=" + strings.TrimPrefix(valid, "sha256="), body, 5 * time.Minute, ErrInvalidSignature},
+		{"hexadecimal en mayúsculas", "secreto", stamp, "sha256=" + strings.ToUpper(strings.TrimPrefix(valid, "sha256=")), body, 5 * time.Minute, ErrInvalidSignature},
+		{"firma truncada", "secreto", stamp, valid[:len(valid)-2], body, 5 * time.Minute, ErrInvalidSignature},
+		{"firma vacía", "secreto", stamp, "", body, 5 * time.Minute, ErrInvalidSignature},
+		{"marca de tiempo vacía", "secreto", "", valid, body, 5 * time.Minute, ErrInvalidSignature},
+		{"marca de tiempo no numérica", "secreto", "ayer", valid, body, 5 * time.Minute, ErrInvalidSignature},
+		{"marca de tiempo vencida", "secreto", "1700000000", knownSignature, body, 5 * time.Minute, ErrExpiredTimestamp},
+		{"marca de tiempo futura", "secreto", strconv.FormatInt(now+600, 10), Sign("secreto", now+600, body), body, 5 * time.Minute, ErrExpiredTimestamp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, tt.tolerance)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}